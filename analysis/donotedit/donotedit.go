@@ -0,0 +1,44 @@
+// Package donotedit defines an analyzer that reports manual edits to files
+// generated by genstruct.
+//
+// Files generated with genstruct.WithChecksum record a checksum of their
+// content on their last line. The analyzer recomputes it for every such file
+// in the analyzed packages and reports files that no longer match, so CI can
+// enforce regenerating data instead of editing generated code. Files without
+// a checksum are not checked.
+package donotedit
+
+import (
+	"os"
+	"strings"
+
+	"github.com/conneroisu/genstruct"
+	"golang.org/x/tools/go/analysis"
+)
+
+// generatedMarker identifies files generated by genstruct
+const generatedMarker = "// Code generated by genstruct. DO NOT EDIT."
+
+// Analyzer reports genstruct-generated files edited by hand.
+var Analyzer = &analysis.Analyzer{
+	Name: "donotedit",
+	Doc:  "report manual edits to files generated by genstruct with a checksum",
+	Run:  run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, file := range pass.Files {
+		name := pass.Fset.File(file.Pos()).Name()
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.Contains(string(src), generatedMarker) {
+			continue
+		}
+		if found, ok := genstruct.VerifyChecksum(src); found && !ok {
+			pass.Reportf(file.Package, "generated file was edited by hand; change the source data and regenerate it instead")
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,14 @@
+package donotedit_test
+
+import (
+	"testing"
+
+	"github.com/conneroisu/genstruct/analysis/donotedit"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// TestAnalyzer tests that only generated files whose checksum no longer
+// matches are reported
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), donotedit.Analyzer, "edited", "intact", "unchecked")
+}
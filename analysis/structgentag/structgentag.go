@@ -0,0 +1,241 @@
+// Package structgentag defines an analyzer that checks structgen struct tags.
+//
+// The generator only finds mistakes in structgen tags when it runs on data,
+// and some of them, like a misspelled source field, silently leave the target
+// field empty. The analyzer checks the tags statically instead:
+//
+//   - the tag parses, with known modifiers only
+//   - the source field exists on the struct
+//   - the source and target types form a supported reference, a key for a
+//     T or *T target, a slice of keys for a []T or []*T target and for a
+//     map[K]T or map[K]*T target with keys of the same kind, T being a struct
+//     and keys being strings, booleans or numbers, or a string or []string for
+//     an interface or interface slice target
+//   - the ptr modifier is only used on *T, []*T and map[K]*T targets
+//   - the typeField modifier is only used on interface targets and names a
+//     string field of the struct
+//   - the fields references are matched on exist on a target struct as
+//     strings, booleans or numbers, which are the match field if given and
+//     the identifier fields otherwise
+//
+// The identifier fields default to those of genstruct.NewGenerator and can be
+// changed with the -identifiers flag.
+package structgentag
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Analyzer reports invalid structgen tags.
+var Analyzer = &analysis.Analyzer{
+	Name: "structgentag",
+	Doc:  "check structgen struct tags against the fields and types they reference",
+	Run:  run,
+}
+
+// identifiers holds the -identifiers flag
+var identifiers = "ID,Name,Slug,Title,Key,Code"
+
+func init() {
+	Analyzer.Flags.StringVar(&identifiers, "identifiers", identifiers, "comma-separated identifier fields references are matched on without a match modifier")
+}
+
+// tag is a parsed structgen tag
+type tag struct {
+	source    string
+	ptr       bool
+	match     string
+	typeField string
+}
+
+// parseTag parses a structgen tag value, following the grammar of the
+// generator
+func parseTag(value string) (tag, string) {
+	parts := strings.Split(value, ",")
+	t := tag{source: strings.TrimSpace(parts[0])}
+	if t.source == "" {
+		return t, "missing source field"
+	}
+	for _, part := range parts[1:] {
+		modifier := strings.TrimSpace(part)
+		name, arg, hasArg := strings.Cut(modifier, "=")
+		switch name {
+		case "ptr", "strict", "omitempty":
+			if hasArg {
+				return t, "modifier " + name + " takes no value"
+			}
+			t.ptr = t.ptr || name == "ptr"
+		case "match", "typeField":
+			if arg == "" {
+				return t, "modifier " + name + " requires a field name"
+			}
+			if name == "match" {
+				t.match = arg
+			} else {
+				t.typeField = arg
+			}
+		default:
+			return t, "unknown modifier " + modifier
+		}
+	}
+	return t, ""
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			if structType, ok := node.(*ast.StructType); ok {
+				checkStruct(pass, structType)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// checkStruct checks the structgen tags of the fields of a struct type
+func checkStruct(pass *analysis.Pass, structType *ast.StructType) {
+	st, ok := pass.TypesInfo.TypeOf(structType).(*types.Struct)
+	if !ok {
+		return
+	}
+	for _, field := range structType.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		raw, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		value, ok := reflect.StructTag(raw).Lookup("structgen")
+		if !ok || value == "" {
+			continue
+		}
+
+		t, reason := parseTag(value)
+		if reason != "" {
+			pass.Reportf(field.Tag.Pos(), "invalid structgen tag %q: %s", value, reason)
+			continue
+		}
+		checkField(pass, field, st, t)
+	}
+}
+
+// checkField checks a parsed structgen tag against the struct holding the
+// field and the type of the field
+func checkField(pass *analysis.Pass, field *ast.Field, st *types.Struct, t tag) {
+	obj, _, _ := types.LookupFieldOrMethod(st, false, pass.Pkg, t.source)
+	source, ok := obj.(*types.Var)
+	if !ok || !source.IsField() {
+		pass.Reportf(field.Tag.Pos(), "structgen source field %s does not exist", t.source)
+		return
+	}
+
+	target := pass.TypesInfo.TypeOf(field.Type)
+	elem, isSlice, isMap := target, false, false
+	if slice, ok := target.Underlying().(*types.Slice); ok {
+		elem, isSlice = slice.Elem(), true
+	}
+	var mapKey types.Type
+	if m, ok := target.Underlying().(*types.Map); ok {
+		elem, isMap, mapKey = m.Elem(), true, m.Key()
+	}
+	ref, isPointer := elem, false
+	if pointer, ok := elem.Underlying().(*types.Pointer); ok {
+		ref, isPointer = pointer.Elem(), true
+	}
+	refStruct, isStruct := ref.Underlying().(*types.Struct)
+	_, isInterface := ref.Underlying().(*types.Interface)
+	if !isStruct && (!isInterface || isPointer || isMap) {
+		pass.Reportf(field.Tag.Pos(), "structgen target %s must be a struct, a struct pointer, an interface, a slice of them or a map of structs", types.TypeString(target, types.RelativeTo(pass.Pkg)))
+		return
+	}
+
+	// Interfaces are resolved by string keys only
+	isValidKey := isKey
+	if isInterface {
+		isValidKey = isString
+	}
+	sourceType := source.Type()
+	if isMap {
+		slice, ok := sourceType.Underlying().(*types.Slice)
+		if !ok || !isValidKey(slice.Elem()) {
+			pass.Reportf(field.Tag.Pos(), "structgen source field %s must be a slice of keys for a map target, got %s", t.source, types.TypeString(sourceType, types.RelativeTo(pass.Pkg)))
+			return
+		}
+		if !isKey(mapKey) || !types.Identical(mapKey.Underlying(), slice.Elem().Underlying()) {
+			pass.Reportf(field.Tag.Pos(), "structgen map target %s must have keys of the kind of %s", types.TypeString(target, types.RelativeTo(pass.Pkg)), types.TypeString(slice.Elem(), types.RelativeTo(pass.Pkg)))
+			return
+		}
+	} else if isSlice {
+		if slice, ok := sourceType.Underlying().(*types.Slice); !ok || !isValidKey(slice.Elem()) {
+			pass.Reportf(field.Tag.Pos(), "structgen source field %s must be a slice of keys for a slice target, got %s", t.source, types.TypeString(sourceType, types.RelativeTo(pass.Pkg)))
+			return
+		}
+	} else if !isValidKey(sourceType) {
+		pass.Reportf(field.Tag.Pos(), "structgen source field %s must be a key for a single target, got %s", t.source, types.TypeString(sourceType, types.RelativeTo(pass.Pkg)))
+		return
+	}
+	if t.ptr && !isPointer {
+		pass.Reportf(field.Tag.Pos(), "structgen modifier ptr requires a *T, []*T or map[K]*T field")
+	}
+
+	if t.typeField != "" && (!isInterface || isSlice || isMap) {
+		pass.Reportf(field.Tag.Pos(), "structgen modifier typeField requires an interface field")
+		return
+	}
+	if t.typeField != "" && !hasStringField(pass.Pkg, st, t.typeField) {
+		pass.Reportf(field.Tag.Pos(), "structgen type field %s is not a string field", t.typeField)
+		return
+	}
+
+	// The datasets an interface resolves against are only known when generating
+	if isInterface {
+		return
+	}
+	if t.match != "" {
+		if !hasKeyField(pass.Pkg, refStruct, t.match) {
+			pass.Reportf(field.Tag.Pos(), "structgen match field %s is not a key field of %s", t.match, types.TypeString(ref, types.RelativeTo(pass.Pkg)))
+		}
+		return
+	}
+	for _, name := range strings.Split(identifiers, ",") {
+		if hasKeyField(pass.Pkg, refStruct, strings.TrimSpace(name)) {
+			return
+		}
+	}
+	pass.Reportf(field.Tag.Pos(), "structgen target %s has none of the identifier fields %s; add one or use a match modifier", types.TypeString(ref, types.RelativeTo(pass.Pkg)), identifiers)
+}
+
+// hasStringField reports whether the struct has a string field named name
+func hasStringField(pkg *types.Package, st *types.Struct, name string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(st, false, pkg, name)
+	field, ok := obj.(*types.Var)
+	return ok && field.IsField() && isString(field.Type())
+}
+
+// hasKeyField reports whether the struct has a field named name that
+// references can be matched on
+func hasKeyField(pkg *types.Package, st *types.Struct, name string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(st, false, pkg, name)
+	field, ok := obj.(*types.Var)
+	return ok && field.IsField() && isKey(field.Type())
+}
+
+// isKey reports whether t has a string, boolean or numeric kind
+func isKey(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&(types.IsString|types.IsBoolean|types.IsNumeric) != 0
+}
+
+// isString reports whether t has the string kind
+func isString(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Kind() == types.String
+}
@@ -0,0 +1,14 @@
+package structgentag_test
+
+import (
+	"testing"
+
+	"github.com/conneroisu/genstruct/analysis/structgentag"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// TestAnalyzer tests that invalid structgen tags are reported and valid ones
+// are not
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), structgentag.Analyzer, "tags")
+}
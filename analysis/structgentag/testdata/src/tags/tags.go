@@ -0,0 +1,45 @@
+package tags
+
+type Slug string
+
+type Tag struct {
+	ID   string
+	Slug Slug
+}
+
+type Author struct {
+	Handle string
+	Age    int
+	Links  []string
+}
+
+type Item interface {
+	ItemTitle() string
+}
+
+type Post struct {
+	ID       string
+	TagSlugs []string
+	AuthorID string
+	Handle   string
+	Count    int
+	MainTag  string
+	Tags     []Tag           `structgen:"TagSlugs"`
+	TagPtrs  []*Tag          `structgen:"TagSlugs,ptr,match=Slug,strict"`
+	Tag      *Tag            `structgen:"MainTag,omitempty"`
+	Author   *Author         `structgen:"Handle,match=Handle"`
+	TagMap   map[string]*Tag `structgen:"TagSlugs,ptr"`
+	ByAge    *Author         `structgen:"Count,match=Age"`
+	ByID     map[int]Tag     `structgen:"TagSlugs"`           // want `structgen map target map\[int\]Tag must have keys of the kind of string`
+	MapOne   map[string]Tag  `structgen:"MainTag"`            // want `structgen source field MainTag must be a slice of keys for a map target, got string`
+	Missing  []Tag           `structgen:"TagSlug"`            // want `structgen source field TagSlug does not exist`
+	Scalar   Tag             `structgen:"TagSlugs"`           // want `structgen source field TagSlugs must be a key for a single target, got \[\]string`
+	Numbered []Tag           `structgen:"Count"`              // want `structgen source field Count must be a slice of keys for a slice target, got int`
+	Name     string          `structgen:"MainTag"`            // want `structgen target string must be a struct, a struct pointer, an interface, a slice of them or a map of structs`
+	Copied   Tag             `structgen:"MainTag,ptr"`        // want `structgen modifier ptr requires a \*T, \[\]\*T or map\[K\]\*T field`
+	ByLinks  *Author         `structgen:"Handle,match=Links"` // want `structgen match field Links is not a key field of Author`
+	Unnamed  *Author         `structgen:"Handle"`             // want `structgen target Author has none of the identifier fields`
+	Bad      Tag             `structgen:"MainTag,unique"`     // want `invalid structgen tag "MainTag,unique": unknown modifier unique`
+	NoMatch  Tag             `structgen:"MainTag,match="`     // want `invalid structgen tag "MainTag,match=": modifier match requires a field name`
+	Untagged Tag             `json:"untagged"`
+}
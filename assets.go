@@ -0,0 +1,145 @@
+package genstruct
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// defaultAssetHashLen is the number of hex characters of an asset hash used
+// when AssetHash.HashLen is not set
+const defaultAssetHashLen = 8
+
+// AssetHash describes a string field holding the path of a static asset whose
+// content is hashed at generation time, e.g. to build cache-busted URLs. At
+// least one of HashField and FingerprintField must be set.
+type AssetHash struct {
+	PathField        string // Field holding the asset path, e.g. "/css/site.css"
+	HashField        string // Field receiving the hex SHA-256 of the asset
+	FingerprintField string // Field receiving the path with the hash before the extension, e.g. "/css/site.3f2a9c1d.css"
+	Root             string // Directory asset paths are relative to, resolved against WorkingDir
+	HashLen          int    // Number of hex characters kept, defaults to 8
+}
+
+// withDefaults returns the asset hash with unset settings replaced by their
+// defaults
+func (a AssetHash) withDefaults() AssetHash {
+	if a.HashLen <= 0 || a.HashLen > sha256.Size*2 {
+		a.HashLen = defaultAssetHashLen
+	}
+	return a
+}
+
+// validateAssetHash checks that the fields of an asset hash exist on
+// structType and hold strings
+func validateAssetHash(asset AssetHash, structType reflect.Type) []error {
+	var errs []error
+	if asset.HashField == "" && asset.FingerprintField == "" {
+		errs = append(errs, ConfigError{Option: "AssetHash", Value: asset.PathField, Reason: "neither HashField nor FingerprintField given"})
+	}
+	fields := []struct{ option, name string }{
+		{"AssetHash.PathField", asset.PathField},
+		{"AssetHash.HashField", asset.HashField},
+		{"AssetHash.FingerprintField", asset.FingerprintField},
+	}
+	for i, f := range fields {
+		if f.name == "" && i > 0 {
+			continue
+		}
+		if field, ok := structType.FieldByName(f.name); !ok || field.Type.Kind() != reflect.String {
+			errs = append(errs, ConfigError{
+				Option: f.option,
+				Value:  f.name,
+				Reason: "not a string field of type " + structType.Name(),
+			})
+		}
+	}
+	return errs
+}
+
+// assetHashValue returns the generated value of field when it receives the
+// hash or fingerprinted path of an asset referenced by structValue
+func (g *Generator) assetHashValue(structValue reflect.Value, field reflect.StructField) (string, bool) {
+	for _, asset := range g.AssetHashes {
+		if field.Name != asset.HashField && field.Name != asset.FingerprintField {
+			continue
+		}
+		pathValue := structValue.FieldByName(asset.PathField)
+		if !pathValue.IsValid() || pathValue.Kind() != reflect.String {
+			continue
+		}
+		assetPath := pathValue.String()
+		if assetPath == "" {
+			return "", true
+		}
+
+		asset = asset.withDefaults()
+		sum, err := g.assetSum(asset, assetPath)
+		if err != nil {
+			g.addGenError(AssetHashError{Record: g.currentRecord, Path: assetPath, Err: err})
+			return "", true
+		}
+		sum = sum[:asset.HashLen]
+		if field.Name == asset.HashField {
+			return sum, true
+		}
+		return fingerprintPath(assetPath, sum), true
+	}
+	return "", false
+}
+
+// assetSum returns the hex SHA-256 of the asset at assetPath, reading each
+// file once per run
+func (g *Generator) assetSum(asset AssetHash, assetPath string) (string, error) {
+	file := g.resolvePath(filepath.Join(asset.Root, filepath.FromSlash(strings.TrimPrefix(assetPath, "/"))))
+	if sum, ok := g.assetSums[file]; ok {
+		return sum, nil
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(content)
+	sum := hex.EncodeToString(digest[:])
+	if g.assetSums == nil {
+		g.assetSums = make(map[string]string)
+	}
+	g.assetSums[file] = sum
+	return sum, nil
+}
+
+// assetSumsString returns the hashes of every asset referenced by the primary
+// dataset in record order, skipping assets that cannot be read
+func (g *Generator) assetSumsString() string {
+	dataValue := reflect.ValueOf(g.Data)
+	if len(g.AssetHashes) == 0 || (dataValue.Kind() != reflect.Slice && dataValue.Kind() != reflect.Array) {
+		return ""
+	}
+	var b strings.Builder
+	for _, elem := range g.unprunedRecords(dataValue) {
+		if elem.Kind() != reflect.Struct {
+			continue
+		}
+		for _, asset := range g.AssetHashes {
+			pathValue := elem.FieldByName(asset.PathField)
+			if !pathValue.IsValid() || pathValue.Kind() != reflect.String || pathValue.String() == "" {
+				continue
+			}
+			if sum, err := g.assetSum(asset, pathValue.String()); err == nil {
+				b.WriteString(sum)
+			}
+			b.WriteByte(';')
+		}
+	}
+	return b.String()
+}
+
+// fingerprintPath inserts hash before the extension of assetPath
+func fingerprintPath(assetPath, hash string) string {
+	ext := path.Ext(assetPath)
+	return strings.TrimSuffix(assetPath, ext) + "." + hash + ext
+}
@@ -0,0 +1,85 @@
+package genstruct
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAssetHash tests that asset contents are hashed into hash and
+// fingerprinted path fields and that missing assets are reported
+func TestAssetHash(t *testing.T) {
+	type Page struct {
+		ID         string
+		Stylesheet string
+		StyleHash  string
+		StyleURL   string
+	}
+
+	dir := t.TempDir()
+	css := []byte("body { color: red; }\n")
+	if err := os.MkdirAll(filepath.Join(dir, "static", "css"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "static", "css", "site.css"), css, 0644); err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256(css)
+	sum := hex.EncodeToString(digest[:])
+
+	asset := AssetHash{
+		PathField:        "Stylesheet",
+		HashField:        "StyleHash",
+		FingerprintField: "StyleURL",
+		Root:             "static",
+	}
+	pages := []Page{{ID: "home", Stylesheet: "/css/site.css"}, {ID: "plain"}}
+	err := NewGenerator(
+		WithPackageName("site"),
+		WithOutputFile("pages.go"),
+		WithWorkingDir(dir),
+		WithAssetHash(asset),
+	).Generate(pages)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "pages.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	output := string(content)
+	if !strings.Contains(output, `StyleHash:  "`+sum[:8]+`"`) {
+		t.Errorf("Expected hash field to hold %s, got:\n%s", sum[:8], output)
+	}
+	if !strings.Contains(output, `StyleURL:   "/css/site.`+sum[:8]+`.css"`) {
+		t.Errorf("Expected fingerprinted path, got:\n%s", output)
+	}
+
+	pages = []Page{{ID: "missing", Stylesheet: "/css/missing.css"}}
+	err = NewGenerator(
+		WithPackageName("site"),
+		WithOutputFile("missing.go"),
+		WithWorkingDir(dir),
+		WithAssetHash(asset),
+	).Generate(pages)
+	var assetErr AssetHashError
+	if !errors.As(err, &assetErr) || assetErr.Path != "/css/missing.css" {
+		t.Errorf("Expected AssetHashError for the missing asset, got %v", err)
+	}
+
+	err = NewGenerator(
+		WithPackageName("site"),
+		WithOutputFile("invalid.go"),
+		WithWorkingDir(dir),
+		WithAssetHash(AssetHash{PathField: "Stylesheet", HashField: "Missing"}),
+	).Generate(pages)
+	var configErr ConfigError
+	if !errors.As(err, &configErr) || configErr.Option != "AssetHash.HashField" {
+		t.Errorf("Expected ConfigError for the missing hash field, got %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+package genstruct
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// AttributionConfig describes the license and attribution metadata of records
+// built from third-party content.
+//
+// Each record's attribution is read from Field, a string or struct field. When
+// the field is empty and SidecarDir is set, it is decoded from the JSON file
+// named after the record identifier (e.g. lion-001.json) in that directory,
+// and the decoded value is written into the record literal.
+type AttributionConfig struct {
+	Field      string // Field holding the attribution, defaults to Attribution
+	SidecarDir string // Directory of JSON sidecar files, resolved against WorkingDir
+	NoticeFile string // Output file of the NOTICE-style text, none if empty
+}
+
+// withDefaults returns the configuration with unset settings replaced by their
+// defaults
+func (c AttributionConfig) withDefaults() AttributionConfig {
+	if c.Field == "" {
+		c.Field = "Attribution"
+	}
+	return c
+}
+
+// validateAttributions checks that the attribution field exists on structType
+// and holds a string or struct
+func validateAttributions(config AttributionConfig, structType reflect.Type) []error {
+	config = config.withDefaults()
+	field, ok := structType.FieldByName(config.Field)
+	if ok && (field.Type.Kind() == reflect.String || field.Type.Kind() == reflect.Struct) {
+		return nil
+	}
+	return []error{ConfigError{
+		Option: "Attributions.Field",
+		Value:  config.Field,
+		Reason: "not a string or struct field of type " + structType.Name(),
+	}}
+}
+
+// loadAttributionSidecars decodes the sidecar attribution files of the
+// records whose attribution field is empty, keyed by record variable name
+func (g *Generator) loadAttributionSidecars(dataValue reflect.Value) error {
+	g.sidecarAttributions = nil
+	config := g.Attributions.withDefaults()
+	if config.SidecarDir == "" {
+		return nil
+	}
+
+	for _, elem := range g.unprunedRecords(dataValue) {
+		field := elem.FieldByName(config.Field)
+		if !field.IsZero() {
+			continue
+		}
+		path := g.resolvePath(filepath.Join(config.SidecarDir, g.getStructIdentifier(elem)+".json"))
+		content, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		attribution := reflect.New(field.Type())
+		if err := json.Unmarshal(content, attribution.Interface()); err != nil {
+			return LoadError{Path: path, Err: err}
+		}
+		if g.sidecarAttributions == nil {
+			g.sidecarAttributions = make(map[string]reflect.Value)
+		}
+		g.sidecarAttributions[g.varName(elem)] = attribution.Elem()
+	}
+	return nil
+}
+
+// sidecarString returns the names and contents of the JSON files in the
+// sidecar directory, so that editing a sidecar invalidates the generation hash
+func (g *Generator) sidecarString() string {
+	if g.Attributions == nil || g.Attributions.SidecarDir == "" {
+		return ""
+	}
+	dir := g.resolvePath(g.Attributions.SidecarDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		b.WriteString(entry.Name())
+		b.Write(content)
+	}
+	return b.String()
+}
+
+// recordAttribution returns the attribution of a record, from its sidecar
+// file if it has one
+func (g *Generator) recordAttribution(elem reflect.Value, field string) reflect.Value {
+	if attribution, ok := g.sidecarAttributions[g.varName(elem)]; ok {
+		return attribution
+	}
+	return elem.FieldByName(field)
+}
+
+// attributionGroup is a distinct attribution and the records carrying it
+type attributionGroup struct {
+	value   reflect.Value
+	records []string
+}
+
+// attributionGroups collects the distinct non-empty attributions of the
+// dataset in record order
+func (g *Generator) attributionGroups(dataValue reflect.Value) []*attributionGroup {
+	config := g.Attributions.withDefaults()
+	var groups []*attributionGroup
+	byKey := make(map[string]*attributionGroup)
+	for _, elem := range g.unprunedRecords(dataValue) {
+		attribution := g.recordAttribution(elem, config.Field)
+		if attribution.IsZero() {
+			continue
+		}
+		key := canonicalString(attribution)
+		group, ok := byKey[key]
+		if !ok {
+			group = &attributionGroup{value: attribution}
+			byKey[key] = group
+			groups = append(groups, group)
+		}
+		group.records = append(group.records, g.getStructIdentifier(elem))
+	}
+	return groups
+}
+
+// generateAttributions declares a slice of the distinct attributions of the
+// dataset (e.g. AnimalAttributions)
+func (g *Generator) generateAttributions(dataValue reflect.Value) {
+	config := g.Attributions.withDefaults()
+	field, ok := g.dataStructType().FieldByName(config.Field)
+	if !ok {
+		return
+	}
+
+	name := g.safeName(g.TypeName + "Attributions")
+	g.File.Commentf("%s holds the distinct attributions of the %s values.", name, g.TypeName)
+	g.File.Var().Id(name).Op("=").Index().Add(g.getTypeStatement(field.Type)).ValuesFunc(func(group *jen.Group) {
+		for _, attribution := range g.attributionGroups(dataValue) {
+			group.Add(g.getValueStatement(attribution.value))
+		}
+	})
+}
+
+// writeNotice writes the NOTICE-style text listing every attribution and the
+// records carrying it
+func (g *Generator) writeNotice(dataValue reflect.Value) error {
+	config := g.Attributions.withDefaults()
+
+	// Make sure the notice stays inside the module like the generated code
+	ng := *g
+	ng.OutputFile = config.NoticeFile
+	if err := ng.validateOutputPath(); err != nil {
+		g.Logger.Error("Invalid notice output path", "error", err)
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s data includes third-party content:\n", g.plural(g.TypeName))
+	for _, group := range g.attributionGroups(dataValue) {
+		b.WriteString("\n")
+		b.WriteString(attributionText(group.value))
+		fmt.Fprintf(&b, "Used by: %s\n", strings.Join(group.records, ", "))
+	}
+
+	g.Logger.Debug(
+		"Writing notice to file",
+		slog.String("file", config.NoticeFile),
+	)
+	return ng.writeFile(ng.resolvePath(config.NoticeFile), []byte(b.String()))
+}
+
+// attributionText renders an attribution as lines of text: a string as is and
+// a struct as one "Field: value" line per non-empty field
+func attributionText(value reflect.Value) string {
+	if value.Kind() != reflect.Struct {
+		return strings.TrimRight(fmt.Sprint(value.Interface()), "\n") + "\n"
+	}
+	var b strings.Builder
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+		if !field.IsExported() || value.Field(i).IsZero() {
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %v\n", field.Name, value.Field(i).Interface())
+	}
+	return b.String()
+}
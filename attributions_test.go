@@ -0,0 +1,76 @@
+package genstruct
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAttributions tests that record and sidecar attributions are collected
+// into a distinct slice and a notice file
+func TestAttributions(t *testing.T) {
+	type Credit struct {
+		Author  string
+		License string
+	}
+	type Photo struct {
+		ID     string
+		Credit Credit
+	}
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "credits"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	sidecar := []byte(`{"Author": "Bea", "License": "CC0"}`)
+	if err := os.WriteFile(filepath.Join(dir, "credits", "photo-3.json"), sidecar, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	photos := []Photo{
+		{ID: "photo-1", Credit: Credit{Author: "Al", License: "CC-BY-4.0"}},
+		{ID: "photo-2", Credit: Credit{Author: "Al", License: "CC-BY-4.0"}},
+		{ID: "photo-3"},
+		{ID: "photo-4"},
+	}
+	err := NewGenerator(
+		WithPackageName("gallery"),
+		WithOutputFile("photos.go"),
+		WithWorkingDir(dir),
+		WithAttributions(AttributionConfig{
+			Field:      "Credit",
+			SidecarDir: "credits",
+			NoticeFile: "NOTICE",
+		}),
+	).Generate(photos)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "photos.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	output := string(content)
+	if !strings.Contains(output, "var PhotoAttributions = []Credit{") {
+		t.Errorf("Expected attributions slice, got:\n%s", output)
+	}
+	if strings.Count(output, `Author:  "Al"`) != 3 {
+		t.Errorf("Expected the shared attribution once in the slice, got:\n%s", output)
+	}
+	if strings.Count(output, `Author:  "Bea"`) != 2 {
+		t.Errorf("Expected the sidecar attribution in the record and the slice, got:\n%s", output)
+	}
+
+	notice, err := os.ReadFile(filepath.Join(dir, "NOTICE"))
+	if err != nil {
+		t.Fatalf("Error reading notice: %v", err)
+	}
+	want := "Photos data includes third-party content:\n\n" +
+		"Author: Al\nLicense: CC-BY-4.0\nUsed by: photo-1, photo-2\n\n" +
+		"Author: Bea\nLicense: CC0\nUsed by: photo-3\n"
+	if string(notice) != want {
+		t.Errorf("Unexpected notice:\n%s", notice)
+	}
+}
@@ -0,0 +1,116 @@
+package genstruct
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// writeCanonical writes a canonical, type-aware encoding of value to buf.
+//
+// Struct fields are written in name order and map entries in key order, so
+// the encoding does not depend on field declaration or map iteration order.
+// Unexported fields and fields listed in skipFields (top-level only) are
+// omitted. Pointer cycles are written as "cycle" instead of recursing.
+func writeCanonical(buf *bytes.Buffer, value reflect.Value, skipFields map[string]bool, seen map[uintptr]bool) {
+	if !value.IsValid() {
+		buf.WriteString("invalid")
+		return
+	}
+
+	buf.WriteString(value.Type().String())
+	buf.WriteByte('(')
+	defer buf.WriteByte(')')
+
+	switch value.Kind() {
+	case reflect.Bool:
+		buf.WriteString(strconv.FormatBool(value.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		buf.WriteString(strconv.FormatInt(value.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		buf.WriteString(strconv.FormatUint(value.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		buf.WriteString(strconv.FormatFloat(value.Float(), 'g', -1, 64))
+	case reflect.Complex64, reflect.Complex128:
+		buf.WriteString(strconv.FormatComplex(value.Complex(), 'g', -1, 128))
+	case reflect.String:
+		buf.WriteString(strconv.Quote(value.String()))
+	case reflect.Array, reflect.Slice:
+		for i := range value.Len() {
+			writeCanonical(buf, value.Index(i), nil, seen)
+			buf.WriteByte(',')
+		}
+	case reflect.Map:
+		entries := make([]string, 0, value.Len())
+		for _, key := range value.MapKeys() {
+			entry := &bytes.Buffer{}
+			writeCanonical(entry, key, nil, seen)
+			entry.WriteByte(':')
+			writeCanonical(entry, value.MapIndex(key), nil, seen)
+			entries = append(entries, entry.String())
+		}
+		sort.Strings(entries)
+		for _, entry := range entries {
+			buf.WriteString(entry)
+			buf.WriteByte(',')
+		}
+	case reflect.Struct:
+		if t, ok := value.Interface().(time.Time); ok {
+			// The location is kept, since time zone modes may render it
+			buf.WriteString(t.Format(time.RFC3339Nano) + " " + t.Location().String())
+			return
+		}
+		fields := make([]reflect.StructField, 0, value.NumField())
+		for i := range value.NumField() {
+			field := value.Type().Field(i)
+			if field.IsExported() && !skipFields[field.Name] {
+				fields = append(fields, field)
+			}
+		}
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+		for _, field := range fields {
+			buf.WriteString(field.Name)
+			buf.WriteByte(':')
+			writeCanonical(buf, value.FieldByIndex(field.Index), nil, seen)
+			buf.WriteByte(',')
+		}
+	case reflect.Pointer:
+		if value.IsNil() {
+			buf.WriteString("nil")
+			return
+		}
+		if seen[value.Pointer()] {
+			buf.WriteString("cycle")
+			return
+		}
+		seen[value.Pointer()] = true
+		writeCanonical(buf, value.Elem(), skipFields, seen)
+		delete(seen, value.Pointer())
+	case reflect.Interface:
+		if value.IsNil() {
+			buf.WriteString("nil")
+			return
+		}
+		writeCanonical(buf, value.Elem(), skipFields, seen)
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		// Addresses differ between runs, only record whether one is set
+		buf.WriteString(strconv.FormatBool(!value.IsNil()))
+	default:
+		fmt.Fprintf(buf, "%v", value.Interface())
+	}
+}
+
+// canonicalString returns the canonical encoding of value, omitting the
+// top-level struct fields listed in skipFields
+func canonicalString(value reflect.Value, skipFields ...string) string {
+	skip := make(map[string]bool, len(skipFields))
+	for _, name := range skipFields {
+		skip[name] = true
+	}
+	buf := &bytes.Buffer{}
+	writeCanonical(buf, value, skip, make(map[uintptr]bool))
+	return buf.String()
+}
@@ -0,0 +1,50 @@
+package genstruct
+
+import (
+	"reflect"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// getCharStatement returns a character literal for a rune or byte value when
+// CharLiterals is set and the character is printable or has a short escape
+// such as '\n', or nil otherwise. Bytes are converted to byte, since the
+// literal alone is an untyped rune.
+func (g *Generator) getCharStatement(value reflect.Value) *jen.Statement {
+	if !g.CharLiterals || value.Type().PkgPath() != "" {
+		return nil
+	}
+	var r rune
+	switch value.Kind() {
+	case reflect.Int32:
+		r = rune(value.Int())
+	case reflect.Uint8:
+		r = rune(value.Uint())
+		if r >= utf8.RuneSelf {
+			return nil
+		}
+	default:
+		return nil
+	}
+	if !utf8.ValidRune(r) || (!unicode.IsPrint(r) && !isShortEscape(r)) {
+		return nil
+	}
+
+	literal := jen.Op(strconv.QuoteRune(r))
+	if value.Kind() == reflect.Uint8 {
+		return jen.Byte().Call(literal)
+	}
+	return literal
+}
+
+// isShortEscape reports whether r has a single-letter escape sequence
+func isShortEscape(r rune) bool {
+	switch r {
+	case '\a', '\b', '\f', '\n', '\r', '\t', '\v':
+		return true
+	}
+	return false
+}
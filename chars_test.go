@@ -0,0 +1,40 @@
+package genstruct
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// TestCharLiterals tests rendering runes and bytes as character literals
+func TestCharLiterals(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{'A', "'A'"},
+		{'\n', "'\\n'"},
+		{'\'', "'\\''"},
+		{'é', "'é'"},
+		{rune(0), "int32(0)"},
+		{rune(-1), "int32(-1)"},
+		{rune(0xD800), "int32(55296)"},
+		{byte('x'), "byte('x')"},
+		{byte('\t'), "byte('\\t')"},
+		{byte(0xE9), "uint8(233)"},
+		{FuzzCount('A'), "genstruct.FuzzCount(65)"},
+		{[]rune("hi"), "[]int32{'h', 'i'}"},
+	}
+
+	g := NewGenerator(WithCharLiterals())
+	for _, tt := range tests {
+		got := fmt.Sprintf("%#v", g.getValueStatement(reflect.ValueOf(tt.value)))
+		if got != tt.want {
+			t.Errorf("getValueStatement(%T(%v)) = %q, want %q", tt.value, tt.value, got, tt.want)
+		}
+	}
+
+	if got := fmt.Sprintf("%#v", NewGenerator().getValueStatement(reflect.ValueOf('A'))); got != "int32(65)" {
+		t.Errorf("Expected runes to render as integers by default, got %q", got)
+	}
+}
@@ -0,0 +1,36 @@
+package genstruct
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// checksumCommentPrefix prefixes the last line of a generated file recording
+// the checksum of everything before it
+const checksumCommentPrefix = "// genstruct Checksum: "
+
+// appendChecksum appends the checksum line to the final generated source
+func appendChecksum(src []byte) []byte {
+	if len(src) > 0 && src[len(src)-1] != '\n' {
+		src = append(src, '\n')
+	}
+	sum := sha256.Sum256(src)
+	return append(src, []byte(checksumCommentPrefix+"sha256:"+hex.EncodeToString(sum[:])+"\n")...)
+}
+
+// VerifyChecksum checks a file generated with WithChecksum against the
+// checksum recorded on its last line. found reports whether the file records
+// a checksum at all, and ok whether the content still matches it, i.e. the
+// file wasn't edited since it was generated.
+func VerifyChecksum(src []byte) (found, ok bool) {
+	body := bytes.TrimRight(src, "\n")
+	start := bytes.LastIndexByte(body, '\n') + 1
+	line := body[start:]
+	recorded, found := bytes.CutPrefix(line, []byte(checksumCommentPrefix))
+	if !found {
+		return false, false
+	}
+	sum := sha256.Sum256(src[:start])
+	return true, string(recorded) == "sha256:"+hex.EncodeToString(sum[:])
+}
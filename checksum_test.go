@@ -0,0 +1,46 @@
+package genstruct
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestChecksum tests that generated files record a checksum that detects
+// manual edits
+func TestChecksum(t *testing.T) {
+	type Animal struct {
+		ID   string
+		Name string
+	}
+	animals := []Animal{{ID: "lion-001", Name: "Leo"}}
+
+	dir := t.TempDir()
+	err := NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile("animals.go"),
+		WithWorkingDir(dir),
+		WithChecksum(),
+	).Generate(animals)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, "animals.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	if found, ok := VerifyChecksum(src); !found || !ok {
+		t.Errorf("Expected an intact checksum, got found=%v ok=%v", found, ok)
+	}
+
+	edited := bytes.Replace(src, []byte(`"Leo"`), []byte(`"Leonard"`), 1)
+	if found, ok := VerifyChecksum(edited); !found || ok {
+		t.Errorf("Expected the edit to be detected, got found=%v ok=%v", found, ok)
+	}
+
+	if found, _ := VerifyChecksum([]byte("package zoo\n")); found {
+		t.Error("Expected no checksum in a file generated without one")
+	}
+}
@@ -0,0 +1,171 @@
+package genstruct
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// cloneTypes returns the struct types Clone methods are generated for: the
+// element types of the datasets followed by the struct types reachable from
+// their exported fields, in the order they are first reached
+func (g *Generator) cloneTypes() []reflect.Type {
+	var types []reflect.Type
+	var visit func(t reflect.Type)
+	visit = func(t reflect.Type) {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			visit(t.Elem())
+		case reflect.Struct:
+			if !g.hasCloneMethod(t) || slices.Contains(types, t) {
+				return
+			}
+			types = append(types, t)
+			for i := range t.NumField() {
+				if field := t.Field(i); field.IsExported() {
+					visit(field.Type)
+				}
+			}
+		}
+	}
+
+	for _, data := range append([]any{g.Data}, g.refDatasets()...) {
+		dataType := reflect.TypeOf(data)
+		if dataType != nil && (dataType.Kind() == reflect.Slice || dataType.Kind() == reflect.Array) {
+			visit(dataType.Elem())
+		}
+	}
+	return types
+}
+
+// hasCloneMethod reports whether a Clone method is generated for t, which
+// requires a named struct type declared in the generated package
+func (g *Generator) hasCloneMethod(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct &&
+		t.Name() != "" &&
+		!strings.Contains(t.Name(), "[") &&
+		t != timeType &&
+		g.isLocalType(t)
+}
+
+// needsDeepCopy reports whether copying a value of type t by assignment would
+// share memory with the original
+func (g *Generator) needsDeepCopy(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		return true
+	case reflect.Array:
+		return g.needsDeepCopy(t.Elem())
+	case reflect.Struct:
+		if !g.hasCloneMethod(t) {
+			return false
+		}
+		for i := range t.NumField() {
+			if field := t.Field(i); field.IsExported() && g.needsDeepCopy(field.Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generateCloneMethods generates a Clone method returning a deep copy for
+// each of the cloneTypes
+func (g *Generator) generateCloneMethods() {
+	for _, t := range g.cloneTypes() {
+		recv := cloneReceiver(t.Name())
+		g.declareFunc(
+			"Clone returns a deep copy of the "+t.Name()+", which can be modified without",
+			"changing the original.",
+		).Params(jen.Id(recv).Op("*").Id(t.Name())).Id("Clone").Params().Op("*").Id(t.Name()).BlockFunc(func(group *jen.Group) {
+			group.If(jen.Id(recv).Op("==").Nil()).Block(jen.Return(jen.Nil()))
+			group.Id("clone").Op(":=").Op("*").Id(recv)
+			for i := range t.NumField() {
+				field := t.Field(i)
+				if !field.IsExported() || !g.needsDeepCopy(field.Type) {
+					continue
+				}
+				g.cloneValue(group, jen.Id("clone").Dot(field.Name), jen.Id(recv).Dot(field.Name), field.Type, 0)
+			}
+			group.Return(jen.Op("&").Id("clone"))
+		})
+	}
+}
+
+// cloneValue emits the statements assigning a deep copy of src, a value of
+// type t, to dst. depth numbers the variables of nested loops.
+func (g *Generator) cloneValue(group *jen.Group, dst, src *jen.Statement, t reflect.Type, depth int) {
+	if !g.needsDeepCopy(t) {
+		group.Add(dst).Op("=").Add(src)
+		return
+	}
+
+	index, key, value := cloneVar("i", depth), cloneVar("k", depth), cloneVar("v", depth)
+	switch t.Kind() {
+	case reflect.Struct:
+		group.Add(dst).Op("=").Op("*").Add(src).Dot("Clone").Call()
+	case reflect.Pointer:
+		if g.hasCloneMethod(t.Elem()) {
+			group.Add(dst).Op("=").Add(src).Dot("Clone").Call()
+			return
+		}
+		group.If(jen.Add(src).Op("!=").Nil()).BlockFunc(func(block *jen.Group) {
+			block.Id(value).Op(":=").New(g.getTypeStatement(t.Elem()))
+			g.cloneValue(block, jen.Op("*").Id(value), jen.Op("*").Add(src), t.Elem(), depth+1)
+			block.Add(dst).Op("=").Id(value)
+		})
+	case reflect.Slice:
+		group.If(jen.Add(src).Op("!=").Nil()).BlockFunc(func(block *jen.Group) {
+			block.Add(dst).Op("=").Make(g.getTypeStatement(t), jen.Len(src))
+			if !g.needsDeepCopy(t.Elem()) {
+				block.Copy(dst, src)
+				return
+			}
+			block.For(jen.Id(index).Op(":=").Range().Add(src)).BlockFunc(func(loop *jen.Group) {
+				g.cloneValue(loop, jen.Add(dst).Index(jen.Id(index)), jen.Add(src).Index(jen.Id(index)), t.Elem(), depth+1)
+			})
+		})
+	case reflect.Array:
+		group.For(jen.Id(index).Op(":=").Range().Add(src)).BlockFunc(func(loop *jen.Group) {
+			g.cloneValue(loop, jen.Add(dst).Index(jen.Id(index)), jen.Add(src).Index(jen.Id(index)), t.Elem(), depth+1)
+		})
+	case reflect.Map:
+		group.If(jen.Add(src).Op("!=").Nil()).BlockFunc(func(block *jen.Group) {
+			block.Add(dst).Op("=").Make(g.getTypeStatement(t), jen.Len(src))
+			block.For(jen.List(jen.Id(key), jen.Id(value)).Op(":=").Range().Add(src)).BlockFunc(func(loop *jen.Group) {
+				// Elements of arrays held in a map can't be assigned in place
+				if t.Elem().Kind() == reflect.Array {
+					elem := cloneVar("c", depth)
+					loop.Var().Id(elem).Add(g.getTypeStatement(t.Elem()))
+					g.cloneValue(loop, jen.Id(elem), jen.Id(value), t.Elem(), depth+1)
+					loop.Add(dst).Index(jen.Id(key)).Op("=").Id(elem)
+					return
+				}
+				g.cloneValue(loop, jen.Add(dst).Index(jen.Id(key)), jen.Id(value), t.Elem(), depth+1)
+			})
+		})
+	}
+}
+
+// cloneVar returns the name of a variable of a generated Clone method, numbered
+// after the first nesting level
+func cloneVar(name string, depth int) string {
+	if depth == 0 {
+		return name
+	}
+	return name + strconv.Itoa(depth)
+}
+
+// cloneReceiver returns the receiver name of the Clone method of a type, its
+// lowercased initial unless that is taken by a variable of the method
+func cloneReceiver(typeName string) string {
+	recv := string(unicode.ToLower([]rune(typeName)[0]))
+	if slices.Contains([]string{"c", "i", "k", "v", "_"}, recv) {
+		return "r"
+	}
+	return recv
+}
@@ -0,0 +1,145 @@
+package genstruct
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// CloneDetail is a nested type reached from CloneRecord
+type CloneDetail struct {
+	Notes []string
+}
+
+// CloneRecord exercises the deep copy of slices, maps, and nested pointers
+type CloneRecord struct {
+	ID      string
+	Labels  []string
+	Scores  map[string][]int
+	Detail  *CloneDetail
+	Details []CloneDetail
+	Grid    map[string][2][]string
+}
+
+// TestCloneMethods tests that the generated Clone methods deep copy the
+// records so that modifying a clone leaves the generated record unchanged
+func TestCloneMethods(t *testing.T) {
+	records := []CloneRecord{{
+		ID:      "rec-1",
+		Labels:  []string{"a", "b"},
+		Scores:  map[string][]int{"x": {1, 2}},
+		Detail:  &CloneDetail{Notes: []string{"first"}},
+		Details: []CloneDetail{{Notes: []string{"second"}}},
+		Grid:    map[string][2][]string{"g": {{"top"}, {"bottom"}}},
+	}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("main"),
+		WithOutputFile("records.go"),
+		WithWorkingDir(dir),
+		WithCloneMethods(),
+	)
+	if err := generator.Generate(records); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "records.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	output := string(content)
+
+	expected := []string{
+		"func (r *CloneRecord) Clone() *CloneRecord {",
+		"clone := *r",
+		"copy(clone.Labels, r.Labels)",
+		"clone.Detail = r.Detail.Clone()",
+		"clone.Details[i] = *r.Details[i].Clone()",
+		"clone.Scores[k] = make([]int, len(v))",
+		"var c [2][]string",
+		"func (r *CloneDetail) Clone() *CloneDetail {",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q", exp)
+		}
+	}
+	if t.Failed() {
+		t.Logf("Generated output:\n%s", output)
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+	files := map[string]string{
+		"go.mod": "module clone\n\ngo 1.24\n",
+		"types.go": `package main
+
+type CloneDetail struct {
+	Notes []string
+}
+
+type CloneRecord struct {
+	ID      string
+	Labels  []string
+	Scores  map[string][]int
+	Detail  *CloneDetail
+	Details []CloneDetail
+	Grid    map[string][2][]string
+}
+`,
+		"main.go": `package main
+
+func main() {
+	clone := CloneRecordRec1.Clone()
+	clone.Labels[0] = "changed"
+	clone.Scores["x"][0] = 100
+	clone.Detail.Notes[0] = "changed"
+	clone.Details[0].Notes[0] = "changed"
+	clone.Grid["g"][0][0] = "changed"
+
+	original := CloneRecordRec1
+	if original.Labels[0] != "a" || original.Scores["x"][0] != 1 ||
+		original.Detail.Notes[0] != "first" || original.Details[0].Notes[0] != "second" ||
+		original.Grid["g"][0][0] != "top" {
+		panic("modifying the clone changed the original")
+	}
+	if (*CloneRecord)(nil).Clone() != nil {
+		panic("clone of nil is not nil")
+	}
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Error writing %s: %v", name, err)
+		}
+	}
+
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Generated program failed: %v\n%s", err, output)
+	}
+}
+
+// TestCloneMethodsForeignType tests that Clone methods are rejected for a
+// type declared outside the generated package
+func TestCloneMethodsForeignType(t *testing.T) {
+	records := []CloneRecord{{ID: "rec-1"}}
+
+	err := NewGenerator(
+		WithPackageName("records"),
+		WithOutputFile("records/records.go"),
+		WithWorkingDir(t.TempDir()),
+		WithCloneMethods(),
+	).Generate(records)
+	if err == nil || !strings.Contains(err.Error(), "CloneMethods") {
+		t.Fatalf("Expected a CloneMethods configuration error, got %v", err)
+	}
+}
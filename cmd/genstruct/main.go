@@ -0,0 +1,378 @@
+// Command genstruct generates static Go code from JSON or CSV data files
+// without a hand-written generator program, e.g. from a go:generate directive:
+//
+//	//go:generate go run github.com/conneroisu/genstruct/cmd/genstruct -type example.com/blog/content.Post -data posts.json -ref example.com/blog/content.Tag=tags.json -output posts_generated.go
+//
+// Settings may also be read from a JSON config file with -config, with flags
+// taking precedence:
+//
+//	{
+//	  "type": "example.com/blog/content.Post",
+//	  "data": "posts.json",
+//	  "refs": [{"type": "example.com/blog/content.Tag", "data": "tags.json"}],
+//	  "package": "blog",
+//	  "output": "posts_generated.go",
+//	  "identifierFields": ["Slug", "ID"],
+//	  "pruned": ["PostDraft"]
+//	}
+//
+// The data types must be importable from the module in the current directory,
+// so types declared in a main package can't be used. Paths are relative to
+// the current directory.
+//
+// The prune subcommand prints the records of a generated file that the module
+// in the current directory never references, one variable name per line, to
+// be left out of the next run with -pruned or the "pruned" setting:
+//
+//	genstruct prune posts_generated.go
+//
+// The explain subcommand takes the same flags as a generation run followed by
+// a structgen field as Type.Field, and prints how each of its reference keys
+// resolves instead of generating code:
+//
+//	genstruct explain -config genstruct.json Post.Tags
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/conneroisu/genstruct"
+	"github.com/dave/jennifer/jen"
+)
+
+// genstructPath is the import path of the genstruct package
+const genstructPath = "github.com/conneroisu/genstruct"
+
+// dataset is a data file and the qualified struct type its records decode to
+type dataset struct {
+	Type string `json:"type"` // Import path and name of the struct type, e.g. example.com/blog.Post
+	Data string `json:"data"` // Path of the JSON file holding an array of records, or of a CSV file
+}
+
+// config holds the settings of a generation run
+type config struct {
+	dataset
+	Refs             []dataset `json:"refs"`
+	PackageName      string    `json:"package"`
+	OutputFile       string    `json:"output"`
+	TypeName         string    `json:"typeName"`
+	ConstantIdent    string    `json:"constantIdent"`
+	VarPrefix        string    `json:"varPrefix"`
+	IdentifierFields []string  `json:"identifierFields"`
+	Pruned           []string  `json:"pruned"`
+	DryRun           bool      `json:"dryRun"`
+	Check            bool      `json:"check"`
+	Explain          string    `json:"-"` // structgen field to explain instead of generating, e.g. Post.Tags
+
+	args []string // Arguments left after the flags
+}
+
+// refFlag collects the repeatable -ref flag
+type refFlag []dataset
+
+// String returns the flag value
+func (r *refFlag) String() string {
+	refs := make([]string, 0, len(*r))
+	for _, ref := range *r {
+		refs = append(refs, ref.Type+"="+ref.Data)
+	}
+	return strings.Join(refs, ",")
+}
+
+// Set adds a reference dataset given as type=file
+func (r *refFlag) Set(value string) error {
+	typ, data, ok := strings.Cut(value, "=")
+	if !ok || typ == "" || data == "" {
+		return fmt.Errorf("reference %q must have the form import/path.Type=file.json", value)
+	}
+	*r = append(*r, dataset{Type: typ, Data: data})
+	return nil
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "genstruct:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the arguments and runs the generation program or subcommand
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) > 0 && args[0] == "prune" {
+		return runPrune(args[1:], stdout, stderr)
+	}
+	explain := len(args) > 0 && args[0] == "explain"
+	if explain {
+		args = args[1:]
+	}
+	cfg, err := parseArgs(args, stderr)
+	if err != nil {
+		return err
+	}
+	switch {
+	case explain && len(cfg.args) != 1:
+		return errors.New("a structgen field is required, e.g. genstruct explain -config genstruct.json Post.Tags")
+	case explain:
+		cfg.Explain = cfg.args[0]
+	case len(cfg.args) > 0:
+		return fmt.Errorf("unexpected arguments %q", cfg.args)
+	}
+	src, err := renderProgram(cfg)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "genstruct-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	program := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(program, src, 0644); err != nil {
+		return err
+	}
+
+	// Running the program by file resolves imports against the module in the
+	// current directory
+	cmd := exec.Command("go", "run", program)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// runPrune prints the records of the generated file never referenced by the
+// module in the root directory
+func runPrune(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("genstruct prune", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	root := fs.String("root", ".", "root directory of the module using the generated records")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("a generated file is required, e.g. genstruct prune posts_generated.go")
+	}
+
+	unused, err := genstruct.FindUnusedRecords(*root, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	for _, name := range unused {
+		fmt.Fprintln(stdout, name)
+	}
+	return nil
+}
+
+// parseArgs reads the config file, if any, and applies the flags over it
+func parseArgs(args []string, stderr io.Writer) (config, error) {
+	var (
+		cfg              config
+		refs             refFlag
+		configFile       string
+		identifierFields string
+		pruned           string
+		dryRun           bool
+		check            bool
+	)
+	fs := flag.NewFlagSet("genstruct", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&configFile, "config", "", "JSON config file")
+	fs.StringVar(&cfg.Type, "type", "", "qualified struct type of the records, e.g. example.com/blog.Post")
+	fs.StringVar(&cfg.Data, "data", "", "JSON file holding an array of records, or CSV file")
+	fs.Var(&refs, "ref", "reference dataset as import/path.Type=file.json, may be repeated")
+	fs.StringVar(&cfg.PackageName, "package", "", "package name of the generated code")
+	fs.StringVar(&cfg.OutputFile, "output", "", "output file of the generated code")
+	fs.StringVar(&cfg.TypeName, "type-name", "", "type name used in the generated code")
+	fs.StringVar(&cfg.ConstantIdent, "const-ident", "", "prefix of the generated constants")
+	fs.StringVar(&cfg.VarPrefix, "var-prefix", "", "prefix of the generated variables")
+	fs.StringVar(&identifierFields, "identifier-fields", "", "comma-separated fields used to name records")
+	fs.StringVar(&pruned, "pruned", "", "comma-separated variables of records left out, as printed by genstruct prune")
+	fs.BoolVar(&dryRun, "dry-run", false, "print a diff of the changes instead of writing files")
+	fs.BoolVar(&check, "check", false, "fail with a diff if the generated files are stale, without writing them")
+	if err := fs.Parse(args); err != nil {
+		return config{}, err
+	}
+
+	if configFile != "" {
+		fileCfg, err := readConfig(configFile)
+		if err != nil {
+			return config{}, err
+		}
+		cfg = mergeConfig(fileCfg, cfg)
+	}
+	cfg.Refs = append(cfg.Refs, refs...)
+	if identifierFields != "" {
+		cfg.IdentifierFields = strings.Split(identifierFields, ",")
+	}
+	if pruned != "" {
+		cfg.Pruned = strings.Split(pruned, ",")
+	}
+	cfg.DryRun = cfg.DryRun || dryRun
+	cfg.Check = cfg.Check || check
+	cfg.args = fs.Args()
+
+	if cfg.Type == "" || cfg.Data == "" {
+		return config{}, errors.New("a record type and data file are required, use -type and -data or a config file")
+	}
+	return cfg, nil
+}
+
+// readConfig reads a JSON config file
+func readConfig(path string) (config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return config{}, err
+	}
+	var cfg config
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		return config{}, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// mergeConfig returns base with the values set in override replacing its own
+func mergeConfig(base, override config) config {
+	for _, field := range []struct{ dst, src *string }{
+		{&base.Type, &override.Type},
+		{&base.Data, &override.Data},
+		{&base.PackageName, &override.PackageName},
+		{&base.OutputFile, &override.OutputFile},
+		{&base.TypeName, &override.TypeName},
+		{&base.ConstantIdent, &override.ConstantIdent},
+		{&base.VarPrefix, &override.VarPrefix},
+	} {
+		if *field.src != "" {
+			*field.dst = *field.src
+		}
+	}
+	base.Refs = append(base.Refs, override.Refs...)
+	if len(override.IdentifierFields) > 0 {
+		base.IdentifierFields = override.IdentifierFields
+	}
+	if len(override.Pruned) > 0 {
+		base.Pruned = override.Pruned
+	}
+	return base
+}
+
+// splitType splits a qualified type such as example.com/blog.Post into its
+// import path and name
+func splitType(qualified string) (string, string, error) {
+	i := strings.LastIndex(qualified, ".")
+	if i <= strings.LastIndex(qualified, "/") || i == len(qualified)-1 {
+		return "", "", fmt.Errorf("type %q must have the form import/path.Type", qualified)
+	}
+	return qualified[:i], qualified[i+1:], nil
+}
+
+// renderProgram renders the program running the generator with the
+// configured options on the datasets, read as CSV for .csv files and as JSON
+// otherwise
+func renderProgram(cfg config) ([]byte, error) {
+	file := jen.NewFile("main")
+	file.HeaderComment("Code generated by genstruct for a single run. DO NOT EDIT.")
+
+	var datasets []jen.Code
+	for _, ds := range append([]dataset{cfg.dataset}, cfg.Refs...) {
+		pkgPath, typeName, err := splitType(ds.Type)
+		if err != nil {
+			return nil, err
+		}
+		source := "JSONFile"
+		if strings.EqualFold(filepath.Ext(ds.Data), ".csv") {
+			source = "CSVFile"
+		}
+		datasets = append(datasets, jen.Qual(genstructPath, source).Types(jen.Qual(pkgPath, typeName)).Call(jen.Lit(ds.Data)))
+	}
+
+	var opts []jen.Code
+	for _, opt := range []struct{ name, value string }{
+		{"WithPackageName", cfg.PackageName},
+		{"WithOutputFile", cfg.OutputFile},
+		{"WithTypeName", cfg.TypeName},
+		{"WithConstantIdent", cfg.ConstantIdent},
+		{"WithVarPrefix", cfg.VarPrefix},
+	} {
+		if opt.value != "" {
+			opts = append(opts, jen.Qual(genstructPath, opt.name).Call(jen.Lit(opt.value)))
+		}
+	}
+	if len(cfg.IdentifierFields) > 0 {
+		opts = append(opts, jen.Qual(genstructPath, "WithIdentifierFields").Call(
+			jen.Index().String().ValuesFunc(func(group *jen.Group) {
+				for _, field := range cfg.IdentifierFields {
+					group.Lit(strings.TrimSpace(field))
+				}
+			}),
+		))
+	}
+
+	if len(cfg.Pruned) > 0 {
+		opts = append(opts, jen.Qual(genstructPath, "WithPrunedRecords").Call(
+			jen.Index().String().ValuesFunc(func(group *jen.Group) {
+				for _, name := range cfg.Pruned {
+					group.Lit(strings.TrimSpace(name))
+				}
+			}),
+		))
+	}
+
+	if cfg.DryRun {
+		opts = append(opts, jen.Qual(genstructPath, "WithDryRun").Call())
+	}
+
+	file.Func().Id("main").Params().BlockFunc(func(group *jen.Group) {
+		group.Id("generator").Op(":=").Qual(genstructPath, "NewGenerator").Call(opts...)
+		if cfg.Explain != "" {
+			group.List(jen.Id("resolutions"), jen.Err()).Op(":=").Id("generator").Dot("Explain").Call(
+				append([]jen.Code{jen.Lit(cfg.Explain)}, datasets...)...,
+			)
+			group.If(jen.Err().Op("!=").Nil()).Block(jen.Id("fail").Call(jen.Err()))
+			group.If(
+				jen.Err().Op(":=").Qual(genstructPath, "WriteExplanation").Call(jen.Qual("os", "Stdout"), jen.Id("resolutions")),
+				jen.Err().Op("!=").Nil(),
+			).Block(jen.Id("fail").Call(jen.Err()))
+			return
+		}
+		if cfg.Check {
+			group.If(
+				jen.Err().Op(":=").Id("generator").Dot("Verify").Call(datasets...),
+				jen.Err().Op("!=").Nil(),
+			).Block(
+				jen.Var().Id("stale").Qual(genstructPath, "StaleOutputError"),
+				jen.If(jen.Qual("errors", "As").Call(jen.Err(), jen.Op("&").Id("stale"))).Block(
+					jen.Qual("fmt", "Print").Call(jen.Id("stale").Dot("Diff")),
+				),
+				jen.Id("fail").Call(jen.Err()),
+			)
+			return
+		}
+		group.If(
+			jen.Err().Op(":=").Id("generator").Dot("Generate").Call(datasets...),
+			jen.Err().Op("!=").Nil(),
+		).Block(jen.Id("fail").Call(jen.Err()))
+		if cfg.DryRun {
+			group.Qual("fmt", "Print").Call(jen.Id("generator").Dot("Diff"))
+		}
+	})
+
+	// fail reports an error and exits
+	file.Func().Id("fail").Params(jen.Err().Error()).Block(
+		jen.Qual("fmt", "Fprintln").Call(jen.Qual("os", "Stderr"), jen.Lit("genstruct:"), jen.Err()),
+		jen.Qual("os", "Exit").Call(jen.Lit(1)),
+	)
+
+	buf := &strings.Builder{}
+	if err := file.Render(buf); err != nil {
+		return nil, err
+	}
+	return []byte(buf.String()), nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestParseArgs tests that flags override the config file
+func TestParseArgs(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "genstruct.json")
+	content := `{
+		"type": "example.com/blog.Post",
+		"data": "posts.json",
+		"refs": [{"type": "example.com/blog.Tag", "data": "tags.json"}],
+		"package": "blog",
+		"output": "posts.go",
+		"identifierFields": ["Slug"]
+	}`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := parseArgs([]string{
+		"-config", configFile,
+		"-output", "generated.go",
+		"-ref", "example.com/blog.Author=authors.json",
+		"-identifier-fields", "ID,Name",
+		"-pruned", "PostDraft,PostOld",
+	}, io.Discard)
+	if err != nil {
+		t.Fatalf("Error parsing arguments: %v", err)
+	}
+	if cfg.Type != "example.com/blog.Post" || cfg.PackageName != "blog" {
+		t.Errorf("Expected config file values to be kept, got %+v", cfg)
+	}
+	if cfg.OutputFile != "generated.go" {
+		t.Errorf("Expected -output to override the config file, got %s", cfg.OutputFile)
+	}
+	if len(cfg.Refs) != 2 || cfg.Refs[1].Type != "example.com/blog.Author" {
+		t.Errorf("Expected both reference datasets, got %+v", cfg.Refs)
+	}
+	if !slices.Equal(cfg.IdentifierFields, []string{"ID", "Name"}) {
+		t.Errorf("Expected -identifier-fields to override the config file, got %v", cfg.IdentifierFields)
+	}
+	if !slices.Equal(cfg.Pruned, []string{"PostDraft", "PostOld"}) {
+		t.Errorf("Expected -pruned to set the pruned records, got %v", cfg.Pruned)
+	}
+
+	if _, err := parseArgs([]string{"-data", "posts.json"}, io.Discard); err == nil {
+		t.Error("Expected an error without a record type")
+	}
+	if _, err := parseArgs([]string{"-ref", "posts.json"}, io.Discard); err == nil {
+		t.Error("Expected an error for a malformed -ref")
+	}
+}
+
+// TestRenderProgram tests that the generation program is valid Go calling
+// the generator with the configured options
+func TestRenderProgram(t *testing.T) {
+	src, err := renderProgram(config{
+		dataset:          dataset{Type: "example.com/blog/content.Post", Data: "posts.json"},
+		Refs:             []dataset{{Type: "example.com/blog/content.Tag", Data: "tags.csv"}},
+		PackageName:      "blog",
+		OutputFile:       "posts_generated.go",
+		IdentifierFields: []string{"Slug"},
+		Pruned:           []string{"PostDraft"},
+		DryRun:           true,
+	})
+	if err != nil {
+		t.Fatalf("Error rendering program: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "main.go", src, 0); err != nil {
+		t.Fatalf("Rendered program is not valid Go: %v\n%s", err, src)
+	}
+
+	program := string(src)
+	for _, want := range []string{
+		`genstruct.JSONFile[content.Post]("posts.json")`,
+		`genstruct.CSVFile[content.Tag]("tags.csv")`,
+		`genstruct.WithPackageName("blog")`,
+		`genstruct.WithOutputFile("posts_generated.go")`,
+		`genstruct.WithIdentifierFields([]string{"Slug"})`,
+		`genstruct.WithPrunedRecords([]string{"PostDraft"})`,
+		`genstruct.WithDryRun()`,
+		`fmt.Print(generator.Diff)`,
+	} {
+		if !strings.Contains(program, want) {
+			t.Errorf("Expected program to contain %q, got:\n%s", want, program)
+		}
+	}
+
+	// Check mode verifies instead of generating
+	src, err = renderProgram(config{
+		dataset: dataset{Type: "example.com/blog/content.Post", Data: "posts.json"},
+		Check:   true,
+	})
+	if err != nil {
+		t.Fatalf("Error rendering program: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "main.go", src, 0); err != nil {
+		t.Fatalf("Rendered program is not valid Go: %v\n%s", err, src)
+	}
+	program = string(src)
+	for _, want := range []string{
+		`generator.Verify(genstruct.JSONFile[content.Post]("posts.json"))`,
+		`errors.As(err, &stale)`,
+		`fmt.Print(stale.Diff)`,
+	} {
+		if !strings.Contains(program, want) {
+			t.Errorf("Expected program to contain %q, got:\n%s", want, program)
+		}
+	}
+	if strings.Contains(program, "generator.Generate(") {
+		t.Errorf("Expected check mode not to generate, got:\n%s", program)
+	}
+
+	// Explain mode prints the resolutions instead of generating
+	src, err = renderProgram(config{
+		dataset: dataset{Type: "example.com/blog/content.Post", Data: "posts.json"},
+		Refs:    []dataset{{Type: "example.com/blog/content.Tag", Data: "tags.json"}},
+		Explain: "Post.Tags",
+	})
+	if err != nil {
+		t.Fatalf("Error rendering program: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "main.go", src, 0); err != nil {
+		t.Fatalf("Rendered program is not valid Go: %v\n%s", err, src)
+	}
+	program = string(src)
+	for _, want := range []string{
+		`generator.Explain("Post.Tags", genstruct.JSONFile[content.Post]("posts.json"), genstruct.JSONFile[content.Tag]("tags.json"))`,
+		`genstruct.WriteExplanation(os.Stdout, resolutions)`,
+	} {
+		if !strings.Contains(program, want) {
+			t.Errorf("Expected program to contain %q, got:\n%s", want, program)
+		}
+	}
+	if strings.Contains(program, "generator.Generate(") {
+		t.Errorf("Expected explain mode not to generate, got:\n%s", program)
+	}
+
+	if _, err := renderProgram(config{dataset: dataset{Type: "Post", Data: "posts.json"}}); err == nil {
+		t.Error("Expected an error for an unqualified type")
+	}
+}
+
+// TestRunPrune tests that the prune subcommand prints the records the module
+// never references
+func TestRunPrune(t *testing.T) {
+	dir := t.TempDir()
+	generated := "// Code generated by genstruct. DO NOT EDIT.\n\npackage zoo\n\n" +
+		"var AnimalLion = Animal{Name: \"Leo\"}\nvar AnimalBear = Animal{Name: \"Bruno\"}\n" +
+		"var AllAnimals = []*Animal{&AnimalLion, &AnimalBear}\n"
+	consumer := "package zoo\n\nvar favorite = AnimalLion\n"
+	for name, content := range map[string]string{"animals.go": generated, "consumer.go": consumer} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stdout := &strings.Builder{}
+	if err := run([]string{"prune", "-root", dir, filepath.Join(dir, "animals.go")}, stdout, io.Discard); err != nil {
+		t.Fatalf("Error running prune: %v", err)
+	}
+	if stdout.String() != "AnimalBear\n" {
+		t.Errorf("Expected AnimalBear to be reported, got %q", stdout.String())
+	}
+
+	if err := run([]string{"prune"}, io.Discard, io.Discard); err == nil {
+		t.Error("Expected an error without a generated file")
+	}
+}
+
+// TestRunArgs tests that explain requires a single field and that generation
+// runs take no positional arguments
+func TestRunArgs(t *testing.T) {
+	flags := []string{"-type", "example.com/blog.Post", "-data", "posts.json"}
+	for _, args := range [][]string{
+		append([]string{"explain"}, flags...),
+		append(append([]string{"explain"}, flags...), "Post.Tags", "Post.Author"),
+		append(flags, "Post.Tags"),
+	} {
+		if err := run(args, io.Discard, io.Discard); err == nil {
+			t.Errorf("Expected an error for arguments %q", args)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+// Command genstructvet runs the genstruct analyzers, which report generated
+// files edited by hand and invalid structgen tags, e.g. in CI:
+//
+//	go run github.com/conneroisu/genstruct/cmd/genstructvet ./...
+package main
+
+import (
+	"github.com/conneroisu/genstruct/analysis/donotedit"
+	"github.com/conneroisu/genstruct/analysis/structgentag"
+	"golang.org/x/tools/go/analysis/multichecker"
+)
+
+func main() {
+	multichecker.Main(donotedit.Analyzer, structgentag.Analyzer)
+}
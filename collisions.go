@@ -0,0 +1,97 @@
+package genstruct
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"strconv"
+)
+
+// CollisionPolicy decides how records whose identifiers produce the same
+// generated name as an earlier record of their dataset are named.
+type CollisionPolicy string
+
+const (
+	// CollisionError fails generation with a DuplicateNameError, or logs a
+	// warning with WithDuplicateNameWarnings. This is the default.
+	CollisionError CollisionPolicy = "error"
+	// CollisionSuffix appends the lowest number from 2 up that makes the name
+	// unique (e.g. TagGo and TagGo2).
+	CollisionSuffix CollisionPolicy = "suffix"
+	// CollisionHash appends a short hash of the record's identifier (e.g.
+	// TagGo and TagGo9f86d0), which doesn't change when records are reordered.
+	CollisionHash CollisionPolicy = "hash"
+)
+
+// collisionKey identifies the name of a record by the prefix it's joined to
+// and the record's address
+type collisionKey struct {
+	prefix string
+	addr   uintptr
+}
+
+// resolveCollision returns the identifier joined to prefix for a struct
+// instance, renamed as set by CollisionPolicy when an earlier record of its
+// dataset has the same identifier. The names of a dataset are resolved
+// together, in order, on first use.
+func (g *Generator) resolveCollision(prefix string, structValue reflect.Value) string {
+	ident := g.unprefixedIdentifier(prefix, structValue)
+	if g.CollisionPolicy != CollisionSuffix && g.CollisionPolicy != CollisionHash {
+		return ident
+	}
+	structValue = reflect.Indirect(structValue)
+	if !structValue.CanAddr() {
+		return ident
+	}
+	key := collisionKey{prefix: prefix, addr: structValue.UnsafeAddr()}
+	if name, ok := g.collisionNames[key]; ok {
+		return name
+	}
+
+	if g.collisionNames == nil {
+		g.collisionNames = make(map[collisionKey]string)
+	}
+	datasets := append([]any{g.Data}, g.refDatasets()...)
+	for _, data := range datasets {
+		dataValue := reflect.ValueOf(data)
+		if (dataValue.Kind() != reflect.Slice && dataValue.Kind() != reflect.Array) ||
+			dataValue.Len() == 0 || reflect.Indirect(dataValue.Index(0)).Type() != structValue.Type() {
+			continue
+		}
+		used := make(map[string]bool)
+		for i := range dataValue.Len() {
+			elem := reflect.Indirect(dataValue.Index(i))
+			if !elem.IsValid() || !elem.CanAddr() {
+				continue
+			}
+			name := g.unprefixedIdentifier(prefix, elem)
+			if used[name] {
+				name = g.uniqueIdentifier(name, g.getStructIdentifier(elem), used)
+			}
+			used[name] = true
+			g.collisionNames[collisionKey{prefix: prefix, addr: elem.UnsafeAddr()}] = name
+		}
+	}
+
+	if name, ok := g.collisionNames[key]; ok {
+		return name
+	}
+	return ident
+}
+
+// uniqueIdentifier returns ident renamed as set by CollisionPolicy so that it
+// isn't in used. Hashes of equal identifiers fall back to numeric suffixes.
+func (g *Generator) uniqueIdentifier(ident, identValue string, used map[string]bool) string {
+	if g.CollisionPolicy == CollisionHash {
+		sum := sha256.Sum256([]byte(identValue))
+		ident += hex.EncodeToString(sum[:])[:truncationHashLen]
+		if !used[ident] {
+			return ident
+		}
+	}
+	n := 2
+	for used[ident+strconv.Itoa(n)] {
+		n++
+	}
+	return ident + strconv.Itoa(n)
+}
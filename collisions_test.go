@@ -0,0 +1,65 @@
+package genstruct
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestCollisionPolicy tests that records named like an earlier record of their
+// dataset are renamed consistently as set by the policy
+func TestCollisionPolicy(t *testing.T) {
+	tags := []Tag{
+		{ID: "tag-1", Name: "Go!", Slug: "go"},
+		{ID: "tag-2", Name: "go", Slug: "go"},
+		{ID: "tag-3", Name: "GO", Slug: "go"},
+		{ID: "tag-4", Name: "Rust", Slug: "rust"},
+	}
+	posts := []Post{{ID: "post-1", Title: "Intro", TagSlugs: []string{"go"}}}
+
+	render := func(policy CollisionPolicy) (string, error) {
+		src, err := NewGenerator(
+			WithPackageName("blog"),
+			WithOutputFile("posts.go"),
+			WithIdentifierFieldsFor("Tag", []string{"Name"}),
+			WithCollisionPolicy(policy),
+		).Render(posts, tags)
+		return string(src), err
+	}
+
+	output, err := render(CollisionSuffix)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	for _, want := range []string{
+		"var TagGo = ",
+		"var TagGo2 = ",
+		"var TagGo3 = ",
+		"var TagRust = ",
+		"TagGo2ID ",
+		"[]*Tag{&TagGo2}",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	output, err = render(CollisionHash)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	for _, want := range []string{"var TagGo = ", "var TagGo4cd0e2 = ", "[]*Tag{&TagGo4cd0e2}", "var TagRust = "} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	var duplicateErr DuplicateNameError
+	if _, err := render(CollisionError); !errors.As(err, &duplicateErr) {
+		t.Errorf("Expected DuplicateNameError, got %v", err)
+	}
+	var configErr ConfigError
+	if _, err := render("random"); !errors.As(err, &configErr) || configErr.Option != "CollisionPolicy" {
+		t.Errorf("Expected CollisionPolicy ConfigError, got %v", err)
+	}
+}
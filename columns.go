@@ -0,0 +1,56 @@
+package genstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// mapColumns returns the index of the field each named column is mapped to,
+// nil for columns without a field. A column maps to the field whose tagKey
+// tag equals its name, falling back to the untagged field whose name matches
+// ignoring case, spaces, underscores and hyphens; fields tagged "-" are
+// skipped.
+func mapColumns(structType reflect.Type, tagKey string, names []string) ([][]int, error) {
+	columns := make([][]int, len(names))
+	mapped := make(map[string]string)
+	for column, name := range names {
+		field, ok := columnField(structType, tagKey, strings.TrimSpace(name))
+		if !ok {
+			continue
+		}
+		if previous, ok := mapped[field.Name]; ok {
+			return nil, fmt.Errorf("columns %q and %q both map to field %s", previous, name, field.Name)
+		}
+		mapped[field.Name] = name
+		columns[column] = field.Index
+	}
+	return columns, nil
+}
+
+// columnField returns the exported field of structType the column maps to
+func columnField(structType reflect.Type, tagKey, column string) (reflect.StructField, bool) {
+	var byName reflect.StructField
+	found := false
+	for _, field := range reflect.VisibleFields(structType) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		tag, hasTag := field.Tag.Lookup(tagKey)
+		if hasTag {
+			if tag == column && tag != "-" {
+				return field, true
+			}
+			continue
+		}
+		if !found && normalizeColumnName(field.Name) == normalizeColumnName(column) {
+			byName, found = field, true
+		}
+	}
+	return byName, found
+}
+
+// normalizeColumnName lowercases name and drops spaces, underscores and hyphens
+func normalizeColumnName(name string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(name))
+}
@@ -0,0 +1,58 @@
+package genstruct
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// langMinorAny is the minimum Go minor version (of Go 1.x) with the
+// predeclared any alias.
+const langMinorAny = 18
+
+// parseLangVersion parses a Go language version such as "1.17" or "go1.21.3"
+// and returns its major and minor components.
+func parseLangVersion(version string) (major, minor int, err error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(version), "go")
+	parts := strings.Split(trimmed, ".")
+	if len(parts) < 2 {
+		return 0, 0, InvalidLangVersionError{Version: version}
+	}
+
+	major, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, InvalidLangVersionError{Version: version}
+	}
+	minor, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, InvalidLangVersionError{Version: version}
+	}
+
+	return major, minor, nil
+}
+
+// langAtLeast reports whether the configured LangVersion is at least go1.minor.
+// When no LangVersion is configured every feature is considered available.
+func (g *Generator) langAtLeast(minor int) bool {
+	if g.LangVersion == "" {
+		return true
+	}
+	major, m, err := parseLangVersion(g.LangVersion)
+	if err != nil {
+		return true
+	}
+	if major != 1 {
+		return major > 1
+	}
+	return m >= minor
+}
+
+// anyType returns the empty interface type, spelled as any when the target
+// Go version supports it and as interface{} otherwise.
+func (g *Generator) anyType() *jen.Statement {
+	if g.langAtLeast(langMinorAny) {
+		return jen.Any()
+	}
+	return jen.Interface()
+}
@@ -0,0 +1,33 @@
+package genstruct
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestLangVersion tests that the target Go version gates generated features
+func TestLangVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		anyType string
+	}{
+		{"", "any"},
+		{"1.17", "interface{}"},
+		{"go1.18", "any"},
+		{"go1.21.3", "any"},
+		{"2.0", "any"},
+	}
+
+	for _, tt := range tests {
+		g := NewGenerator(WithLangVersion(tt.version))
+		if got := fmt.Sprintf("%#v", g.anyType()); got != tt.anyType {
+			t.Errorf("anyType() for %q = %s, want %s", tt.version, got, tt.anyType)
+		}
+	}
+
+	var versionErr InvalidLangVersionError
+	if _, _, err := parseLangVersion("latest"); !errors.As(err, &versionErr) {
+		t.Errorf("Expected InvalidLangVersionError, got %v", err)
+	}
+}
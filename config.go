@@ -0,0 +1,253 @@
+package genstruct
+
+import (
+	"errors"
+	"go/token"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Validate checks the configuration against the data that would be passed to
+// Generate and reports every contradictory or invalid setting at once.
+//
+// Values that are not set are inferred from data the same way Generate infers
+// them, without modifying the generator. Generate runs the same checks before
+// emitting any code.
+func (g *Generator) Validate(data any) error {
+	data, err := g.loadSource(data)
+	if err != nil {
+		return err
+	}
+	run := *g
+	run.Data = g.unwrapPointer(data)
+	run.resetInferred()
+	if err := run.inferConfig(run.Data); err != nil {
+		return err
+	}
+	return run.validateConfig(run.dataStructType())
+}
+
+// validateConfig checks the inferred configuration. structType is the element
+// type of the primary dataset, or nil when it is not known.
+func (g *Generator) validateConfig(structType reflect.Type) error {
+	var errs []error
+
+	// A supplied file already carries its package clause
+	if !g.fileProvided && !token.IsIdentifier(g.PackageName) {
+		reason := "not a valid Go identifier"
+		if g.inferred.packageName {
+			reason = "derived from output file " + g.OutputFile + " is not a valid Go identifier, use WithPackageName"
+		}
+		errs = append(errs, ConfigError{Option: "PackageName", Value: g.PackageName, Reason: reason})
+	}
+
+	// TypeName may be qualified with a package (e.g. "pkg.Animal")
+	for _, part := range strings.Split(g.TypeName, ".") {
+		if !token.IsIdentifier(part) {
+			errs = append(errs, ConfigError{Option: "TypeName", Value: g.TypeName, Reason: "not a valid Go identifier"})
+			break
+		}
+	}
+
+	if !token.IsIdentifier(g.VarPrefix) {
+		errs = append(errs, ConfigError{Option: "VarPrefix", Value: g.VarPrefix, Reason: "contains characters not allowed in Go identifiers"})
+	}
+	// An empty ConstantIdent leaves the constants of GenerateConstants unprefixed
+	if g.ConstantIdent != "" && !token.IsIdentifier(g.ConstantIdent) {
+		errs = append(errs, ConfigError{Option: "ConstantIdent", Value: g.ConstantIdent, Reason: "contains characters not allowed in Go identifiers"})
+	}
+
+	if g.MaxIdentifierLen < 0 || (g.MaxIdentifierLen > 0 && g.MaxIdentifierLen <= truncationHashLen) {
+		errs = append(errs, ConfigError{
+			Option: "MaxIdentifierLen",
+			Value:  strconv.Itoa(g.MaxIdentifierLen),
+			Reason: "must leave room for the " + strconv.Itoa(truncationHashLen) + " character hash suffix",
+		})
+	}
+
+	if _, err := g.parseHeaderTemplate(); err != nil {
+		errs = append(errs, ConfigError{Option: "HeaderTemplate", Value: g.HeaderTemplate, Reason: err.Error()})
+	}
+
+	switch g.CollisionPolicy {
+	case "", CollisionError, CollisionSuffix, CollisionHash:
+	default:
+		errs = append(errs, ConfigError{Option: "CollisionPolicy", Value: string(g.CollisionPolicy), Reason: "must be error, suffix or hash"})
+	}
+	if g.MaxDepth < 0 {
+		errs = append(errs, ConfigError{Option: "MaxDepth", Value: strconv.Itoa(g.MaxDepth), Reason: "must not be negative"})
+	}
+	if g.MaxFileKB < 0 {
+		errs = append(errs, ConfigError{Option: "MaxFileKB", Value: strconv.Itoa(g.MaxFileKB), Reason: "must not be negative"})
+	}
+	if g.MaxFileDecls < 0 {
+		errs = append(errs, ConfigError{Option: "MaxFileDecls", Value: strconv.Itoa(g.MaxFileDecls), Reason: "must not be negative"})
+	}
+
+	for _, pkgPath := range slices.Sorted(maps.Keys(g.PackageConstructors)) {
+		constructor := g.PackageConstructors[pkgPath]
+		if !token.IsIdentifier(constructor) || !token.IsExported(constructor) {
+			errs = append(errs, ConfigError{Option: "PackageConstructors", Value: pkgPath + "." + constructor, Reason: "constructor is not an exported function name"})
+		}
+	}
+
+	for _, typeName := range slices.Sorted(maps.Keys(g.RefPrefixes)) {
+		if prefix := g.RefPrefixes[typeName]; !token.IsIdentifier(prefix) {
+			errs = append(errs, ConfigError{Option: "RefPrefixes", Value: typeName + ": " + prefix, Reason: "contains characters not allowed in Go identifiers"})
+		}
+	}
+
+	for _, typeName := range slices.Sorted(maps.Keys(g.SliceNames)) {
+		if name := g.SliceNames[typeName]; !token.IsIdentifier(name) {
+			errs = append(errs, ConfigError{Option: "SliceNames", Value: typeName + ": " + name, Reason: "contains characters not allowed in Go identifiers"})
+		}
+	}
+
+	for _, typeName := range slices.Sorted(maps.Keys(g.RefOutputFiles)) {
+		if path := g.RefOutputFiles[typeName]; path == "" || filepath.Ext(path) != ".go" {
+			errs = append(errs, ConfigError{Option: "RefOutputFiles", Value: typeName + ": " + path, Reason: "must be a .go file"})
+		}
+	}
+
+	if g.nameFunc() == nil {
+		typeFieldsSet := false
+		if structType != nil {
+			_, typeFieldsSet = g.TypeIdentifierFields[structType.Name()]
+		}
+		if len(g.IdentifierFields) == 0 {
+			errs = append(errs, ConfigError{Option: "IdentifierFields", Reason: "no fields given and no naming function set"})
+		} else if g.identifierFieldsSet && !typeFieldsSet && structType != nil && !hasAnyField(structType, g.IdentifierFields) {
+			errs = append(errs, ConfigError{
+				Option: "IdentifierFields",
+				Value:  strings.Join(g.IdentifierFields, ", "),
+				Reason: "none of the fields exist on type " + structType.Name(),
+			})
+		}
+
+		for _, typeName := range slices.Sorted(maps.Keys(g.TypeIdentifierFields)) {
+			fields := g.TypeIdentifierFields[typeName]
+			if len(fields) == 0 {
+				errs = append(errs, ConfigError{Option: "TypeIdentifierFields", Value: typeName, Reason: "no fields given"})
+			} else if structType != nil && structType.Name() == typeName && !hasAnyField(structType, fields) {
+				errs = append(errs, ConfigError{
+					Option: "TypeIdentifierFields",
+					Value:  typeName + ": " + strings.Join(fields, ", "),
+					Reason: "none of the fields exist on type " + typeName,
+				})
+			}
+		}
+	}
+
+	if structType != nil {
+		for _, name := range g.RangeIndexFields {
+			field, ok := structType.FieldByName(name)
+			if !ok || !isOrderedKind(field.Type.Kind()) {
+				errs = append(errs, ConfigError{
+					Option: "RangeIndexFields",
+					Value:  name,
+					Reason: "not an integer or float field of type " + structType.Name(),
+				})
+			}
+		}
+		for _, name := range g.FlagSetFields {
+			field, ok := structType.FieldByName(name)
+			if !ok || !isFlagSetField(field.Type) {
+				errs = append(errs, ConfigError{
+					Option: "FlagSetFields",
+					Value:  name,
+					Reason: "not a string slice field of type " + structType.Name(),
+				})
+			}
+		}
+		for _, name := range g.SortOrderFields {
+			field, ok := structType.FieldByName(name)
+			if !ok || sortOrderLess(field.Type) == nil {
+				errs = append(errs, ConfigError{
+					Option: "SortOrderFields",
+					Value:  name,
+					Reason: "not a number, string, bool or time.Time field of type " + structType.Name(),
+				})
+			}
+		}
+		for _, name := range g.DateIndexFields {
+			field, ok := structType.FieldByName(name)
+			if !ok || field.Type != timeType {
+				errs = append(errs, ConfigError{
+					Option: "DateIndexFields",
+					Value:  name,
+					Reason: "not a time.Time field of type " + structType.Name(),
+				})
+			}
+		}
+		for _, filter := range g.Filters {
+			if !token.IsIdentifier(filter.Name) {
+				errs = append(errs, ConfigError{Option: "Filters", Value: filter.Name, Reason: "not a valid Go identifier"})
+			}
+			if filter.recordType != nil && filter.recordType != structType && filter.recordType != reflect.PointerTo(structType) {
+				errs = append(errs, ConfigError{
+					Option: "Filters",
+					Value:  filter.Name,
+					Reason: "declared for " + filter.recordType.String() + " instead of " + structType.Name(),
+				})
+			}
+		}
+		for _, feed := range g.Feeds {
+			errs = append(errs, validateFeed(feed, structType)...)
+		}
+		for _, asset := range g.AssetHashes {
+			errs = append(errs, validateAssetHash(asset, structType)...)
+		}
+		if g.PartitionField != "" {
+			if _, ok := structType.FieldByName(g.PartitionField); !ok {
+				errs = append(errs, ConfigError{
+					Option: "PartitionField",
+					Value:  g.PartitionField,
+					Reason: "not a field of type " + structType.Name(),
+				})
+			}
+		}
+		if g.Attributions != nil {
+			errs = append(errs, validateAttributions(*g.Attributions, structType)...)
+		}
+		if g.CloneMethods && !g.hasCloneMethod(structType) {
+			errs = append(errs, ConfigError{
+				Option: "CloneMethods",
+				Value:  structType.String(),
+				Reason: "methods can only be generated in the package declaring the type",
+			})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// dataStructType returns the struct type of the elements of the primary
+// dataset, or nil if it cannot be determined
+func (g *Generator) dataStructType() reflect.Type {
+	dataValue := reflect.ValueOf(g.Data)
+	if dataValue.Kind() != reflect.Slice && dataValue.Kind() != reflect.Array {
+		return nil
+	}
+	elemType := dataValue.Type().Elem()
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return nil
+	}
+	return elemType
+}
+
+// hasAnyField reports whether structType has at least one of the named fields
+func hasAnyField(structType reflect.Type, names []string) bool {
+	for _, name := range names {
+		if _, ok := structType.FieldByName(name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package genstruct
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestValidate tests that invalid and contradictory settings are reported
+func TestValidate(t *testing.T) {
+	tags := []Tag{{ID: "tag-1", Name: "Go", Slug: "go"}}
+
+	valid := NewGenerator(WithPackageName("testdata"))
+	if err := valid.Validate(tags); err != nil {
+		t.Errorf("Expected valid configuration, got %v", err)
+	}
+	if valid.TypeName != "" {
+		t.Errorf("Expected Validate to leave the generator unchanged, got TypeName %q", valid.TypeName)
+	}
+
+	tests := []struct {
+		name   string
+		opts   []Option
+		option string
+	}{
+		{"package from path", []Option{WithOutputFile("tags.go")}, "PackageName"},
+		{"package", []Option{WithPackageName("my-pkg")}, "PackageName"},
+		{"var prefix", []Option{WithPackageName("testdata"), WithVarPrefix("Tag-")}, "VarPrefix"},
+		{"constant ident", []Option{WithPackageName("testdata"), WithConstantIdent("1Tag")}, "ConstantIdent"},
+		{"identifier fields", []Option{WithPackageName("testdata"), WithIdentifierFields([]string{"Handle"})}, "IdentifierFields"},
+		{"no identifier fields", []Option{WithPackageName("testdata"), WithIdentifierFields(nil)}, "IdentifierFields"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewGenerator(tt.opts...).Validate(tags)
+			var configErr ConfigError
+			if !errors.As(err, &configErr) || configErr.Option != tt.option {
+				t.Errorf("Expected %s error, got %v", tt.option, err)
+			}
+		})
+	}
+
+	// Generate runs the same checks before writing anything
+	err := NewGenerator(WithPackageName("testdata"), WithVarPrefix("Tag Var")).Generate(tags)
+	var configErr ConfigError
+	if !errors.As(err, &configErr) {
+		t.Errorf("Expected Generate to fail with a ConfigError, got %v", err)
+	}
+}
+
+// TestExportMode tests that WithExportMode overrides the export mode inferred
+// from the output file path
+func TestExportMode(t *testing.T) {
+	tags := []Tag{{ID: "tag-1", Name: "Go", Slug: "go"}}
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		qualified bool
+	}{
+		{"inferred from plain file", []Option{WithOutputFile("tags.go")}, false},
+		{"inferred from relative file", []Option{WithOutputFile("./tags.go")}, false},
+		{"inferred from directory", []Option{WithOutputFile("out/tags.go")}, true},
+		{"enabled", []Option{WithOutputFile("tags.go"), WithExportMode(true)}, true},
+		{"disabled", []Option{WithOutputFile("out/tags.go"), WithExportMode(false)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithPackageName("blog"), WithWorkingDir(t.TempDir())}, tt.opts...)
+			generator := NewGenerator(opts...)
+			src, err := generator.Render(tags)
+			if err != nil {
+				t.Fatalf("Error rendering code: %v", err)
+			}
+			if generator.ExportMode != tt.qualified {
+				t.Errorf("Expected ExportMode %v, got %v", tt.qualified, generator.ExportMode)
+			}
+			if qualified := strings.Contains(string(src), "genstruct.Tag{"); qualified != tt.qualified {
+				t.Errorf("Expected qualified types %v, got:\n%s", tt.qualified, src)
+			}
+		})
+	}
+}
@@ -3,40 +3,90 @@ package genstruct
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/dave/jennifer/jen"
 )
 
-// generateConstants creates ID constants for each struct if an ID field exists
-func (g *Generator) generateConstants(dataValue reflect.Value) {
-	var (
-		hasIDField  bool
-		idFieldName string
-	)
-
-	// Check if the struct has an ID field
-	firstElem := dataValue.Index(0)
+// idFieldName returns the name of the struct's ID field (case insensitive),
+// or an empty string if the struct has no ID field
+func (g *Generator) idFieldName(elem reflect.Value) string {
 	// Handle pointer to struct case
-	if firstElem.Kind() == reflect.Pointer {
-		firstElem = firstElem.Elem()
+	if elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
 	}
 
 	// Look for an "ID" field (case insensitive)
-	for i := range firstElem.NumField() {
-		fieldName := firstElem.Type().Field(i).Name
+	for i := range elem.NumField() {
+		fieldName := elem.Type().Field(i).Name
 		if strings.ToLower(fieldName) == "id" {
-			hasIDField = true
-			idFieldName = fieldName
-			break
+			return fieldName
 		}
 	}
+	return ""
+}
 
-	if !hasIDField {
+// SequentialID is the default synthetic ID strategy, returning the lowercase
+// type name and the 1-based position of the record (e.g. "animal-3")
+func SequentialID(typeName string, index int) string {
+	return fmt.Sprintf("%s-%d", strings.ToLower(typeName), index+1)
+}
+
+// syntheticIDFor returns the synthetic ID of the record at index of the
+// source dataset
+func (g *Generator) syntheticIDFor(index int) string {
+	if g.SyntheticIDFn == nil {
+		return SequentialID(g.TypeName, index)
+	}
+	return g.SyntheticIDFn(g.TypeName, index)
+}
+
+// generateConstants creates ID constants for each struct if an ID field exists
+func (g *Generator) generateConstants(dataValue reflect.Value) {
+	// Check if the struct has an ID field
+	idFieldName := g.idFieldName(dataValue.Index(0))
+	if idFieldName == "" {
 		return // No ID field found
 	}
 
-	// Create constants for each ID
+	// Create constants for each ID, in sections when partitioned
+	g.File.Const().DefsFunc(func(group *jen.Group) {
+		for _, section := range g.sections(dataValue) {
+			section.writeComment(group)
+			for _, i := range section.indices {
+				elem := dataValue.Index(i)
+				// Handle pointer to struct case
+				if elem.Kind() == reflect.Pointer {
+					elem = elem.Elem()
+				}
+
+				idField := elem.FieldByName(idFieldName)
+
+				// If there's an ID field that's a string, create a constant
+				if idField.IsValid() &&
+					idField.Kind() == reflect.String {
+
+					idValue := idField.String()
+					// If ID is empty, generate one
+					if idValue == "" {
+						idValue = g.syntheticIDFor(g.sourceIndex(dataValue, i))
+					}
+
+					// Get a name for the constant based on the struct
+					constName := g.safeName(g.prefixedIdentifier(g.ConstantIdent, elem) + "ID")
+					group.Id(constName).Op("=").Lit(idValue)
+				}
+			}
+		}
+	})
+}
+
+// generateIdentifierConstants creates one constant per populated identifier
+// field per struct (e.g. PostIntroToGoSlug), in addition to the ID constants
+func (g *Generator) generateIdentifierConstants(dataValue reflect.Value) {
+	idFieldName := g.idFieldName(dataValue.Index(0))
+
 	g.File.Const().DefsFunc(func(group *jen.Group) {
 		for i := range dataValue.Len() {
 			elem := dataValue.Index(i)
@@ -45,23 +95,72 @@ func (g *Generator) generateConstants(dataValue reflect.Value) {
 				elem = elem.Elem()
 			}
 
-			idField := elem.FieldByName(idFieldName)
+			recordName := g.prefixedIdentifier(g.ConstantIdent, elem)
+			for _, fieldName := range g.identifierFields(elem.Type()) {
+				// ID constants are already generated by generateConstants
+				if fieldName == idFieldName {
+					continue
+				}
 
-			// If there's an ID field that's a string, create a constant
-			if idField.IsValid() &&
-				idField.Kind() == reflect.String {
+				field := elem.FieldByName(fieldName)
+				if !field.IsValid() ||
+					field.Kind() != reflect.String ||
+					field.String() == "" {
+					continue
+				}
 
-				idValue := idField.String()
-				// If ID is empty, generate one
-				if idValue == "" {
-					idValue = fmt.Sprintf("%s-%d", strings.ToLower(g.TypeName), i+1)
+				constName := g.safeName(recordName + fieldName)
+				group.Id(constName).Op("=").Lit(field.String())
+			}
+		}
+	})
+}
+
+// generateMapKeyConstants creates a constant for every key appearing in the
+// string-keyed map fields of the structs (e.g. PostMetadataKeyAuthor = "author")
+func (g *Generator) generateMapKeyConstants(dataValue reflect.Value) {
+	structType := dataValue.Index(0).Type()
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	// Keys normalizing to the same name (e.g. "a-b" and "a_b") would redeclare it
+	keysByName := make(map[string]string)
+	g.File.Const().DefsFunc(func(group *jen.Group) {
+		for i := range structType.NumField() {
+			field := structType.Field(i)
+			if !field.IsExported() ||
+				field.Type.Kind() != reflect.Map ||
+				field.Type.Key().Kind() != reflect.String {
+				continue
+			}
+
+			// Collect the keys used by any struct in the dataset
+			keys := make(map[string]bool)
+			for j := range dataValue.Len() {
+				elem := dataValue.Index(j)
+				if elem.Kind() == reflect.Pointer {
+					elem = elem.Elem()
+				}
+				for _, key := range elem.Field(i).MapKeys() {
+					keys[key.String()] = true
 				}
+			}
 
-				// Get a name for the constant based on the struct
-				identValue := g.getStructIdentifier(elem)
+			sortedKeys := make([]string, 0, len(keys))
+			for key := range keys {
+				sortedKeys = append(sortedKeys, key)
+			}
+			sort.Strings(sortedKeys)
 
-				constName := g.ConstantIdent + slugToIdentifier(identValue) + "ID"
-				group.Id(constName).Op("=").Lit(idValue)
+			for _, key := range sortedKeys {
+				constName := g.safeName(g.ConstantIdent + field.Name + "Key" + g.sanitizeIdentifier(key))
+				if previous, ok := keysByName[constName]; ok {
+					g.addGenError(DuplicateNameError{Name: constName, Record: key, Previous: previous})
+					continue
+				}
+				keysByName[constName] = key
+				group.Id(constName).Op("=").Lit(key)
 			}
 		}
 	})
@@ -0,0 +1,125 @@
+package genstruct
+
+import (
+	"errors"
+	"go/token"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// GenerateConstants generates a const block declaring a string constant for
+// each entry of constants with a generator configured by opts, e.g.
+//
+//	err := genstruct.GenerateConstants(
+//		map[string]string{"foo": "bar"},
+//		genstruct.WithConstantIdent("Key"),
+//		genstruct.WithOutputFile("keys.go"),
+//	)
+//
+// declares const KeyFoo = "bar". See Generator.GenerateConstants.
+func GenerateConstants(constants map[string]string, opts ...Option) error {
+	return NewGenerator(opts...).GenerateConstants(constants)
+}
+
+// GenerateConstants generates a const block declaring a string constant for
+// each entry of constants, in key order.
+//
+// Constants are named by ConstantIdent followed by the key converted like
+// record identifiers, so WithConstantIdent sets their prefix and
+// WithInitialisms or WithIdentifierSanitizer control the rest of the name.
+// OutputFile defaults to constants_generated.go and PackageName is inferred
+// from it like in Generate, so a file in the working directory needs
+// WithPackageName. The generated file carries the WithHeaderTemplate header,
+// with {{.Count}} being the number of constants.
+//
+// Returns an InvalidRecordError if a key doesn't produce a valid Go
+// identifier, a DuplicateNameError if two keys produce the same name, a
+// ReservedNameError if a name is reserved (see WithRenameReserved), and a
+// ConfigError if the configuration is invalid.
+func (g *Generator) GenerateConstants(constants map[string]string) error {
+	g.genErrors = nil
+	keys := slices.Sorted(maps.Keys(constants))
+	names := make([]string, len(keys))
+	keysByName := make(map[string]string, len(keys))
+	for i, key := range keys {
+		name := g.ConstantIdent + g.sanitizeIdentifier(key)
+		if !token.IsIdentifier(name) {
+			return InvalidRecordError{Name: name, Reason: "name of the constant for key " + key + " is not a valid Go identifier"}
+		}
+		if previous, ok := keysByName[name]; ok {
+			return DuplicateNameError{Name: name, Record: key, Previous: previous}
+		}
+		keysByName[name] = key
+		names[i] = g.safeName(name)
+	}
+	if err := errors.Join(g.genErrors...); err != nil {
+		return err
+	}
+
+	// Infer the configuration not derived from typed data
+	g.resetInferred()
+	if g.TypeName == "" {
+		g.TypeName = "Constant"
+		g.inferred.typeName = true
+	}
+	if g.OutputFile == "" {
+		g.OutputFile = "constants_generated.go"
+		g.inferred.outputFile = true
+	}
+	if g.VarPrefix == "" {
+		g.VarPrefix = g.TypeName
+		g.inferred.varPrefix = true
+	}
+	if g.PackageName == "" {
+		g.PackageName = GetPackageNameFromPath(g.OutputFile)
+		g.inferred.packageName = true
+	}
+	g.inferExportMode()
+	if err := g.validateConfig(nil); err != nil {
+		g.Logger.Error("Invalid configuration", "error", err)
+		return err
+	}
+	if err := g.validateOutputPath(); err != nil {
+		g.Logger.Error("Invalid output path", "error", err)
+		return err
+	}
+
+	// The entries are the primary dataset, e.g. for {{.Count}} in the header
+	entries := make([][2]string, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, [2]string{key, constants[key]})
+	}
+	g.Data = entries
+	hash, err := HashDataset(entries)
+	if err != nil {
+		return err
+	}
+	g.Hash = hash
+
+	if g.headerLines, err = g.renderHeader(); err != nil {
+		g.Logger.Error("Invalid header template", "error", err)
+		return err
+	}
+
+	g.File = jen.NewFile(g.PackageName)
+	if err := g.writePackageComment(); err != nil {
+		return err
+	}
+
+	g.Logger.Info(
+		"Generating constants",
+		slog.String("package", g.PackageName),
+		slog.Int("count", len(keys)),
+		slog.String("output", g.OutputFile),
+	)
+	g.File.Const().DefsFunc(func(group *jen.Group) {
+		for i, key := range keys {
+			group.Id(names[i]).Op("=").Lit(constants[key])
+		}
+	})
+
+	return g.writeOutput()
+}
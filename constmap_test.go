@@ -0,0 +1,94 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGenerateConstants tests that map entries are emitted as constants named
+// by their prefixed keys
+func TestGenerateConstants(t *testing.T) {
+	dir := t.TempDir()
+	err := GenerateConstants(
+		map[string]string{"foo": "bar", "api-url": "https://example.com", "max-retries": "3"},
+		WithPackageName("config"),
+		WithOutputFile("keys.go"),
+		WithWorkingDir(dir),
+		WithConstantIdent("Key"),
+		WithInitialisms(),
+	)
+	if err != nil {
+		t.Fatalf("Error generating constants: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "keys.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	output := string(content)
+	for _, want := range []string{
+		"package config",
+		`KeyAPIURL     = "https://example.com"`,
+		`KeyFoo        = "bar"`,
+		`KeyMaxRetries = "3"`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected to find %q in generated code:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "KeyAPIURL") > strings.Index(output, "KeyFoo") {
+		t.Errorf("Expected constants in key order:\n%s", output)
+	}
+
+	generator := NewGenerator(WithPackageName("config"), WithOutputFile("keys.go"), WithWorkingDir(dir))
+	var recordErr InvalidRecordError
+	if err := generator.GenerateConstants(map[string]string{"404": "not found"}); !errors.As(err, &recordErr) {
+		t.Errorf("Expected InvalidRecordError for a key starting with a digit, got %v", err)
+	}
+	var duplicateErr DuplicateNameError
+	if err := generator.GenerateConstants(map[string]string{"a-b": "1", "a_b": "2"}); !errors.As(err, &duplicateErr) {
+		t.Errorf("Expected DuplicateNameError for keys with the same name, got %v", err)
+	}
+}
+
+// TestGenerateConstantsConfig tests that constants are generated with the
+// package name inferred from the output file and the custom header, and that
+// an invalid inferred package name is rejected
+func TestGenerateConstantsConfig(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "config"), 0755); err != nil {
+		t.Fatalf("Error creating package directory: %v", err)
+	}
+	err := GenerateConstants(
+		map[string]string{"foo": "bar", "baz": "qux"},
+		WithOutputFile(filepath.Join("config", "keys.go")),
+		WithWorkingDir(dir),
+		WithConstantIdent("Key"),
+		WithHeaderTemplate("Copyright {{.Year}} Example Corp.\n{{.Tool}} generated {{.Count}} {{.Type}} values"),
+	)
+	if err != nil {
+		t.Fatalf("Error generating constants: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "config", "keys.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	header := generatedHeader + "\n// Copyright 2023 Example Corp.\n// genstruct generated 2 Constant values\n\n// Package config"
+	if !strings.HasPrefix(string(content), header) {
+		t.Errorf("Expected generated file to start with %q, got:\n%s", header, content)
+	}
+
+	var configErr ConfigError
+	err = GenerateConstants(map[string]string{"foo": "bar"}, WithWorkingDir(dir), WithConstantIdent("Key"))
+	if !errors.As(err, &configErr) || configErr.Option != "PackageName" {
+		t.Errorf("Expected PackageName ConfigError for the default output file, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "constants_generated.go")); !os.IsNotExist(err) {
+		t.Errorf("Expected no file to be written for an invalid configuration, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package genstruct
+
+import (
+	"log/slog"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// funcFile returns the file generated functions are declared in
+func (g *Generator) funcFile() *jen.File {
+	if g.FuncFile != nil {
+		return g.FuncFile
+	}
+	return g.File
+}
+
+// declareFunc writes the doc comment lines and coverage marker of a generated
+// function and returns the func statement to complete
+func (g *Generator) declareFunc(comments ...string) *jen.Statement {
+	file := g.funcFile()
+	for _, comment := range comments {
+		file.Comment(comment)
+	}
+	if g.CoverageMarker != "" {
+		file.Comment(g.CoverageMarker)
+	}
+	return file.Func()
+}
+
+// validateFuncsPath makes sure the functions file stays inside the module
+func (g *Generator) validateFuncsPath() error {
+	fg := *g
+	fg.OutputFile = g.FuncsFile
+	return fg.validateOutputPath()
+}
+
+// writeFuncsFile renders the generated functions and writes them to FuncsFile
+func (g *Generator) writeFuncsFile() error {
+	g.Logger.Debug(
+		"Writing generated functions to file",
+		slog.String("file", g.FuncsFile),
+	)
+	return g.writeSplit(g.FuncsFile, g.FuncFile)
+}
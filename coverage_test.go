@@ -0,0 +1,54 @@
+package genstruct
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestFuncsFileAndCoverageMarker tests emitting helper functions into their own
+// file with a coverage marker
+func TestFuncsFileAndCoverageMarker(t *testing.T) {
+	type Animal struct {
+		ID     string
+		Weight float64
+	}
+	animals := []Animal{{ID: "leo", Weight: 190.5}, {ID: "mia", Weight: 4.2}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile("animals.go"),
+		WithWorkingDir(dir),
+		WithRangeIndexes("Weight"),
+		WithFuncsFile("animals_funcs.go"),
+		WithCoverageMarker("//coverage:ignore"),
+	)
+	if err := generator.Generate(animals); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	data, err := os.ReadFile(dir + "/animals.go")
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	if !strings.HasPrefix(string(data), generatedHeader+"\n\n// Package zoo") {
+		t.Errorf("Expected the generated header to precede the package comment:\n%s", data)
+	}
+	if strings.Contains(string(data), "func ") {
+		t.Errorf("Expected no functions in the data file:\n%s", data)
+	}
+
+	funcs, err := os.ReadFile(dir + "/animals_funcs.go")
+	if err != nil {
+		t.Fatalf("Error reading functions file: %v", err)
+	}
+	for _, want := range []string{
+		generatedHeader + "\n\npackage zoo",
+		"//coverage:ignore\nfunc AnimalsWithWeightBetween(lo, hi float64) []*Animal {",
+	} {
+		if !strings.Contains(string(funcs), want) {
+			t.Errorf("Expected to find %q in functions file:\n%s", want, funcs)
+		}
+	}
+}
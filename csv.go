@@ -0,0 +1,148 @@
+package genstruct
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvTimeLayouts are the layouts tried, in order, to parse time.Time cells
+var csvTimeLayouts = []string{time.RFC3339, time.DateTime, time.DateOnly}
+
+// CSVSource is a dataset decoded from CSV records when passed to Generate,
+// Validate or Explain, as the primary dataset or as a reference dataset.
+// Create one with CSVFile or CSVReader, which fix the struct type the rows
+// decode to.
+//
+// The first row is a header naming the column of each field. A column is
+// mapped to the field whose csv tag equals the header, e.g. `csv:"Unit
+// Price"`, falling back to the field whose name matches the header ignoring
+// case, spaces, underscores and hyphens. Fields tagged `csv:"-"` and columns
+// without a field are ignored.
+//
+// Cells are converted to the field type: strings are kept as they are, while
+// ints, uints, floats, bools and time.Time values are parsed after trimming
+// spaces. Times are parsed as RFC 3339, "2006-01-02 15:04:05" or
+// "2006-01-02". Empty cells leave the field at its zero value.
+type CSVSource struct {
+	Path   string    // File holding the CSV records, resolved against WorkingDir
+	Reader io.Reader // Reader holding the CSV records, used when Path is empty
+
+	decode func(content []byte) (any, error)
+}
+
+// CSVFile returns a dataset of T decoded from the CSV file at path. The
+// element type T may be a struct or a pointer to a struct.
+//
+//	err := generator.Generate(genstruct.CSVFile[Product]("products.csv"))
+func CSVFile[T any](path string) CSVSource {
+	return CSVSource{Path: path, decode: decodeCSV[T]}
+}
+
+// CSVReader returns a dataset of T decoded from the CSV records read from r.
+func CSVReader[T any](r io.Reader) CSVSource {
+	return CSVSource{Reader: r, decode: decodeCSV[T]}
+}
+
+// decodeCSV decodes CSV records with a header row into a slice of T
+func decodeCSV[T any](content []byte) (any, error) {
+	elemType := reflect.TypeFor[T]()
+	structType := elemType
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not a struct type", elemType)
+	}
+
+	rows, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("missing header row")
+	}
+	header := rows[0]
+	columns, err := mapColumns(structType, "csv", header)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]T, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		record := reflect.New(structType)
+		for column, index := range columns {
+			if index == nil {
+				continue
+			}
+			if err := setCSVField(record.Elem().FieldByIndex(index), row[column]); err != nil {
+				// Lines are 1-based and the header is the first line
+				return nil, CSVFieldError{Line: i + 2, Column: header[column], Value: row[column], Err: err}
+			}
+		}
+		if elemType.Kind() == reflect.Pointer {
+			records = append(records, record.Interface().(T))
+		} else {
+			records = append(records, record.Elem().Interface().(T))
+		}
+	}
+	return records, nil
+}
+
+// setCSVField converts a CSV cell to the type of field and sets it
+func setCSVField(field reflect.Value, cell string) error {
+	if field.Kind() == reflect.String {
+		field.SetString(cell)
+		return nil
+	}
+	cell = strings.TrimSpace(cell)
+	if cell == "" {
+		return nil
+	}
+
+	if field.Type() == reflect.TypeFor[time.Time]() {
+		for _, layout := range csvTimeLayouts {
+			if t, err := time.Parse(layout, cell); err == nil {
+				field.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return errors.New("not a time in a supported layout")
+	}
+
+	switch field.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(cell, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(cell, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(cell, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
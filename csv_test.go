@@ -0,0 +1,80 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCSVSources tests generating from CSV files with header-to-field mapping
+// and type coercion
+func TestCSVSources(t *testing.T) {
+	type Category struct {
+		ID   string
+		Name string
+	}
+	type Product struct {
+		ID         string
+		Name       string
+		UnitPrice  float64 `csv:"Price (USD)"`
+		Stock      int
+		Active     bool
+		Released   time.Time
+		CategoryID string
+		Category   *Category `structgen:"CategoryID"`
+		Notes      string    `csv:"-"`
+	}
+
+	dir := t.TempDir()
+	products := "id,name,Price (USD),stock,active,released,category_id,notes\n" +
+		"widget,Widget,9.5, 12 ,true,2024-03-01,tools,internal\n" +
+		"gadget,Gadget,20,,false,2024-04-02T10:00:00Z,tools,\n"
+	if err := os.WriteFile(filepath.Join(dir, "products.csv"), []byte(products), 0644); err != nil {
+		t.Fatal(err)
+	}
+	categories := strings.NewReader("ID,Name,Comment\ntools,Tools,ignored\n")
+
+	err := NewGenerator(
+		WithPackageName("shop"),
+		WithOutputFile("products.go"),
+		WithWorkingDir(dir),
+	).Generate(CSVFile[Product]("products.csv"), CSVReader[Category](categories))
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "products.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	output := string(content)
+	for _, want := range []string{
+		"var ProductWidget = Product{",
+		"UnitPrice:  9.5",
+		"Stock:      12",
+		"Active:     true",
+		"time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)",
+		"Category:   &CategoryTools",
+		"AllProducts",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "internal") {
+		t.Errorf("Expected the ignored notes column to be left out, got:\n%s", output)
+	}
+
+	err = NewGenerator(
+		WithPackageName("shop"),
+		WithOutputFile("broken.go"),
+		WithWorkingDir(dir),
+	).Generate(CSVReader[Product](strings.NewReader("ID,Stock\nwidget,twelve\n")))
+	var fieldErr CSVFieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Line != 2 || fieldErr.Column != "Stock" {
+		t.Errorf("Expected CSVFieldError for line 2, column Stock, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package genstruct
+
+import (
+	"log/slog"
+	"reflect"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// cycleKey identifies a pointer being rendered by its address and type, as a
+// struct and its first field share an address
+type cycleKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// enterPointer marks the pointer as being rendered until the returned func is
+// called. Returns false when it's already being rendered, meaning the data
+// points back to a value enclosing it (e.g. a linked list looping to its head)
+// and rendering it would never end.
+func (g *Generator) enterPointer(ptr reflect.Value) (func(), bool) {
+	key := cycleKey{ptr: ptr.Pointer(), typ: ptr.Type()}
+	if g.rendering[key] {
+		return nil, false
+	}
+	if g.rendering == nil {
+		g.rendering = make(map[cycleKey]bool)
+	}
+	g.rendering[key] = true
+	return func() { delete(g.rendering, key) }, true
+}
+
+// enterRecord marks the address of the record being rendered, so pointers back
+// to it are detected as cycles too
+func (g *Generator) enterRecord(structValue reflect.Value) func() {
+	switch {
+	case structValue.Kind() == reflect.Pointer:
+	case structValue.CanAddr():
+		structValue = structValue.Addr()
+	default:
+		return func() {}
+	}
+	if leave, ok := g.enterPointer(structValue); ok {
+		return leave
+	}
+	return func() {}
+}
+
+// cycleStatement renders a pointer back to a value enclosing it as nil. A
+// reference to the variable of the enclosing record would be an
+// initialization cycle, which doesn't compile either.
+func (g *Generator) cycleStatement(ptr reflect.Value) *jen.Statement {
+	g.Logger.Warn(
+		"Replaced cyclic pointer with nil",
+		slog.String("type", ptr.Type().String()),
+	)
+	return jen.Nil()
+}
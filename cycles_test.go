@@ -0,0 +1,98 @@
+package genstruct
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// CycleNode is a linked list node that may point back to itself
+type CycleNode struct {
+	ID   string
+	Next *CycleNode
+	Kids []*CycleNode
+}
+
+// TestCyclicPointers tests that pointers back to an enclosing value are
+// rendered as nil with a warning instead of recursing forever
+func TestCyclicPointers(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	// A ring of two nodes, a node pointing to itself, and a tree whose
+	// child points back to its parent
+	nodes := []*CycleNode{{ID: "a"}, {ID: "b"}, {ID: "self"}, {ID: "root"}}
+	nodes[0].Next = nodes[1]
+	nodes[1].Next = nodes[0]
+	nodes[2].Next = nodes[2]
+	nodes[3].Kids = []*CycleNode{{ID: "leaf", Next: nodes[3]}}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module cycles\n\ngo 1.24\n",
+		"types.go": `package main
+
+type CycleNode struct {
+	ID   string
+	Next *CycleNode
+	Kids []*CycleNode
+}
+`,
+		"main.go": `package main
+
+func main() {
+	if CycleNodeA.Next.ID != "b" || CycleNodeA.Next.Next != nil {
+		panic("ring was not cut after one lap")
+	}
+	if CycleNodeSelf.Next != nil {
+		panic("self pointer was not cut")
+	}
+	if CycleNodeRoot.Kids[0].ID != "leaf" || CycleNodeRoot.Kids[0].Next != nil {
+		panic("parent pointer was not cut")
+	}
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Error writing %s: %v", name, err)
+		}
+	}
+
+	var logs bytes.Buffer
+	generator := NewGenerator(
+		WithPackageName("main"),
+		WithOutputFile("nodes.go"),
+		WithWorkingDir(dir),
+		WithTypeScriptFile("nodes.ts"),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+	)
+	if err := generator.Generate(nodes); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	if got := strings.Count(logs.String(), "Replaced cyclic pointer with nil"); got != 4 {
+		t.Errorf("Expected 4 cyclic pointer warnings, got %d:\n%s", got, logs.String())
+	}
+
+	ts, err := os.ReadFile(filepath.Join(dir, "nodes.ts"))
+	if err != nil {
+		t.Fatalf("Error reading TypeScript file: %v", err)
+	}
+	if !strings.Contains(string(ts), `"ID": "self",
+  "Next": null,`) {
+		t.Errorf("Expected the self pointer to be cut in TypeScript, got:\n%s", ts)
+	}
+
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Generated program failed: %v\n%s", err, output)
+	}
+}
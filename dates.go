@@ -0,0 +1,116 @@
+package genstruct
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// timeType is the reflect type of time.Time
+var timeType = reflect.TypeOf(time.Time{})
+
+// lessTime reports whether the time.Time value a is before b
+func lessTime(a, b reflect.Value) bool {
+	return a.Interface().(time.Time).Before(b.Interface().(time.Time))
+}
+
+// groupTime returns the time used to group a record, in the location the
+// generated code will hold it in
+func (g *Generator) groupTime(t time.Time) time.Time {
+	if g.TimeZoneMode == TimeZoneUTC {
+		return t.UTC()
+	}
+	return t
+}
+
+// generateDateIndexes creates, for each configured time.Time field, a slice of
+// the records sorted by that field, maps grouping them by year and by
+// year-month (e.g. PostsByDateYearMonth["2023-01"]), and helpers returning the
+// records before or after a given time using binary search
+func (g *Generator) generateDateIndexes(dataValue reflect.Value) {
+	typeStmt := g.elemTypeStatement(dataValue)
+	elems := g.unprunedRecords(dataValue)
+	plural := strings.TrimPrefix(g.sliceName(), "All")
+
+	for _, fieldName := range g.DateIndexFields {
+		indexName := plural + "SortedBy" + fieldName
+		g.generateSortedIndex(indexName, typeStmt, fieldName, elems, lessTime)
+
+		// Group the records in chronological order
+		sorted := append([]reflect.Value(nil), elems...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return lessTime(sorted[i].FieldByName(fieldName), sorted[j].FieldByName(fieldName))
+		})
+		var years []int
+		var months []string
+		byYear := make(map[int][]string)
+		byMonth := make(map[string][]string)
+		for _, elem := range sorted {
+			t := g.groupTime(elem.FieldByName(fieldName).Interface().(time.Time))
+			year, month := t.Year(), t.Format("2006-01")
+			if _, ok := byYear[year]; !ok {
+				years = append(years, year)
+			}
+			if _, ok := byMonth[month]; !ok {
+				months = append(months, month)
+			}
+			byYear[year] = append(byYear[year], g.varName(elem))
+			byMonth[month] = append(byMonth[month], g.varName(elem))
+		}
+
+		yearName := plural + "By" + fieldName + "Year"
+		g.File.Commentf("%s groups the %s values by the year of their %s.", yearName, g.TypeName, fieldName)
+		g.File.Var().Id(yearName).Op("=").Map(jen.Int()).Index().Op("*").Add(typeStmt.Clone()).Values(jen.DictFunc(func(dict jen.Dict) {
+			for _, year := range years {
+				dict[jen.Lit(year)] = recordPointers(typeStmt, byYear[year])
+			}
+		}))
+
+		monthName := plural + "By" + fieldName + "YearMonth"
+		g.File.Commentf("%s groups the %s values by the year and month (YYYY-MM) of their %s.", monthName, g.TypeName, fieldName)
+		g.File.Var().Id(monthName).Op("=").Map(jen.String()).Index().Op("*").Add(typeStmt.Clone()).Values(jen.DictFunc(func(dict jen.Dict) {
+			for _, month := range months {
+				dict[jen.Lit(month)] = recordPointers(typeStmt, byMonth[month])
+			}
+		}))
+
+		// Binary search for the first record not before t and the first one after t
+		search := func(cond func(value *jen.Statement) *jen.Statement) *jen.Statement {
+			return jen.Qual("sort", "Search").Call(
+				jen.Len(jen.Id(indexName)),
+				jen.Func().Params(jen.Id("i").Int()).Bool().Block(
+					jen.Return(cond(jen.Id(indexName).Index(jen.Id("i")).Dot(fieldName))),
+				),
+			)
+		}
+
+		beforeName := plural + "With" + fieldName + "Before"
+		g.declareFunc(fmt.Sprintf("%s returns the %s values whose %s is before t, ordered by %s.", beforeName, g.TypeName, fieldName, fieldName)).Id(beforeName).Params(jen.Id("t").Qual("time", "Time")).Index().Op("*").Add(typeStmt.Clone()).Block(
+			jen.Id("end").Op(":=").Add(search(func(value *jen.Statement) *jen.Statement {
+				return jen.Op("!").Add(value).Dot("Before").Call(jen.Id("t"))
+			})),
+			jen.Return(jen.Id(indexName).Index(jen.Op(":").Id("end").Op(":").Id("end"))),
+		)
+
+		afterName := plural + "With" + fieldName + "After"
+		g.declareFunc(fmt.Sprintf("%s returns the %s values whose %s is after t, ordered by %s.", afterName, g.TypeName, fieldName, fieldName)).Id(afterName).Params(jen.Id("t").Qual("time", "Time")).Index().Op("*").Add(typeStmt.Clone()).Block(
+			jen.Id("start").Op(":=").Add(search(func(value *jen.Statement) *jen.Statement {
+				return value.Dot("After").Call(jen.Id("t"))
+			})),
+			jen.Return(jen.Id(indexName).Index(jen.Id("start").Op(":"))),
+		)
+	}
+}
+
+// recordPointers returns a slice literal of pointers to the named variables
+func recordPointers(typeStmt *jen.Statement, varNames []string) *jen.Statement {
+	return jen.Index().Op("*").Add(typeStmt.Clone()).ValuesFunc(func(group *jen.Group) {
+		for _, name := range varNames {
+			group.Op("&").Id(name)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDateIndexes tests the date grouping maps and archive helpers
+func TestDateIndexes(t *testing.T) {
+	posts := []Post{
+		{ID: "post-1", Title: "Spring", Date: time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "post-2", Title: "New Year", Date: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "post-3", Title: "Next Year", Date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{ID: "post-4", Title: "Late January", Date: time.Date(2023, 1, 30, 0, 0, 0, 0, time.UTC)},
+	}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithDateIndexes("Date"),
+	)
+	if err := generator.Generate(posts); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(dir + "/posts.go")
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	for _, want := range []string{
+		"var PostsSortedByDate = []*Post{&PostPost2, &PostPost4, &PostPost1, &PostPost3}",
+		"2023: []*Post{&PostPost2, &PostPost4, &PostPost1},",
+		`"2023-01": []*Post{&PostPost2, &PostPost4},`,
+		"func PostsWithDateBefore(t time.Time) []*Post {",
+		"func PostsWithDateAfter(t time.Time) []*Post {",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in generated code:\n%s", want, content)
+		}
+	}
+
+	generator = NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithDateIndexes("Title"),
+	)
+	var configErr ConfigError
+	if err := generator.Generate(posts); !errors.As(err, &configErr) || configErr.Option != "DateIndexFields" {
+		t.Errorf("Expected DateIndexFields error, got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package genstruct
+
+import (
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// enterPath appends a segment (e.g. ".Children" or "[3]") to the path of the
+// value being rendered until the returned func is called. Paths are only
+// tracked when MaxDepth is set.
+func (g *Generator) enterPath(segment string) func() {
+	if g.MaxDepth == 0 {
+		return func() {}
+	}
+	g.valuePath = append(g.valuePath, segment)
+	return func() { g.valuePath = g.valuePath[:len(g.valuePath)-1] }
+}
+
+// depthStatement returns nil when the value being rendered is nested deeper
+// than MaxDepth, and otherwise a placeholder to render instead of the value.
+// The first value too deep in each record fails Generate with a DepthError
+// naming its path.
+func (g *Generator) depthStatement() *jen.Statement {
+	if g.MaxDepth == 0 || len(g.valuePath) <= g.MaxDepth {
+		return nil
+	}
+	for _, err := range g.genErrors {
+		if depthErr, ok := err.(DepthError); ok && depthErr.Record == g.currentRecord {
+			return jen.Nil()
+		}
+	}
+	g.addGenError(DepthError{
+		Record:   g.currentRecord,
+		Path:     g.currentRecord + strings.Join(g.valuePath, ""),
+		MaxDepth: g.MaxDepth,
+	})
+	return jen.Nil()
+}
@@ -0,0 +1,53 @@
+package genstruct
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestMaxDepth tests that values nested deeper than MaxDepth fail generation
+// with an error naming the path of the first one
+func TestMaxDepth(t *testing.T) {
+	root := &CycleNode{ID: "root"}
+	node := root
+	for range 5000 {
+		node.Kids = []*CycleNode{{ID: "kid"}}
+		node = node.Kids[0]
+	}
+	nodes := []*CycleNode{root, {ID: "shallow", Next: &CycleNode{ID: "next"}}}
+
+	_, err := NewGenerator(
+		WithPackageName("nodes"),
+		WithOutputFile("nodes.go"),
+		WithMaxDepth(4),
+	).Render(nodes)
+	var depthErr DepthError
+	if !errors.As(err, &depthErr) {
+		t.Fatalf("Expected a DepthError, got %v", err)
+	}
+	if depthErr.Record != "CycleNodeRoot" || depthErr.Path != "CycleNodeRoot.Kids[0].Kids[0].ID" {
+		t.Errorf("Expected the path of the first value too deep, got %+v", depthErr)
+	}
+	if strings.Count(err.Error(), "nested deeper") != 1 {
+		t.Errorf("Expected one error for the deep record, got: %v", err)
+	}
+
+	if _, err := NewGenerator(
+		WithPackageName("nodes"),
+		WithOutputFile("nodes.go"),
+		WithMaxDepth(4),
+	).Render(nodes[1:]); err != nil {
+		t.Errorf("Expected records within the depth to render, got: %v", err)
+	}
+
+	var configErr ConfigError
+	_, err = NewGenerator(
+		WithPackageName("nodes"),
+		WithOutputFile("nodes.go"),
+		WithMaxDepth(-1),
+	).Render(nodes[1:])
+	if !errors.As(err, &configErr) || configErr.Option != "MaxDepth" {
+		t.Errorf("Expected MaxDepth ConfigError, got %v", err)
+	}
+}
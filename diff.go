@@ -0,0 +1,154 @@
+package genstruct
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around changes
+const diffContext = 3
+
+// lineEdit is a line kept (' '), removed ('-') or added ('+') by a diff
+type lineEdit struct {
+	op   byte
+	line string
+}
+
+// unifiedDiff returns the unified diff turning before into after, or an
+// empty string when they are equal
+func unifiedDiff(beforeName, afterName string, before, after []byte) string {
+	edits := diffLines(splitLines(string(before)), splitLines(string(after)))
+
+	// Group changes closer than twice the context into hunks of edit indices
+	type hunk struct{ start, end int }
+	var hunks []hunk
+	for i, edit := range edits {
+		if edit.op == ' ' {
+			continue
+		}
+		start, end := max(i-diffContext, 0), min(i+diffContext+1, len(edits))
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1].end {
+			hunks[len(hunks)-1].end = end
+		} else {
+			hunks = append(hunks, hunk{start, end})
+		}
+	}
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "--- %s\n+++ %s\n", beforeName, afterName)
+	beforeLine, afterLine, next := 0, 0, 0
+	for _, h := range hunks {
+		for ; next < h.start; next++ {
+			beforeLine, afterLine = advanceLines(edits[next].op, beforeLine, afterLine)
+		}
+		beforeCount, afterCount := 0, 0
+		for _, edit := range edits[h.start:h.end] {
+			beforeCount, afterCount = advanceLines(edit.op, beforeCount, afterCount)
+		}
+		fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(beforeLine, beforeCount), hunkRange(afterLine, afterCount))
+		for ; next < h.end; next++ {
+			edit := edits[next]
+			out.WriteByte(edit.op)
+			out.WriteString(strings.TrimSuffix(edit.line, "\n"))
+			out.WriteByte('\n')
+			beforeLine, afterLine = advanceLines(edit.op, beforeLine, afterLine)
+		}
+	}
+	return out.String()
+}
+
+// advanceLines counts the line of an edit on the sides it belongs to
+func advanceLines(op byte, before, after int) (int, int) {
+	if op != '+' {
+		before++
+	}
+	if op != '-' {
+		after++
+	}
+	return before, after
+}
+
+// hunkRange formats the range of a hunk following the lines before it, where
+// an empty range names the line it follows
+func hunkRange(linesBefore, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", linesBefore)
+	}
+	return fmt.Sprintf("%d,%d", linesBefore+1, count)
+}
+
+// splitLines splits text into lines keeping their line endings
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.SplitAfter(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines returns the shortest edit script turning a into b using the
+// Myers algorithm
+func diffLines(a, b []string) []lineEdit {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+
+	// Find the furthest reaching path for each number of differences
+search:
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Walk the path back from the end
+	var edits []lineEdit
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			edits = append(edits, lineEdit{' ', a[x-1]})
+			x, y = x-1, y-1
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, lineEdit{'+', b[y-1]})
+				y--
+			} else {
+				edits = append(edits, lineEdit{'-', a[x-1]})
+				x--
+			}
+		}
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
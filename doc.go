@@ -19,6 +19,26 @@
 //	    genstruct.WithOutputFile("blog.go"),
 //	)
 //	err := generator.Generate(posts, tags)
+//
+// The structgen tag names the source field holding the reference keys and may
+// be followed by comma-separated modifiers:
+//
+//	Tags []*Tag `structgen:"TagSlugs,ptr,match=Slug,strict"`
+//
+// ptr requires a *T, []*T or map[K]*T field, match=Field matches keys against Field
+// instead of the identifier fields, strict makes unresolved keys an error and
+// omitempty leaves the field unset when the source is empty. Unknown modifiers
+// are reported as a StructgenTagError.
+//
+// Output is deterministic: map entries, lookup maps and reference datasets
+// are ordered by key or type name rather than by map iteration, so the same
+// data and options always produce the same bytes. A golden test checks this on
+// every supported Go version.
+//
+// References are wired with package-level variable initializers rather than
+// init functions, so Go initializes them in dependency order across all files
+// of the package before any user init function runs. No init ordering
+// configuration is needed.
 package genstruct
 
 //go:generate gomarkdoc -o README.md -e .
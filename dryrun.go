@@ -0,0 +1,93 @@
+package genstruct
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// outputDiff is the unified diff of a generated Go file against the file on
+// disk
+type outputDiff struct {
+	path string
+	diff string
+}
+
+// outputDiffs renders the Go files and diffs them and the other captured
+// outputs against the existing files, returning the files that would change.
+// A missing file counts as empty.
+func (g *Generator) outputDiffs() ([]outputDiff, error) {
+	type output struct {
+		path string
+		file *jen.File
+	}
+	outputs := []output{{g.OutputFile, g.File}}
+	if g.FuncFile != nil {
+		outputs = append(outputs, output{g.FuncsFile, g.FuncFile})
+	}
+	for _, path := range slices.Sorted(maps.Keys(g.RefFiles)) {
+		outputs = append(outputs, output{path, g.RefFiles[path]})
+	}
+
+	var diffs []outputDiff
+	for _, out := range outputs {
+		files, err := g.renderSplit(out.path, out.file)
+		if err != nil {
+			return nil, err
+		}
+		stale, err := g.staleParts(out.path, len(files)-1)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			existing, err := g.readFile(g.resolvePath(f.path))
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+			if diff := unifiedDiff("a/"+f.path, "b/"+f.path, existing, f.src); diff != "" {
+				diffs = append(diffs, outputDiff{f.path, diff})
+			}
+		}
+		for _, f := range stale {
+			// Stale pieces of a split file would be removed
+			diffs = append(diffs, outputDiff{f.path, unifiedDiff("a/"+f.path, "b/"+f.path, f.src, nil)})
+		}
+	}
+	for _, path := range slices.Sorted(maps.Keys(g.captured)) {
+		existing, err := g.readFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		name := g.relativePath(path)
+		if diff := unifiedDiff("a/"+name, "b/"+name, existing, g.captured[path]); diff != "" {
+			diffs = append(diffs, outputDiff{name, diff})
+		}
+	}
+	return diffs, nil
+}
+
+// dryRun diffs the rendered and captured files against the existing files, storing the
+// diff in Diff and logging it instead of writing anything
+func (g *Generator) dryRun() error {
+	diffs, err := g.outputDiffs()
+	if err != nil {
+		return err
+	}
+	diff := &strings.Builder{}
+	for _, d := range diffs {
+		diff.WriteString(d.diff)
+	}
+	g.Diff = diff.String()
+
+	if g.Diff == "" {
+		g.Logger.Info("Dry run: generated code is unchanged", slog.String("output", g.OutputFile))
+	} else {
+		g.Logger.Info("Dry run: generated code would change", slog.String("output", g.OutputFile), slog.String("diff", g.Diff))
+	}
+	return nil
+}
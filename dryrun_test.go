@@ -0,0 +1,66 @@
+package genstruct
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDryRun tests that a dry run reports the diff against the existing
+// output without writing it
+func TestDryRun(t *testing.T) {
+	dir := t.TempDir()
+	tags := []Tag{{ID: "go", Name: "Go"}}
+	if err := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("tags.go"),
+		WithWorkingDir(dir),
+	).Generate(tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	before, err := os.ReadFile(filepath.Join(dir, "tags.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags[0].Name = "Golang"
+	generator := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("tags.go"),
+		WithWorkingDir(dir),
+		WithDryRun(),
+	)
+	if err := generator.Generate(tags); err != nil {
+		t.Fatalf("Error in dry run: %v", err)
+	}
+	for _, want := range []string{"--- a/tags.go\n+++ b/tags.go\n", "-\tName: \"Go\",\n", "+\tName: \"Golang\",\n"} {
+		if !strings.Contains(generator.Diff, want) {
+			t.Errorf("Expected diff to contain %q, got:\n%s", want, generator.Diff)
+		}
+	}
+	after, err := os.ReadFile(filepath.Join(dir, "tags.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Errorf("Expected the dry run not to write the output file")
+	}
+
+	// A new file is diffed against nothing
+	generator = NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("new.go"),
+		WithWorkingDir(dir),
+		WithDryRun(),
+	)
+	if err := generator.Generate(tags); err != nil {
+		t.Fatalf("Error in dry run: %v", err)
+	}
+	if !strings.Contains(generator.Diff, "@@ -0,0 +1,") {
+		t.Errorf("Expected a diff adding the whole file, got:\n%s", generator.Diff)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new.go")); !os.IsNotExist(err) {
+		t.Errorf("Expected the dry run not to create the output file, got %v", err)
+	}
+}
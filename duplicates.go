@@ -0,0 +1,154 @@
+package genstruct
+
+import (
+	"log/slog"
+	"reflect"
+)
+
+// DuplicateKind describes why a group of records is considered duplicated.
+type DuplicateKind string
+
+const (
+	// DuplicateContent marks records with identical content but different IDs.
+	DuplicateContent DuplicateKind = "content"
+	// DuplicateIdentifier marks records whose identifiers produce the same
+	// generated names, such as "Go Lang" and "go-lang".
+	DuplicateIdentifier DuplicateKind = "identifier"
+)
+
+// Duplicate describes a group of records within a dataset that look like
+// duplicates of each other.
+type Duplicate struct {
+	Kind    DuplicateKind // Why the records are considered duplicates
+	Type    string        // Name of the struct type of the records
+	Records []string      // Identifiers of the duplicated records
+}
+
+// FindDuplicates reports duplicate records within each of the given datasets.
+//
+// Records are content duplicates when all exported fields other than the ID
+// field are deeply equal, and identifier duplicates when their identifiers
+// (as used for variable naming) produce the same generated name.
+func (g *Generator) FindDuplicates(datasets ...any) []Duplicate {
+	var duplicates []Duplicate
+
+	for _, data := range datasets {
+		dataValue := reflect.ValueOf(g.unwrapPointer(data))
+		if (dataValue.Kind() != reflect.Slice && dataValue.Kind() != reflect.Array) ||
+			dataValue.Len() == 0 {
+			continue
+		}
+
+		first := dataValue.Index(0)
+		if first.Kind() == reflect.Pointer {
+			first = first.Elem()
+		}
+		if first.Kind() != reflect.Struct {
+			continue
+		}
+		typeName := first.Type().Name()
+		idFieldName := g.idFieldName(first)
+
+		var (
+			contentOrder []string
+			identOrder   []string
+			byContent    = make(map[string][]string)
+			byIdent      = make(map[string][]string)
+		)
+		for i := range dataValue.Len() {
+			elem := dataValue.Index(i)
+			if elem.Kind() == reflect.Pointer {
+				elem = elem.Elem()
+			}
+			identValue := g.getStructIdentifier(elem)
+
+			content := canonicalString(elem, idFieldName)
+			if _, ok := byContent[content]; !ok {
+				contentOrder = append(contentOrder, content)
+			}
+			byContent[content] = append(byContent[content], identValue)
+
+			ident := g.sanitizeIdentifier(identValue)
+			if _, ok := byIdent[ident]; !ok {
+				identOrder = append(identOrder, ident)
+			}
+			byIdent[ident] = append(byIdent[ident], identValue)
+		}
+
+		for _, content := range contentOrder {
+			if records := byContent[content]; len(records) > 1 {
+				duplicates = append(duplicates, Duplicate{
+					Kind:    DuplicateContent,
+					Type:    typeName,
+					Records: records,
+				})
+			}
+		}
+		for _, ident := range identOrder {
+			if records := byIdent[ident]; len(records) > 1 {
+				duplicates = append(duplicates, Duplicate{
+					Kind:    DuplicateIdentifier,
+					Type:    typeName,
+					Records: records,
+				})
+			}
+		}
+	}
+
+	return duplicates
+}
+
+// reportDuplicates logs a warning for every duplicate found in the primary
+// and reference datasets
+func (g *Generator) reportDuplicates() {
+	datasets := append([]any{g.Data}, g.refDatasets()...)
+
+	for _, duplicate := range g.FindDuplicates(datasets...) {
+		g.Logger.Warn(
+			"Duplicate records found",
+			slog.String("kind", string(duplicate.Kind)),
+			slog.String("type", duplicate.Type),
+			slog.Any("records", duplicate.Records),
+		)
+	}
+}
+
+// checkDuplicateNames reports records of the dataset that would be generated
+// under the same variable or ID constant name as an earlier record, which
+// leaves the generated file unable to compile. The collisions fail Generate
+// unless WarnDuplicateNames is set, in which case they are logged.
+func (g *Generator) checkDuplicateNames(dataValue reflect.Value) {
+	names := make(map[string]string)
+	check := func(name, record string) {
+		previous, ok := names[name]
+		if !ok {
+			names[name] = record
+			return
+		}
+		if g.WarnDuplicateNames {
+			g.Logger.Warn(
+				"Records generated under the same name",
+				slog.String("name", name),
+				slog.String("record", record),
+				slog.String("previous", previous),
+			)
+			return
+		}
+		g.addGenError(DuplicateNameError{Name: name, Record: record, Previous: previous})
+	}
+
+	for i := range dataValue.Len() {
+		elem := reflect.Indirect(dataValue.Index(i))
+		if !elem.IsValid() {
+			continue
+		}
+		record := g.getStructIdentifier(elem)
+		if !g.isPruned(elem) {
+			check(g.prefixedIdentifier(g.VarPrefix, elem), record)
+		}
+		// Constants are declared for string IDs only, as in generateConstants
+		if idField := elem.FieldByName(g.idFieldName(elem)); idField.IsValid() && idField.Kind() == reflect.String {
+			check(g.prefixedIdentifier(g.ConstantIdent, elem)+"ID", record)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package genstruct
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestFindDuplicates tests that content and identifier duplicates are reported
+func TestFindDuplicates(t *testing.T) {
+	tags := []Tag{
+		{ID: "tag-1", Name: "Go", Slug: "go"},
+		{ID: "tag-2", Name: "Go", Slug: "go"},
+		{ID: "tag-3", Name: "Go Lang", Slug: "go-lang"},
+		{ID: "tag-4", Name: "go lang", Slug: "golang"},
+	}
+
+	generator := NewGenerator(WithIdentifierFields([]string{"Name"}))
+	got := generator.FindDuplicates(tags)
+
+	want := []Duplicate{
+		{Kind: DuplicateContent, Type: "Tag", Records: []string{"Go", "Go"}},
+		{Kind: DuplicateIdentifier, Type: "Tag", Records: []string{"Go", "Go"}},
+		{Kind: DuplicateIdentifier, Type: "Tag", Records: []string{"Go Lang", "go lang"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDuplicates() = %+v, want %+v", got, want)
+	}
+}
+
+// TestDuplicateNames tests that records generated under the same variable or
+// ID constant name fail generation unless configured to warn
+func TestDuplicateNames(t *testing.T) {
+	tags := []Tag{
+		{ID: "tag-1", Name: "Go Lang", Slug: "go-lang"},
+		{ID: "tag-2", Name: "go lang", Slug: "golang"},
+		{ID: "tag-3", Name: "Rust", Slug: "rust"},
+	}
+
+	dir := t.TempDir()
+	err := NewGenerator(
+		WithPackageName("testdata"),
+		WithOutputFile("test_tags.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Name"}),
+	).Generate(tags)
+	if err == nil {
+		t.Fatal("Expected an error for records generated under the same name")
+	}
+	for _, want := range []string{
+		`records "Go Lang" and "go lang" are both generated as TagGoLang;`,
+		`records "Go Lang" and "go lang" are both generated as TagGoLangID;`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to contain %q, got: %v", want, err)
+		}
+	}
+	if !errors.As(err, new(DuplicateNameError)) {
+		t.Errorf("Expected a DuplicateNameError, got %T", err)
+	}
+
+	var logs bytes.Buffer
+	err = NewGenerator(
+		WithPackageName("testdata"),
+		WithOutputFile("test_tags.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Name"}),
+		WithDuplicateNameWarnings(),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+	).Generate(tags)
+	if err != nil {
+		t.Fatalf("Error generating code with warnings: %v", err)
+	}
+	if !strings.Contains(logs.String(), "Records generated under the same name") ||
+		!strings.Contains(logs.String(), "name=TagGoLangID") {
+		t.Errorf("Expected a warning for the duplicate names, got:\n%s", logs.String())
+	}
+}
+
+// TestDuplicateRefNames tests that records of reference datasets generated
+// under the same name fail generation
+func TestDuplicateRefNames(t *testing.T) {
+	tags := []Tag{
+		{ID: "tag-1", Name: "Go", Slug: "go"},
+		{ID: "tag-2", Name: "Go", Slug: "go"},
+	}
+	posts := []Post{
+		{ID: "post-1", Title: "Intro", TagSlugs: []string{"go"}},
+	}
+
+	err := NewGenerator(
+		WithPackageName("testdata"),
+		WithOutputFile("test_posts.go"),
+		WithWorkingDir(t.TempDir()),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+	).Generate(posts, tags)
+	if err == nil || !strings.Contains(err.Error(), "are both generated as TagGo") {
+		t.Errorf("Expected an error for reference records generated under the same name, got: %v", err)
+	}
+}
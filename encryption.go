@@ -0,0 +1,85 @@
+package genstruct
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"reflect"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// isEncryptedField reports whether the field should be encrypted at generation time
+func (g *Generator) isEncryptedField(field reflect.StructField) bool {
+	if field.Type.Kind() != reflect.String {
+		return false
+	}
+	for _, name := range g.EncryptedFields {
+		if name == field.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// newEncryptionAEAD creates the AES-GCM cipher used to encrypt fields
+func (g *Generator) newEncryptionAEAD() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(g.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptString encrypts a string with AES-GCM and returns the base64 encoded
+// nonce followed by the ciphertext.
+//
+// The nonce is derived from an HMAC of the plaintext so regenerating the same
+// data produces the same output.
+func (g *Generator) encryptString(plaintext string) string {
+	aead, err := g.newEncryptionAEAD()
+	if err != nil {
+		// The key is validated before generation starts
+		panic(err)
+	}
+
+	mac := hmac.New(sha256.New, g.EncryptionKey)
+	mac.Write([]byte(plaintext))
+	nonce := mac.Sum(nil)[:aead.NonceSize()]
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed)
+}
+
+// generateDecryptFunc emits the Decrypt accessor used at runtime to recover
+// the plaintext of encrypted fields:
+//
+//	func Decrypt(key []byte, ciphertext string) (string, error)
+func (g *Generator) generateDecryptFunc() {
+	g.declareFunc(
+		"Decrypt decrypts a field value that was encrypted with AES-GCM at generation time.",
+		"The key must be the same key that was passed to genstruct.WithEncryptedFields.",
+	).Id("Decrypt").Params(
+		jen.Id("key").Index().Byte(),
+		jen.Id("ciphertext").String(),
+	).Params(jen.String(), jen.Error()).Block(
+		jen.List(jen.Id("sealed"), jen.Err()).Op(":=").Qual("encoding/base64", "StdEncoding").Dot("DecodeString").Call(jen.Id("ciphertext")),
+		jen.If(jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Lit(""), jen.Err())),
+		jen.List(jen.Id("block"), jen.Err()).Op(":=").Qual("crypto/aes", "NewCipher").Call(jen.Id("key")),
+		jen.If(jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Lit(""), jen.Err())),
+		jen.List(jen.Id("aead"), jen.Err()).Op(":=").Qual("crypto/cipher", "NewGCM").Call(jen.Id("block")),
+		jen.If(jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Lit(""), jen.Err())),
+		jen.If(jen.Len(jen.Id("sealed")).Op("<").Id("aead").Dot("NonceSize").Call()).Block(
+			jen.Return(jen.Lit(""), jen.Qual("errors", "New").Call(jen.Lit("ciphertext too short"))),
+		),
+		jen.List(jen.Id("nonce"), jen.Id("data")).Op(":=").List(
+			jen.Id("sealed").Index(jen.Empty(), jen.Id("aead").Dot("NonceSize").Call()),
+			jen.Id("sealed").Index(jen.Id("aead").Dot("NonceSize").Call(), jen.Empty()),
+		),
+		jen.List(jen.Id("plaintext"), jen.Err()).Op(":=").Id("aead").Dot("Open").Call(jen.Nil(), jen.Id("nonce"), jen.Id("data"), jen.Nil()),
+		jen.If(jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Lit(""), jen.Err())),
+		jen.Return(jen.String().Call(jen.Id("plaintext")), jen.Nil()),
+	)
+}
@@ -0,0 +1,131 @@
+package genstruct
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestEncryptedFields tests that selected fields are encrypted and can be decrypted
+func TestEncryptedFields(t *testing.T) {
+	type Article struct {
+		ID   string
+		Body string
+	}
+	articles := []Article{{ID: "intro", Body: "proprietary content"}}
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("articles"),
+		WithOutputFile("articles.go"),
+		WithWorkingDir(dir),
+		WithEncryptedFields(key, "Body"),
+	)
+	if err := generator.Generate(articles); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "articles.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	if strings.Contains(string(content), "proprietary content") {
+		t.Error("Expected encrypted field to not contain the plaintext")
+	}
+	if !strings.Contains(string(content), "func Decrypt(key []byte, ciphertext string) (string, error)") {
+		t.Error("Expected Decrypt accessor in generated code")
+	}
+
+	// Decrypt the same way the generated accessor does
+	sealed, err := base64.StdEncoding.DecodeString(generator.encryptString("proprietary content"))
+	if err != nil {
+		t.Fatalf("Error decoding ciphertext: %v", err)
+	}
+	block, _ := aes.NewCipher(key)
+	aead, _ := cipher.NewGCM(block)
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil || string(plaintext) != "proprietary content" {
+		t.Errorf("Expected to decrypt plaintext, got %q (%v)", plaintext, err)
+	}
+
+	// Invalid keys are rejected before generation
+	generator = NewGenerator(
+		WithPackageName("articles"),
+		WithOutputFile("articles.go"),
+		WithWorkingDir(dir),
+		WithEncryptedFields([]byte("short"), "Body"),
+	)
+	if err := generator.Generate(articles); err == nil {
+		t.Error("Expected error for invalid encryption key, got nil")
+	}
+}
+
+// TestEncryptedFieldsDecrypt tests that the generated Decrypt recovers the
+// plaintext of encrypted fields when compiled
+func TestEncryptedFieldsDecrypt(t *testing.T) {
+	type Article struct {
+		ID   string
+		Body string
+	}
+	articles := []Article{{ID: "intro", Body: "proprietary content\nwith a second line"}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("main"),
+		WithOutputFile("articles.go"),
+		WithWorkingDir(dir),
+		WithEncryptedFields([]byte("0123456789abcdef0123456789abcdef"), "Body"),
+	)
+	if err := generator.Generate(articles); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+	files := map[string]string{
+		"go.mod": "module articles\n\ngo 1.24\n",
+		"types.go": `package main
+
+type Article struct {
+	ID   string
+	Body string
+}
+`,
+		"main.go": `package main
+
+func main() {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	body, err := Decrypt(key, ArticleIntro.Body)
+	if err != nil {
+		panic(err)
+	}
+	if body != "proprietary content\nwith a second line" {
+		panic("decrypted body " + body + " does not match the plaintext")
+	}
+	if _, err := Decrypt([]byte("fedcba9876543210fedcba9876543210"), ArticleIntro.Body); err == nil {
+		panic("decrypting with the wrong key succeeded")
+	}
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Error writing %s: %v", name, err)
+		}
+	}
+
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Generated program failed: %v\n%s", err, output)
+	}
+}
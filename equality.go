@@ -0,0 +1,129 @@
+package genstruct
+
+import (
+	"bytes"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// equalityTestFile returns the path of the generated equality test, next to
+// the output file (e.g. posts.go -> posts_equal_test.go)
+func (g *Generator) equalityTestFile() string {
+	return strings.TrimSuffix(g.OutputFile, ".go") + "_equal_test.go"
+}
+
+// equalityIgnoredFields returns the fields whose generated values legitimately
+// differ from the source: structgen references and encrypted fields
+func (g *Generator) equalityIgnoredFields(structType reflect.Type) []string {
+	var fields []string
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if _, ok := field.Tag.Lookup("structgen"); ok || slices.Contains(g.EncryptedFields, field.Name) {
+			fields = append(fields, field.Name)
+		}
+	}
+	return fields
+}
+
+// equalityEmptyFields returns the slice and map fields compared by contents
+// only when empty, since nil values are generated as empty literals
+func (g *Generator) equalityEmptyFields(structType reflect.Type) []string {
+	ignored := g.equalityIgnoredFields(structType)
+	var fields []string
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		if !field.IsExported() || slices.Contains(ignored, field.Name) {
+			continue
+		}
+		if field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Map {
+			fields = append(fields, field.Name)
+		}
+	}
+	return fields
+}
+
+// writeEqualityTest writes a test comparing each generated record of the
+// primary dataset against the source record it was generated from
+func (g *Generator) writeEqualityTest(dataValue reflect.Value) error {
+	elemType := dataValue.Type().Elem()
+	isPointer := elemType.Kind() == reflect.Pointer
+	if isPointer {
+		elemType = elemType.Elem()
+	}
+
+	source := jen.Id(g.EqualityTestVar)
+	if g.EqualityTestSource != "" {
+		source = jen.Qual(g.EqualityTestSource, g.EqualityTestVar)
+	}
+	want := jen.Add(source).Index(jen.Id("record").Dot("index"))
+	if isPointer {
+		want = jen.Op("*").Add(want)
+	}
+
+	testName := "Test" + strings.TrimPrefix(g.sliceName(), "All") + "MatchSource"
+	file := jen.NewFile(g.PackageName)
+	g.writeHeader(file)
+	file.Commentf("%s checks that each generated %s equals the source record it was", testName, elemType.Name())
+	file.Comment("generated from, ignoring fields populated from references or encrypted.")
+	file.Func().Id(testName).Params(jen.Id("t").Op("*").Qual("testing", "T")).BlockFunc(func(group *jen.Group) {
+		group.Id("records").Op(":=").Index().Struct(
+			jen.Id("index").Int(),
+			jen.Id("got").Add(g.elemTypeStatement(dataValue)),
+		).ValuesFunc(func(records *jen.Group) {
+			for i := range dataValue.Len() {
+				elem := dataValue.Index(i)
+				if g.isPruned(elem) {
+					continue
+				}
+				records.Values(jen.Lit(g.sourceIndex(dataValue, i)), jen.Id(g.varName(elem)))
+			}
+		})
+		group.For(jen.List(jen.Id("_"), jen.Id("record")).Op(":=").Range().Id("records")).BlockFunc(func(loop *jen.Group) {
+			loop.List(jen.Id("want"), jen.Id("got")).Op(":=").List(want, jen.Id("record").Dot("got"))
+			for _, name := range g.equalityIgnoredFields(elemType) {
+				loop.Id("got").Dot(name).Op("=").Id("want").Dot(name)
+			}
+			// Nil slices and maps are generated as empty literals
+			for _, name := range g.equalityEmptyFields(elemType) {
+				loop.If(jen.Len(jen.Id("want").Dot(name)).Op("==").Lit(0).Op("&&").Len(jen.Id("got").Dot(name)).Op("==").Lit(0)).Block(
+					jen.Id("got").Dot(name).Op("=").Id("want").Dot(name),
+				)
+			}
+			// Records without an ID are generated with a synthetic one
+			if idFieldName := g.idFieldName(dataValue.Index(0)); idFieldName != "" {
+				if field, ok := elemType.FieldByName(idFieldName); ok && field.Type.Kind() == reflect.String {
+					loop.If(jen.Id("want").Dot(idFieldName).Op("==").Lit("")).Block(
+						jen.Id("got").Dot(idFieldName).Op("=").Id("want").Dot(idFieldName),
+					)
+				}
+			}
+			loop.If(jen.Op("!").Qual("reflect", "DeepEqual").Call(jen.Id("want"), jen.Id("got"))).Block(
+				jen.Id("t").Dot("Errorf").Call(
+					jen.Lit("record %d differs from its source:\ngot:  %+v\nwant: %+v"),
+					jen.Id("record").Dot("index"),
+					jen.Id("got"),
+					jen.Id("want"),
+				),
+			)
+		})
+	})
+
+	buf := &bytes.Buffer{}
+	if err := file.Render(buf); err != nil {
+		g.Logger.Error("Failed to render equality test", "error", err)
+		return err
+	}
+
+	g.Logger.Debug(
+		"Writing equality test to file",
+		slog.String("file", g.equalityTestFile()),
+	)
+	return g.writeFile(g.resolvePath(g.equalityTestFile()), buf.Bytes())
+}
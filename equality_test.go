@@ -0,0 +1,62 @@
+package genstruct
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestEqualityTest tests that the generated equality test compares every
+// unpruned record against its source, ignoring structgen fields
+func TestEqualityTest(t *testing.T) {
+	type Tag struct {
+		ID   string
+		Name string
+	}
+	type Post struct {
+		ID       string
+		Title    string
+		TagSlugs []string
+		Tags     []*Tag `structgen:"TagSlugs"`
+	}
+	posts := []Post{
+		{ID: "post-1", Title: "Hello", TagSlugs: []string{"go"}},
+		{ID: "post-2", Title: "World"},
+	}
+	tags := []Tag{{ID: "go", Name: "Go"}}
+
+	dir := t.TempDir()
+	err := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithEqualityTest("example.com/blog/data", "Posts"),
+	).Generate(posts, tags)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "posts_equal_test.go"))
+	if err != nil {
+		t.Fatalf("Error reading equality test: %v", err)
+	}
+	output := string(content)
+
+	expected := []string{
+		"package blog",
+		`data "example.com/blog/data"`,
+		"func TestPostsMatchSource(t *testing.T) {",
+		"{0, PostPost1}",
+		"{1, PostPost2}",
+		"want, got := data.Posts[record.index], record.got",
+		"got.Tags = want.Tags",
+		"if len(want.TagSlugs) == 0 && len(got.TagSlugs) == 0 {",
+		"reflect.DeepEqual(want, got)",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected equality test to contain %q", exp)
+		}
+	}
+}
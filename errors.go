@@ -3,6 +3,7 @@ package genstruct
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 // NonSliceOrArrayError is returned when the data is not a slice or array.
@@ -38,3 +39,366 @@ func (e InvalidTypeError) Error() string {
 		e.Kind,
 	)
 }
+
+// InvalidLangVersionError is returned when the configured Go language version
+// cannot be parsed.
+type InvalidLangVersionError struct {
+	Version string
+}
+
+// Error returns the error message
+func (e InvalidLangVersionError) Error() string {
+	return fmt.Sprintf(
+		"invalid Go language version %q, expected a version like \"1.17\" or \"go1.21\"",
+		e.Version,
+	)
+}
+
+// ExternalPathError is returned when the output file resolves outside of the
+// current module or workspace.
+type ExternalPathError struct {
+	Path string
+	Root string
+}
+
+// Error returns the error message
+func (e ExternalPathError) Error() string {
+	return fmt.Sprintf(
+		"output file %s is outside of the module root %s, use WithAllowExternalPath to allow it",
+		e.Path,
+		e.Root,
+	)
+}
+
+// ModuleRequirementError is returned when the output file is written into a
+// module that neither requires the module providing the data types nor shares
+// a go.work workspace with it.
+type ModuleRequirementError struct {
+	Package      string
+	TargetModule string
+	GoMod        string
+}
+
+// Error returns the error message
+func (e ModuleRequirementError) Error() string {
+	return fmt.Sprintf(
+		"package %s is not provided by module %s, its requirements or its workspace (%s); "+
+			"run `go get %s` in the target module, add a replace directive or go.work use directive, "+
+			"or use WithModulePathRewrite to map it to an importable path",
+		e.Package,
+		e.TargetModule,
+		e.GoMod,
+		e.Package,
+	)
+}
+
+// InvalidRecordError is returned when a Record passed to GenerateRecords
+// cannot be declared.
+type InvalidRecordError struct {
+	Name   string
+	Reason string
+}
+
+// Error returns the error message
+func (e InvalidRecordError) Error() string {
+	return fmt.Sprintf("invalid record %q: %s", e.Name, e.Reason)
+}
+
+// StructgenTagError is returned when a structgen tag cannot be parsed.
+type StructgenTagError struct {
+	Field  string
+	Tag    string
+	Reason string
+}
+
+// Error returns the error message
+func (e StructgenTagError) Error() string {
+	return fmt.Sprintf("invalid structgen tag %q on field %s: %s", e.Tag, e.Field, e.Reason)
+}
+
+// UnresolvedReferenceError is returned when a strict structgen field refers
+// to a key without a matching reference record.
+type UnresolvedReferenceError struct {
+	Record string
+	Field  string
+	Key    string
+}
+
+// Error returns the error message
+func (e UnresolvedReferenceError) Error() string {
+	return fmt.Sprintf("record %s: field %s references unknown key %q", e.Record, e.Field, e.Key)
+}
+
+// PrunedReferenceError is returned when a record references a record left out
+// with WithPrunedRecords, whose variable is not generated.
+type PrunedReferenceError struct {
+	Record   string
+	Variable string
+}
+
+// Error returns the error message
+func (e PrunedReferenceError) Error() string {
+	return fmt.Sprintf("record %s references pruned record %s; remove it from WithPrunedRecords", e.Record, e.Variable)
+}
+
+// ConfigError is returned when a generator setting is invalid or contradicts
+// another setting.
+type ConfigError struct {
+	Option string
+	Value  string
+	Reason string
+}
+
+// Error returns the error message
+func (e ConfigError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("invalid %s: %s", e.Option, e.Reason)
+	}
+	return fmt.Sprintf("invalid %s %q: %s", e.Option, e.Value, e.Reason)
+}
+
+// ManifestError is returned when a generated file doesn't match its Manifest.
+type ManifestError struct {
+	Path     string
+	Problems []string
+}
+
+// Error returns the error message
+func (e ManifestError) Error() string {
+	return fmt.Sprintf("%s does not match manifest: %s", e.Path, strings.Join(e.Problems, "; "))
+}
+
+// SlugError is returned when records have slugs that are not URL-safe or not
+// unique within their route namespace.
+type SlugError struct {
+	Violations []SlugViolation
+}
+
+// Error returns the error message
+func (e SlugError) Error() string {
+	problems := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		problems = append(problems, fmt.Sprintf("%s %s: %s %q %s", v.Type, v.Record, v.Field, v.Slug, v.Reason))
+	}
+	return fmt.Sprintf("invalid slugs: %s", strings.Join(problems, "; "))
+}
+
+// ExplainTargetError is returned when the target passed to Explain does not
+// name a structgen field of a dataset.
+type ExplainTargetError struct {
+	Target string
+	Reason string
+}
+
+// Error returns the error message
+func (e ExplainTargetError) Error() string {
+	return fmt.Sprintf("cannot explain %q: %s", e.Target, e.Reason)
+}
+
+// SchemaChangedError is returned when the fields of a generated type changed
+// without bumping the pinned schema version.
+type SchemaChangedError struct {
+	Type     string
+	Version  string
+	Previous string
+	Current  string
+}
+
+// Error returns the error message
+func (e SchemaChangedError) Error() string {
+	return fmt.Sprintf(
+		"schema of %s changed under version %s (was %q, now %q); bump the version passed to WithSchemaVersion to acknowledge it",
+		e.Type,
+		e.Version,
+		e.Previous,
+		e.Current,
+	)
+}
+
+// ReservedNameError is returned when a generated symbol would be declared
+// under a predeclared Go identifier or a name listed in ReservedNames.
+type ReservedNameError struct {
+	Name string
+}
+
+// Error returns the error message
+func (e ReservedNameError) Error() string {
+	return fmt.Sprintf("generated symbol %s collides with a reserved name; rename the record or use WithRenameReserved", e.Name)
+}
+
+// VendorTypeError is returned when a struct value from another package cannot
+// be rendered faithfully with the configured package rendering.
+type VendorTypeError struct {
+	Type   string
+	Record string
+	Reason string
+}
+
+// Error returns the error message
+func (e VendorTypeError) Error() string {
+	return fmt.Sprintf(
+		"cannot render %s value in %s: %s; configure the package with WithPackageConstructor or WithPackageLiterals",
+		e.Type,
+		e.Record,
+		e.Reason,
+	)
+}
+
+// LoadError is returned when a file loaded from an fs.FS cannot be decoded.
+type LoadError struct {
+	Path string
+	Err  error
+}
+
+// Error returns the error message
+func (e LoadError) Error() string {
+	return fmt.Sprintf("cannot decode %s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying decode error
+func (e LoadError) Unwrap() error {
+	return e.Err
+}
+
+// UnsupportedValueError is returned when a record holds a value that can't be
+// written as Go code, such as a non-nil func or channel.
+type UnsupportedValueError struct {
+	Record string // Variable name of the record holding the value
+	Type   string // Type of the value
+}
+
+// Error returns the error message
+func (e UnsupportedValueError) Error() string {
+	return "record " + e.Record + " holds a non-nil " + e.Type + " value, which can't be generated"
+}
+
+// ValueTypeError is returned when a record of a ValuesSource doesn't hold a
+// value of the dataset's element type.
+type ValueTypeError struct {
+	Index int
+	Value reflect.Value
+	Want  reflect.Type
+}
+
+// Error returns the error message
+func (e ValueTypeError) Error() string {
+	if !e.Value.IsValid() {
+		return fmt.Sprintf("record %d is an invalid value, want %s", e.Index, e.Want)
+	}
+	return fmt.Sprintf("record %d has type %s, want %s", e.Index, e.Value.Type(), e.Want)
+}
+
+// StaleOutputError is returned by Verify when generated files differ from the
+// code the data and configuration generate now.
+type StaleOutputError struct {
+	Files []string // Paths of the stale files, as configured
+	Diff  string   // Unified diff from the files on disk to the expected code
+}
+
+// Error returns the error message
+func (e StaleOutputError) Error() string {
+	return fmt.Sprintf("generated code is stale, re-run generation: %s", strings.Join(e.Files, ", "))
+}
+
+// CSVFieldError is returned when a CSV cell cannot be converted to the type
+// of the field its column maps to.
+type CSVFieldError struct {
+	Line   int
+	Column string
+	Value  string
+	Err    error
+}
+
+// Error returns the error message
+func (e CSVFieldError) Error() string {
+	return fmt.Sprintf("line %d, column %s: cannot convert %q: %v", e.Line, e.Column, e.Value, e.Err)
+}
+
+// Unwrap returns the underlying conversion error
+func (e CSVFieldError) Unwrap() error {
+	return e.Err
+}
+
+// TargetFailure is a target of RunTargets that failed to generate.
+type TargetFailure struct {
+	Target string
+	Err    error
+}
+
+// TargetsError is returned by RunTargets when targets failed or were skipped
+// after a failure.
+type TargetsError struct {
+	Failures []TargetFailure
+	Skipped  []string
+}
+
+// Error returns the error message
+func (e TargetsError) Error() string {
+	problems := make([]string, 0, len(e.Failures)+1)
+	for _, f := range e.Failures {
+		problems = append(problems, fmt.Sprintf("%s: %v", f.Target, f.Err))
+	}
+	if len(e.Skipped) > 0 {
+		problems = append(problems, "skipped "+strings.Join(e.Skipped, ", "))
+	}
+	return fmt.Sprintf("%d targets failed: %s", len(e.Failures), strings.Join(problems, "; "))
+}
+
+// Unwrap returns the errors of the failed targets
+func (e TargetsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		errs = append(errs, f.Err)
+	}
+	return errs
+}
+
+// AssetHashError is returned when an asset named by a path field configured
+// with WithAssetHash cannot be read.
+type AssetHashError struct {
+	Record string
+	Path   string
+	Err    error
+}
+
+// Error returns the error message
+func (e AssetHashError) Error() string {
+	return fmt.Sprintf("record %s: cannot hash asset %s: %v", e.Record, e.Path, e.Err)
+}
+
+// Unwrap returns the underlying read error
+func (e AssetHashError) Unwrap() error {
+	return e.Err
+}
+
+// DuplicateNameError is returned when two records of a dataset would be
+// generated under the same variable or ID constant name, or two map keys
+// under the same key constant name.
+type DuplicateNameError struct {
+	Name     string
+	Record   string
+	Previous string
+}
+
+// Error returns the error message
+func (e DuplicateNameError) Error() string {
+	return fmt.Sprintf(
+		"records %q and %q are both generated as %s; rename a record, set WithIdentifierFields, or use WithCollisionPolicy to rename it",
+		e.Previous,
+		e.Record,
+		e.Name,
+	)
+}
+
+// DepthError is returned when a record holds values nested deeper than the
+// limit set with WithMaxDepth.
+type DepthError struct {
+	Record   string
+	Path     string
+	MaxDepth int
+}
+
+// Error returns the error message
+func (e DepthError) Error() string {
+	return fmt.Sprintf("record %s: value at %s is nested deeper than the maximum depth of %d", e.Record, e.Path, e.MaxDepth)
+}
@@ -0,0 +1,107 @@
+package genstruct
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// exampleFile returns the path of the godoc example file written next to the
+// output file (e.g. animals_example_test.go for animals.go), so that it never
+// overwrites a hand-written example_test.go
+func (g *Generator) exampleFile() string {
+	return strings.TrimSuffix(g.OutputFile, ".go") + "_example_test.go"
+}
+
+// writeExampleFile writes the godoc example file next to the output file that
+// demonstrates accessing a generated variable, looking up a record by its ID
+// constant, and iterating over the All-slice.
+func (g *Generator) writeExampleFile(dataValue reflect.Value) error {
+	file := jen.NewFile(g.PackageName)
+	g.writeHeader(file)
+
+	sliceName := g.sliceName()
+	first := dataValue.Index(0)
+	if first.Kind() == reflect.Pointer {
+		first = first.Elem()
+	}
+
+	// Example of accessing a single generated variable
+	varName := g.varName(first)
+	if fieldName := g.exampleFieldName(first); fieldName != "" {
+		file.Comment(fmt.Sprintf("This example shows how to access a single generated %s.", g.TypeName))
+		file.Func().Id("Example_variable").Params().Block(
+			jen.Qual("fmt", "Println").Call(jen.Id(varName).Dot(fieldName)),
+			jen.Comment(exampleOutput(first.FieldByName(fieldName).String())),
+		)
+		file.Line()
+	}
+
+	// Example of looking up a record by its generated ID constant
+	idFieldName := g.idFieldName(first)
+	if idFieldName != "" &&
+		first.FieldByName(idFieldName).Kind() == reflect.String &&
+		first.FieldByName(idFieldName).String() != "" {
+		idValue := first.FieldByName(idFieldName).String()
+		constName := g.prefixedIdentifier(g.ConstantIdent, first) + "ID"
+		file.Comment(fmt.Sprintf("This example shows how to look up a %s by its ID constant.", g.TypeName))
+		file.Func().Id("Example_lookup").Params().Block(
+			jen.For(jen.List(jen.Id("_"), jen.Id("item")).Op(":=").Range().Id(sliceName)).Block(
+				jen.If(jen.Id("item").Dot(idFieldName).Op("==").Id(constName)).Block(
+					jen.Qual("fmt", "Println").Call(jen.Id("item").Dot(idFieldName)),
+				),
+			),
+			jen.Comment(exampleOutput(idValue)),
+		)
+		file.Line()
+	}
+
+	// Example of iterating over the All-slice
+	file.Comment(fmt.Sprintf("This example shows how to iterate over all generated %s values.", g.TypeName))
+	file.Func().Id("Example_all").Params().Block(
+		jen.Id("count").Op(":=").Lit(0),
+		jen.For(jen.Range().Id(sliceName)).Block(
+			jen.Id("count").Op("++"),
+		),
+		jen.Qual("fmt", "Println").Call(jen.Id("count")),
+		jen.Comment(exampleOutput(fmt.Sprint(dataValue.Len()))),
+	)
+
+	buf := &bytes.Buffer{}
+	if err := file.Render(buf); err != nil {
+		g.Logger.Error("Failed to render example file", "error", err)
+		return err
+	}
+
+	examplePath := g.resolvePath(g.exampleFile())
+	g.Logger.Debug(
+		"Writing example file",
+		slog.String("file", examplePath),
+	)
+	return g.writeFile(examplePath, buf.Bytes())
+}
+
+// exampleFieldName returns the first non-empty string identifier field of the
+// struct, used to print a readable value in the variable example
+func (g *Generator) exampleFieldName(structValue reflect.Value) string {
+	for _, fieldName := range g.identifierFields(structValue.Type()) {
+		field := structValue.FieldByName(fieldName)
+		if field.IsValid() && field.Kind() == reflect.String && field.String() != "" {
+			return fieldName
+		}
+	}
+	return ""
+}
+
+// exampleOutput formats printed text as an example "Output:" comment
+func exampleOutput(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("// "+line, " ")
+	}
+	return "// Output:\n" + strings.Join(lines, "\n")
+}
@@ -0,0 +1,66 @@
+package genstruct
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestExampleFile tests that godoc examples are written next to the output
+// file without overwriting hand-written examples
+func TestExampleFile(t *testing.T) {
+	type Animal struct {
+		ID   string
+		Name string
+	}
+
+	animals := []Animal{
+		{ID: "lion-001", Name: "Leo"},
+		{ID: "tiger-002", Name: "Stripes"},
+	}
+
+	dir := t.TempDir()
+	handWritten := []byte("package zoo\n")
+	if err := os.WriteFile(filepath.Join(dir, "example_test.go"), handWritten, 0644); err != nil {
+		t.Fatal(err)
+	}
+	generator := NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile(filepath.Join(dir, "animals.go")),
+		WithAllowExternalPath(),
+		WithExampleFile(),
+	)
+	if err := generator.Generate(animals); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "animals_example_test.go"))
+	if err != nil {
+		t.Fatalf("Error reading example file: %v", err)
+	}
+
+	expected := []string{
+		"func Example_variable()",
+		"fmt.Println(AnimalLion001.ID)",
+		"func Example_lookup()",
+		"item.ID == AnimalLion001ID",
+		"func Example_all()",
+		"range AllAnimals",
+		"// Output:\n\t// 2",
+	}
+	for _, want := range expected {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in example file", want)
+		}
+	}
+
+	// Hand-written examples next to the output file are left alone
+	content, err = os.ReadFile(filepath.Join(dir, "example_test.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != string(handWritten) {
+		t.Errorf("Expected the hand-written example_test.go to be kept, got:\n%s", content)
+	}
+}
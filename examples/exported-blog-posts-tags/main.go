@@ -12,6 +12,9 @@ import (
 	"github.com/conneroisu/genstruct/examples/exported-blog-posts-tags/pkg"
 )
 
+// outPath is the output file, relative to the example directory
+const outPath = "./out/blog_generated.go"
+
 // generateBlogData generates the static blog data file in the out package
 func generateBlogData() error {
 	// Define our blog data in the pkg package
@@ -19,8 +22,6 @@ func generateBlogData() error {
 	tags := pkg.Tags
 
 	// Configure and generate for both post and tag data in one step
-	// Use absolute path for the output file
-	outPath := "./out/blog_generated.go"
 	gen := genstruct.NewGenerator(
 		genstruct.WithOutputFile(outPath),
 		genstruct.WithIdentifierFields([]string{"Slug", "ID"}),
@@ -47,7 +48,6 @@ func main() {
 	fmt.Println("Successfully generated static blog post data in ./out/blog_generated.go")
 
 	// Show the content of the generated file
-	outPath := "/home/connerohnesorge/Documents/001Repos/genstruct/examples/exported-blog-posts-tags/out/blog_generated.go"
 	content, err := os.ReadFile(outPath)
 	if err != nil {
 		fmt.Printf("Error reading generated file: %v\n", err)
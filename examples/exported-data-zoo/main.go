@@ -17,9 +17,9 @@ func generateAnimalData() error {
 	// Define our array of animal data
 
 	// Create a generator with functional options
-	// Note: ExportDataMode is inferred automatically from the output file path
+	// Note: export mode is inferred from the output file path unless set with genstruct.WithExportMode
 	generator := genstruct.NewGenerator(
-		genstruct.WithOutputFile("./out/zoo_animals.go"),       // Output file name (absolute path from project root)
+		genstruct.WithOutputFile("./out/zoo_animals.go"),            // Output file name (absolute path from project root)
 		genstruct.WithIdentifierFields([]string{"Name", "Species"}), // Fields to use for naming variables
 	)
 
@@ -18,7 +18,7 @@ func generateAnimalData() error {
 	// Define our array of animal data
 
 	// Create a generator with functional options
-	// Note: ExportDataMode is inferred automatically from the output file path
+	// Note: export mode is inferred from the output file path unless set with genstruct.WithExportMode
 	generator := genstruct.NewGenerator(
 		genstruct.WithPackageName("out"),                      // Target package name
 		genstruct.WithTypeName("Animal"),                      // The struct type name
@@ -69,6 +69,7 @@ func TestCircusGeneration(t *testing.T) {
 	generator := genstruct.NewGenerator(
 		genstruct.WithPackageName("circus_test"),
 		genstruct.WithOutputFile(tempFile.Name()),
+		genstruct.WithAllowExternalPath(),
 		genstruct.WithVarPrefix("Test"),
 		genstruct.WithConstantIdent("Test"),
 		genstruct.WithLogger(logger),
@@ -3,6 +3,8 @@ package main
 import (
 	"os"
 	"testing"
+
+	"github.com/conneroisu/genstruct"
 )
 
 func TestPointerSlicesGeneration(t *testing.T) {
@@ -26,10 +28,8 @@ func TestPointerSlicesGeneration(t *testing.T) {
 		t.Fatalf("Generated file was not created: %v", err)
 	}
 
-	// Note: In a real-world scenario, you would load and test the generated file.
-	// For this example, we're just verifying that the file was created
-	// successfully, as importing the generated file would create a circular
-	// dependency in the test.
+	// Note: Importing the generated file would create a circular dependency in
+	// the test, so its declarations are verified by parsing it instead.
 
 	t.Run("File_exists", func(t *testing.T) {
 		_, err := os.Stat("articles_generated.go")
@@ -37,6 +37,20 @@ func TestPointerSlicesGeneration(t *testing.T) {
 			t.Fatalf("Generated file does not exist: %v", err)
 		}
 	})
+
+	t.Run("Declarations", func(t *testing.T) {
+		err := genstruct.VerifyGeneratedFile("articles_generated.go", genstruct.Manifest{
+			Package:   "main",
+			Constants: []string{"ArticleArticle001ID", "AuthorAuthor001ID", "CommentComment001ID"},
+			Variables: []string{"AllArticles", "AllAuthors", "AllComments"},
+			Refs: map[string][]string{
+				"ArticleArticle001": {"AuthorAuthor001", "CommentComment001", "CommentComment003"},
+			},
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	})
 }
 
 // TestManualVerification doesn't run as an automated test but provides code
@@ -0,0 +1,91 @@
+package genstruct
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// Resolution describes how one reference key of a structgen field resolved.
+type Resolution struct {
+	Record   string // Variable name of the record holding the key
+	Key      string // Reference key from the source field
+	Variable string // Variable the key resolved to, empty if unmatched
+	Field    string // Field of the referenced record that matched the key
+}
+
+// Explain reports how every key of a structgen field resolves against the
+// reference datasets, to debug why generated code references the wrong record.
+//
+// The target names the field as Type.Field (e.g. "Post.Tags"), where Type is
+// the type of the primary dataset or of one of the reference datasets. The
+// data and refs are the same values passed to Generate; no code is generated.
+func (g *Generator) Explain(target string, data any, refs ...any) ([]Resolution, error) {
+	typeName, fieldName, ok := strings.Cut(target, ".")
+	if !ok || typeName == "" || fieldName == "" {
+		return nil, ExplainTargetError{Target: target, Reason: "expected Type.Field"}
+	}
+
+	data, refs, err := g.loadSources(data, refs)
+	if err != nil {
+		return nil, err
+	}
+	g.Data = g.unwrapPointer(data)
+	g.resetInferred()
+	g.setRefs(refs)
+	if err := g.inferConfig(g.Data); err != nil {
+		return nil, err
+	}
+
+	// Find the dataset holding the field and how its variables are named
+	dataset, varPrefix := g.Data, g.VarPrefix
+	if elemType := g.dataStructType(); elemType == nil || elemType.Name() != typeName {
+		refDataObj, found := g.refData(typeName)
+		if !found {
+			return nil, ExplainTargetError{Target: target, Reason: "no dataset of type " + typeName}
+		}
+		dataset, varPrefix = refDataObj, g.refPrefix(typeName)
+	}
+	dataValue := reflect.ValueOf(dataset)
+
+	elemType := dataValue.Type().Elem()
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	field, found := elemType.FieldByName(fieldName)
+	if !found {
+		return nil, ExplainTargetError{Target: target, Reason: "no field " + fieldName + " on type " + typeName}
+	}
+	tagValue, hasTag := field.Tag.Lookup("structgen")
+	if !hasTag || tagValue == "" {
+		return nil, ExplainTargetError{Target: target, Reason: "field has no structgen tag"}
+	}
+	tag, err := parseStructgenTag(field, tagValue)
+	if err != nil {
+		return nil, err
+	}
+	var records []reflect.Value
+	for i := range dataValue.Len() {
+		records = append(records, reflect.Indirect(dataValue.Index(i)))
+	}
+	resolutions := g.fieldResolutions(records, varPrefix, field, tag)
+	return resolutions, nil
+}
+
+// WriteExplanation writes resolutions as an aligned table with one key per
+// line, showing the matched variable and field or "unmatched".
+func WriteExplanation(w io.Writer, resolutions []Resolution) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, r := range resolutions {
+		result := "unmatched"
+		if r.Variable != "" {
+			result = fmt.Sprintf("%s (matched %s)", r.Variable, r.Field)
+		}
+		if _, err := fmt.Fprintf(tw, "%s\t%q\t%s\n", r.Record, r.Key, result); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,53 @@
+package genstruct
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestExplain tests reporting how structgen reference keys resolve
+func TestExplain(t *testing.T) {
+	tags := []Tag{
+		{ID: "go", Name: "Golang", Slug: "golang"},
+		{ID: "tag-2", Name: "Go", Slug: "go"},
+	}
+	posts := []Post{
+		{ID: "post-1", Title: "Intro", TagSlugs: []string{"go", "rust"}},
+	}
+
+	generator := NewGenerator(WithIdentifierFields([]string{"ID", "Slug"}))
+	resolutions, err := generator.Explain("Post.Tags", posts, tags)
+	if err != nil {
+		t.Fatalf("Error explaining references: %v", err)
+	}
+	want := []Resolution{
+		{Record: "PostPost1", Key: "go", Variable: "TagGo", Field: "ID"},
+		{Record: "PostPost1", Key: "rust"},
+	}
+	if !reflect.DeepEqual(resolutions, want) {
+		t.Errorf("Expected %+v, got %+v", want, resolutions)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := WriteExplanation(buf, resolutions); err != nil {
+		t.Fatalf("Error writing explanation: %v", err)
+	}
+	for _, line := range []string{
+		`PostPost1  "go"    TagGo (matched ID)`,
+		`PostPost1  "rust"  unmatched`,
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("Expected line %q in explanation:\n%s", line, buf.String())
+		}
+	}
+
+	for _, target := range []string{"Post", "Author.Tags", "Post.Missing", "Post.Title"} {
+		var targetErr ExplainTargetError
+		if _, err := generator.Explain(target, posts, tags); !errors.As(err, &targetErr) {
+			t.Errorf("Expected ExplainTargetError for %q, got %v", target, err)
+		}
+	}
+}
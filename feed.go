@@ -0,0 +1,310 @@
+package genstruct
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FeedFormat selects the syndication format written by a Feed.
+type FeedFormat int
+
+const (
+	// FeedRSS writes an RSS 2.0 document.
+	FeedRSS FeedFormat = iota
+	// FeedAtom writes an Atom 1.0 document.
+	FeedAtom
+	// FeedJSON writes a JSON Feed 1.1 document.
+	FeedJSON
+)
+
+// Feed describes a syndication feed rendered from the primary dataset, such as
+// the RSS feed of a static blog. Items are ordered from newest to oldest.
+//
+// Each record becomes an item using its title and date fields, which are
+// required. The slug field, if present, is appended to Link to form the item
+// URL, and the content field, if present, becomes the item body.
+type Feed struct {
+	OutputFile  string     // Output file path of the feed document
+	Format      FeedFormat // Syndication format, RSS by default
+	Title       string     // Title of the site
+	Link        string     // Base URL of the site
+	Description string     // Description of the site
+	Author      string     // Name of the site author
+
+	TitleField   string // Field holding the item title, defaults to Title
+	SlugField    string // Field holding the item slug, defaults to Slug
+	DateField    string // Field holding the item date, defaults to Date
+	ContentField string // Field holding the item content, defaults to Content
+}
+
+// withDefaults returns the feed with unset field names replaced by their defaults
+func (f Feed) withDefaults() Feed {
+	if f.TitleField == "" {
+		f.TitleField = "Title"
+	}
+	if f.SlugField == "" {
+		f.SlugField = "Slug"
+	}
+	if f.DateField == "" {
+		f.DateField = "Date"
+	}
+	if f.ContentField == "" {
+		f.ContentField = "Content"
+	}
+	return f
+}
+
+// feedItem is a single entry of a feed independent of its format
+type feedItem struct {
+	Title   string
+	Link    string
+	Date    time.Time
+	Content string
+}
+
+// validateFeed reports the problems of a feed configuration for structType
+func validateFeed(feed Feed, structType reflect.Type) []error {
+	var errs []error
+	if feed.OutputFile == "" {
+		errs = append(errs, ConfigError{Option: "Feed.OutputFile", Reason: "no output file given"})
+	}
+	if feed.Format < FeedRSS || feed.Format > FeedJSON {
+		errs = append(errs, ConfigError{Option: "Feed.Format", Reason: "unknown feed format"})
+	}
+
+	feed = feed.withDefaults()
+	if field, ok := structType.FieldByName(feed.TitleField); !ok || field.Type.Kind() != reflect.String {
+		errs = append(errs, ConfigError{
+			Option: "Feed.TitleField",
+			Value:  feed.TitleField,
+			Reason: "not a string field of type " + structType.Name(),
+		})
+	}
+	if field, ok := structType.FieldByName(feed.DateField); !ok || field.Type != timeType {
+		errs = append(errs, ConfigError{
+			Option: "Feed.DateField",
+			Value:  feed.DateField,
+			Reason: "not a time.Time field of type " + structType.Name(),
+		})
+	}
+	return errs
+}
+
+// feedItems collects the feed items of the dataset ordered from newest to oldest
+func (g *Generator) feedItems(feed Feed, dataValue reflect.Value) []feedItem {
+	var items []feedItem
+	for _, elem := range g.unprunedRecords(dataValue) {
+		item := feedItem{
+			Title: elem.FieldByName(feed.TitleField).String(),
+			Link:  feed.Link,
+			Date:  elem.FieldByName(feed.DateField).Interface().(time.Time),
+		}
+		if slug := elem.FieldByName(feed.SlugField); slug.IsValid() && slug.Kind() == reflect.String && slug.String() != "" {
+			item.Link = strings.TrimRight(feed.Link, "/") + "/" + slug.String()
+		}
+		if content := elem.FieldByName(feed.ContentField); content.IsValid() && content.Kind() == reflect.String {
+			item.Content = content.String()
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Date.After(items[j].Date)
+	})
+	return items
+}
+
+// writeFeed renders the feed document for the primary dataset
+func (g *Generator) writeFeed(feed Feed, dataValue reflect.Value) error {
+	feed = feed.withDefaults()
+
+	// Make sure the feed stays inside the module like the generated code
+	fg := *g
+	fg.OutputFile = feed.OutputFile
+	if err := fg.validateOutputPath(); err != nil {
+		g.Logger.Error("Invalid feed output path", "error", err)
+		return err
+	}
+
+	items := g.feedItems(feed, dataValue)
+	var (
+		content []byte
+		err     error
+	)
+	switch feed.Format {
+	case FeedAtom:
+		content, err = renderAtom(feed, items)
+	case FeedJSON:
+		content, err = renderJSONFeed(feed, items)
+	default:
+		content, err = renderRSS(feed, items)
+	}
+	if err != nil {
+		g.Logger.Error("Failed to render feed", "error", err)
+		return err
+	}
+
+	g.Logger.Debug(
+		"Writing feed to file",
+		slog.String("file", feed.OutputFile),
+	)
+	return fg.writeFile(fg.resolvePath(feed.OutputFile), content)
+}
+
+// renderRSS renders the items as an RSS 2.0 document
+func renderRSS(feed Feed, items []feedItem) ([]byte, error) {
+	type rssItem struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		GUID        string `xml:"guid"`
+		PubDate     string `xml:"pubDate"`
+		Description string `xml:"description,omitempty"`
+	}
+	type rssChannel struct {
+		Title         string    `xml:"title"`
+		Link          string    `xml:"link"`
+		Description   string    `xml:"description"`
+		LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+		Items         []rssItem `xml:"item"`
+	}
+	type rss struct {
+		XMLName xml.Name   `xml:"rss"`
+		Version string     `xml:"version,attr"`
+		Channel rssChannel `xml:"channel"`
+	}
+
+	doc := rss{
+		Version: "2.0",
+		Channel: rssChannel{Title: feed.Title, Link: feed.Link, Description: feed.Description},
+	}
+	if len(items) > 0 {
+		doc.Channel.LastBuildDate = items[0].Date.Format(time.RFC1123Z)
+	}
+	for _, item := range items {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        item.Link,
+			PubDate:     item.Date.Format(time.RFC1123Z),
+			Description: item.Content,
+		})
+	}
+	return marshalXML(doc)
+}
+
+// renderAtom renders the items as an Atom 1.0 document
+func renderAtom(feed Feed, items []feedItem) ([]byte, error) {
+	type atomLink struct {
+		Href string `xml:"href,attr"`
+	}
+	type atomAuthor struct {
+		Name string `xml:"name"`
+	}
+	type atomContent struct {
+		Type string `xml:"type,attr"`
+		Body string `xml:",chardata"`
+	}
+	type atomEntry struct {
+		Title   string       `xml:"title"`
+		Link    atomLink     `xml:"link"`
+		ID      string       `xml:"id"`
+		Updated string       `xml:"updated"`
+		Content *atomContent `xml:"content,omitempty"`
+	}
+	type atom struct {
+		XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+		Title    string      `xml:"title"`
+		Subtitle string      `xml:"subtitle,omitempty"`
+		Link     atomLink    `xml:"link"`
+		ID       string      `xml:"id"`
+		Updated  string      `xml:"updated"`
+		Author   *atomAuthor `xml:"author,omitempty"`
+		Entries  []atomEntry `xml:"entry"`
+	}
+
+	doc := atom{
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		Link:     atomLink{Href: feed.Link},
+		ID:       feed.Link,
+	}
+	if len(items) > 0 {
+		doc.Updated = items[0].Date.Format(time.RFC3339)
+	}
+	if feed.Author != "" {
+		doc.Author = &atomAuthor{Name: feed.Author}
+	}
+	for _, item := range items {
+		entry := atomEntry{
+			Title:   item.Title,
+			Link:    atomLink{Href: item.Link},
+			ID:      item.Link,
+			Updated: item.Date.Format(time.RFC3339),
+		}
+		if item.Content != "" {
+			entry.Content = &atomContent{Type: "html", Body: item.Content}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return marshalXML(doc)
+}
+
+// renderJSONFeed renders the items as a JSON Feed 1.1 document
+func renderJSONFeed(feed Feed, items []feedItem) ([]byte, error) {
+	type jsonAuthor struct {
+		Name string `json:"name"`
+	}
+	type jsonItem struct {
+		ID            string `json:"id"`
+		URL           string `json:"url"`
+		Title         string `json:"title"`
+		ContentHTML   string `json:"content_html,omitempty"`
+		DatePublished string `json:"date_published"`
+	}
+	type jsonFeed struct {
+		Version     string       `json:"version"`
+		Title       string       `json:"title"`
+		HomePageURL string       `json:"home_page_url,omitempty"`
+		Description string       `json:"description,omitempty"`
+		Authors     []jsonAuthor `json:"authors,omitempty"`
+		Items       []jsonItem   `json:"items"`
+	}
+
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Title,
+		HomePageURL: feed.Link,
+		Description: feed.Description,
+		Items:       []jsonItem{},
+	}
+	if feed.Author != "" {
+		doc.Authors = []jsonAuthor{{Name: feed.Author}}
+	}
+	for _, item := range items {
+		doc.Items = append(doc.Items, jsonItem{
+			ID:            item.Link,
+			URL:           item.Link,
+			Title:         item.Title,
+			ContentHTML:   item.Content,
+			DatePublished: item.Date.Format(time.RFC3339),
+		})
+	}
+	content, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
+
+// marshalXML renders an indented XML document with its declaration
+func marshalXML(doc any) ([]byte, error) {
+	content, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(content, '\n')...), nil
+}
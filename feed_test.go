@@ -0,0 +1,107 @@
+package genstruct
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestFeed tests rendering RSS, Atom and JSON feeds from the primary dataset
+func TestFeed(t *testing.T) {
+	type Article struct {
+		ID      string
+		Title   string
+		Slug    string
+		Date    time.Time
+		Content string
+	}
+	articles := []Article{
+		{ID: "a-1", Title: "First", Slug: "first", Date: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), Content: "<p>Hello</p>"},
+		{ID: "a-2", Title: "Second", Slug: "second", Date: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	site := Feed{Title: "Blog", Link: "https://example.com/posts/", Description: "Posts", Author: "Ana"}
+
+	rss, atom, jsonFeed := site, site, site
+	rss.OutputFile = "feed.xml"
+	atom.OutputFile, atom.Format = "atom.xml", FeedAtom
+	jsonFeed.OutputFile, jsonFeed.Format = "feed.json", FeedJSON
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("articles.go"),
+		WithWorkingDir(dir),
+		WithFeed(rss),
+		WithFeed(atom),
+		WithFeed(jsonFeed),
+	)
+	if err := generator.Generate(articles); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	var rssDoc struct {
+		Items []struct {
+			Title       string `xml:"title"`
+			Link        string `xml:"link"`
+			Description string `xml:"description"`
+		} `xml:"channel>item"`
+	}
+	readFeed(t, dir+"/feed.xml", func(content []byte) error { return xml.Unmarshal(content, &rssDoc) })
+	if len(rssDoc.Items) != 2 || rssDoc.Items[0].Title != "Second" {
+		t.Fatalf("Expected items ordered newest first, got %+v", rssDoc.Items)
+	}
+	if rssDoc.Items[1].Link != "https://example.com/posts/first" || rssDoc.Items[1].Description != "<p>Hello</p>" {
+		t.Errorf("Unexpected RSS item %+v", rssDoc.Items[1])
+	}
+
+	var atomDoc struct {
+		XMLName xml.Name
+		Entries []struct {
+			ID string `xml:"id"`
+		} `xml:"entry"`
+	}
+	readFeed(t, dir+"/atom.xml", func(content []byte) error { return xml.Unmarshal(content, &atomDoc) })
+	if atomDoc.XMLName.Space != "http://www.w3.org/2005/Atom" || len(atomDoc.Entries) != 2 {
+		t.Errorf("Unexpected Atom document %+v", atomDoc)
+	}
+
+	var jsonDoc struct {
+		Version string `json:"version"`
+		Items   []struct {
+			URL           string `json:"url"`
+			DatePublished string `json:"date_published"`
+		} `json:"items"`
+	}
+	readFeed(t, dir+"/feed.json", func(content []byte) error { return json.Unmarshal(content, &jsonDoc) })
+	if jsonDoc.Version != "https://jsonfeed.org/version/1.1" || len(jsonDoc.Items) != 2 ||
+		jsonDoc.Items[0].DatePublished != "2023-02-01T00:00:00Z" {
+		t.Errorf("Unexpected JSON feed %+v", jsonDoc)
+	}
+
+	// The title and date fields are required
+	generator = NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("articles.go"),
+		WithWorkingDir(dir),
+		WithFeed(Feed{OutputFile: "feed.xml", DateField: "Slug"}),
+	)
+	var configErr ConfigError
+	if err := generator.Generate(articles); !errors.As(err, &configErr) || configErr.Option != "Feed.DateField" {
+		t.Errorf("Expected Feed.DateField error, got %v", err)
+	}
+}
+
+// readFeed reads and decodes a rendered feed document
+func readFeed(t *testing.T, path string, decode func([]byte) error) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error reading feed: %v", err)
+	}
+	if err := decode(content); err != nil {
+		t.Fatalf("Error decoding feed: %v", err)
+	}
+}
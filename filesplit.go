@@ -0,0 +1,277 @@
+package genstruct
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// Default limits set by WithSplitLargeFiles, which keep generated files
+// comfortably small for go vet and staticcheck
+const (
+	DefaultMaxFileKB    = 512
+	DefaultMaxFileDecls = 2000
+)
+
+// renderedFile is the rendered source of a generated file
+type renderedFile struct {
+	path string
+	src  []byte
+}
+
+// splitDecl is a top-level declaration of a rendered file along with the
+// comments preceding it
+type splitDecl struct {
+	text    string
+	imports map[string]bool // Names of the imported packages it uses
+}
+
+// partPath returns the path of the numbered piece of a split generated file
+func partPath(file string, n int) string {
+	return strings.TrimSuffix(file, ".go") + "_part" + strconv.Itoa(n) + ".go"
+}
+
+// splitsFiles reports whether generated files are split when they exceed
+// MaxFileKB or MaxFileDecls
+func (g *Generator) splitsFiles() bool {
+	return g.MaxFileKB > 0 || g.MaxFileDecls > 0
+}
+
+// renderSplit renders the generated file written to filePath. When it exceeds
+// MaxFileKB or MaxFileDecls, its declarations are packed in order into
+// numbered pieces next to it, and the file itself is reduced to an index
+// holding the package comment and listing the pieces. A declaration larger
+// than the limit gets a piece of its own.
+func (g *Generator) renderSplit(filePath string, file *jen.File) ([]renderedFile, error) {
+	buf := &bytes.Buffer{}
+	if err := file.Render(buf); err != nil {
+		g.Logger.Error("Failed to render code", "error", err)
+		return nil, err
+	}
+	src := buf.Bytes()
+	if !g.splitsFiles() {
+		src, err := g.postRender(src)
+		if err != nil {
+			return nil, err
+		}
+		return []renderedFile{{filePath, src}}, nil
+	}
+
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	maxBytes := g.MaxFileKB * 1024
+	var decls []ast.Decl
+	for _, decl := range parsed.Decls {
+		if gen, ok := decl.(*ast.GenDecl); !ok || gen.Tok != token.IMPORT {
+			decls = append(decls, decl)
+		}
+	}
+	if (maxBytes == 0 || len(src) <= maxBytes) && (g.MaxFileDecls == 0 || len(decls) <= g.MaxFileDecls) {
+		src, err := g.postRender(src)
+		if err != nil {
+			return nil, err
+		}
+		return []renderedFile{{filePath, src}}, nil
+	}
+
+	// Name the imports the way they are referred to in the code. Blank
+	// imports stay in the index, dot imports are needed by every piece.
+	imports := make(map[string]string)
+	var blankImports, dotImports []string
+	for _, spec := range parsed.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		name := importName(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		switch name {
+		case "_":
+			blankImports = append(blankImports, "_ "+spec.Path.Value)
+		case ".":
+			dotImports = append(dotImports, ". "+spec.Path.Value)
+		default:
+			imports[name] = spec.Path.Value
+		}
+	}
+
+	// Cut the source into declarations, each with the comments before it
+	offset := func(pos token.Pos) int { return fset.Position(pos).Offset }
+	start := offset(parsed.Name.End())
+	for _, decl := range parsed.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			start = offset(gen.End())
+		}
+	}
+	split := make([]splitDecl, len(decls))
+	for i, decl := range decls {
+		end := offset(decl.End())
+		if i == len(decls)-1 {
+			end = len(src)
+		}
+		split[i] = splitDecl{text: string(src[start:end]), imports: make(map[string]bool)}
+		ast.Inspect(decl, func(node ast.Node) bool {
+			if sel, ok := node.(*ast.SelectorExpr); ok {
+				if ident, ok := sel.X.(*ast.Ident); ok && imports[ident.Name] != "" {
+					split[i].imports[ident.Name] = true
+				}
+			}
+			return true
+		})
+		start = end
+	}
+
+	// Pack the declarations into pieces in order
+	var pieces [][]splitDecl
+	size := 0
+	for _, decl := range split {
+		last := len(pieces) - 1
+		if last < 0 ||
+			(maxBytes > 0 && size+len(decl.text) > maxBytes) ||
+			(g.MaxFileDecls > 0 && len(pieces[last]) >= g.MaxFileDecls) {
+			pieces = append(pieces, nil)
+			last++
+			size = 0
+		}
+		pieces[last] = append(pieces[last], decl)
+		size += len(decl.text)
+	}
+
+	header := g.headerComment() + "\n\npackage " + parsed.Name.Name + "\n"
+	files := make([]renderedFile, 0, len(pieces)+1)
+	index := &strings.Builder{}
+	index.Write(src[:offset(parsed.Name.End())])
+	index.WriteString("\n\n")
+	if len(blankImports) > 0 {
+		index.WriteString("import (\n\t" + strings.Join(blankImports, "\n\t") + "\n)\n\n")
+	}
+	index.WriteString("// The declarations of this file are split across the following files to\n")
+	index.WriteString("// keep each of them small:\n//\n")
+	for n, piece := range pieces {
+		used := make(map[string]bool)
+		body := &strings.Builder{}
+		for _, decl := range piece {
+			body.WriteString(decl.text)
+			for name := range decl.imports {
+				used[name] = true
+			}
+		}
+		pieceImports := slices.Clone(dotImports)
+		for _, name := range slices.Sorted(maps.Keys(used)) {
+			spec := imports[name]
+			if importPath, _ := strconv.Unquote(spec); importName(importPath) != name {
+				spec = name + " " + spec
+			}
+			pieceImports = append(pieceImports, spec)
+		}
+		pieceSrc := &strings.Builder{}
+		pieceSrc.WriteString(header)
+		if len(pieceImports) > 0 {
+			pieceSrc.WriteString("\nimport (\n\t" + strings.Join(pieceImports, "\n\t") + "\n)\n")
+		}
+		pieceSrc.WriteString(body.String())
+
+		piecePath := partPath(filePath, n+1)
+		formatted, err := format.Source([]byte(pieceSrc.String()))
+		if err != nil {
+			return nil, fmt.Errorf("format %s: %w", piecePath, err)
+		}
+		if formatted, err = g.postRender(formatted); err != nil {
+			return nil, err
+		}
+		files = append(files, renderedFile{piecePath, formatted})
+		index.WriteString("//   - " + filepath.Base(piecePath) + "\n")
+	}
+
+	indexSrc, err := format.Source([]byte(index.String()))
+	if err != nil {
+		return nil, fmt.Errorf("format %s: %w", filePath, err)
+	}
+	if indexSrc, err = g.postRender(indexSrc); err != nil {
+		return nil, err
+	}
+	return append([]renderedFile{{filePath, indexSrc}}, files...), nil
+}
+
+// importName returns the name a package is referred to by when imported
+// without a name, the last element of its path skipping a major version
+func importName(importPath string) string {
+	name := path.Base(importPath)
+	if len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = path.Base(path.Dir(importPath))
+	}
+	return name
+}
+
+// writeSplit renders the generated file written to filePath, split if it
+// exceeds the file limits, writes its pieces and removes the pieces left over
+// from earlier runs
+func (g *Generator) writeSplit(filePath string, file *jen.File) error {
+	files, err := g.renderSplit(filePath, file)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := g.writeFile(g.resolvePath(f.path), f.src); err != nil {
+			return err
+		}
+	}
+	return g.removeStaleParts(filePath, len(files)-1)
+}
+
+// staleParts returns the pieces of the generated file at filePath numbered
+// after count that were left over from earlier runs
+func (g *Generator) staleParts(filePath string, count int) ([]renderedFile, error) {
+	var stale []renderedFile
+	for n := count + 1; ; n++ {
+		piecePath := partPath(filePath, n)
+		content, err := g.readFile(g.resolvePath(piecePath))
+		if errors.Is(err, fs.ErrNotExist) {
+			return stale, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.HasPrefix(content, []byte(generatedHeader)) {
+			return stale, nil
+		}
+		stale = append(stale, renderedFile{piecePath, content})
+	}
+}
+
+// removeStaleParts removes the pieces of the generated file at filePath
+// numbered after count, which would otherwise redeclare its variables
+func (g *Generator) removeStaleParts(filePath string, count int) error {
+	stale, err := g.staleParts(filePath, count)
+	if err != nil {
+		return err
+	}
+	removeFS, canRemove := g.outputFS().(RemoveFS)
+	for _, f := range stale {
+		if !canRemove {
+			g.Logger.Warn("Cannot remove stale generated file", slog.String("file", f.path))
+			continue
+		}
+		g.Logger.Debug("Removing stale generated file", slog.String("file", f.path))
+		if err := removeFS.Remove(g.resolvePath(f.path)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
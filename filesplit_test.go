@@ -0,0 +1,120 @@
+package genstruct
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSplitLargeFiles tests that a generated file over the declaration limit
+// is split into pieces that compile together, and that the pieces are removed
+// once the file fits again
+func TestSplitLargeFiles(t *testing.T) {
+	var tags []Tag
+	var posts []Post
+	for i := range 12 {
+		id := strconv.Itoa(i)
+		tags = append(tags, Tag{ID: "tag-" + id, Name: "Tag " + id, Slug: "tag-" + id})
+		posts = append(posts, Post{
+			ID:       "post-" + id,
+			Title:    "Post " + id,
+			Date:     time.Date(2024, 1, i+1, 0, 0, 0, 0, time.UTC),
+			TagSlugs: []string{"tag-" + id},
+		})
+	}
+
+	dir := t.TempDir()
+	generate := func(opts ...Option) {
+		t.Helper()
+		opts = append([]Option{
+			WithPackageName("main"),
+			WithOutputFile("posts.go"),
+			WithWorkingDir(dir),
+			WithIdentifierFields([]string{"Slug", "ID"}),
+		}, opts...)
+		if err := NewGenerator(opts...).Generate(posts, tags); err != nil {
+			t.Fatalf("Error generating code: %v", err)
+		}
+	}
+	generate(WithMaxFileDecls(10))
+
+	index, err := os.ReadFile(filepath.Join(dir, "posts.go"))
+	if err != nil {
+		t.Fatalf("Error reading index file: %v", err)
+	}
+	for _, exp := range []string{hashCommentPrefix, "package main", "//   - posts_part1.go", "//   - posts_part2.go"} {
+		if !strings.Contains(string(index), exp) {
+			t.Errorf("Expected index file to contain %q, got:\n%s", exp, index)
+		}
+	}
+	if strings.Contains(string(index), "var ") {
+		t.Errorf("Expected index file to hold no declarations, got:\n%s", index)
+	}
+	parts, _ := filepath.Glob(filepath.Join(dir, "posts_part*.go"))
+	if len(parts) < 3 {
+		t.Fatalf("Expected at least 3 pieces, got %v", parts)
+	}
+	for _, part := range parts {
+		content, err := os.ReadFile(part)
+		if err != nil {
+			t.Fatalf("Error reading piece: %v", err)
+		}
+		if !strings.HasPrefix(string(content), generatedHeader) {
+			t.Errorf("Expected %s to start with the generated header", part)
+		}
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+	files := map[string]string{
+		"go.mod": "module split\n\ngo 1.24\n",
+		"types.go": `package main
+
+import "time"
+
+type Tag struct {
+	ID   string
+	Name string
+	Slug string
+}
+
+type Post struct {
+	ID       string
+	Title    string
+	Date     time.Time
+	TagSlugs []string
+	Tags     []*Tag
+}
+`,
+		"main.go": `package main
+
+func main() {
+	if len(AllPosts) != 12 || AllPosts[11].Tags[0] != &TagTag11 {
+		panic("split declarations are incomplete")
+	}
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Error writing %s: %v", name, err)
+		}
+	}
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Split program failed: %v\n%s", err, output)
+	}
+
+	generate()
+	if parts, _ := filepath.Glob(filepath.Join(dir, "posts_part*.go")); len(parts) != 0 {
+		t.Errorf("Expected stale pieces to be removed, got %v", parts)
+	}
+}
@@ -0,0 +1,78 @@
+package genstruct
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// Filter is a named subset of the primary dataset precomputed at generation
+// time and exposed as a function returning the matching records.
+type Filter struct {
+	Name string                   // Name of the generated function, e.g. EndangeredAnimals
+	Keep func(reflect.Value) bool // Reports whether a record belongs to the subset
+
+	recordType reflect.Type // Record type Keep was declared for
+}
+
+// WithFilterMethod generates a function named name returning the records for
+// which keep returns true, e.g.
+//
+//	WithFilterMethod("EndangeredAnimals", func(a Animal) bool { return a.Endangered })
+//
+// produces func EndangeredAnimals() []*Animal. The filter is evaluated at
+// generation time, so the function returns a precomputed slice without
+// scanning the dataset. T is the record type or a pointer to it.
+func WithFilterMethod[T any](name string, keep func(T) bool) Option {
+	recordType := reflect.TypeFor[T]()
+	return func(g *Generator) {
+		g.Filters = append(g.Filters, Filter{
+			Name: name,
+			Keep: func(record reflect.Value) bool {
+				if recordType.Kind() == reflect.Pointer {
+					if !record.CanAddr() {
+						copied := reflect.New(record.Type())
+						copied.Elem().Set(record)
+						return keep(copied.Interface().(T))
+					}
+					return keep(record.Addr().Interface().(T))
+				}
+				return keep(record.Interface().(T))
+			},
+			recordType: recordType,
+		})
+	}
+}
+
+// filterVarName returns the name of the unexported slice holding the records
+// of a filter
+func filterVarName(name string) string {
+	return lowerFirst(name) + "Records"
+}
+
+// generateFilters declares the precomputed slice and accessor function of
+// each filter
+func (g *Generator) generateFilters(dataValue reflect.Value) {
+	typeStmt := g.elemTypeStatement(dataValue)
+	elems := g.unprunedRecords(dataValue)
+	for _, filter := range g.Filters {
+		var varNames []string
+		for _, elem := range elems {
+			if filter.Keep(elem) {
+				varNames = append(varNames, g.varName(elem))
+			}
+		}
+
+		varName := filterVarName(filter.Name)
+		g.File.Var().Id(varName).Op("=").Add(recordPointers(typeStmt, varNames))
+
+		funcName := g.safeName(filter.Name)
+		g.declareFunc(
+			fmt.Sprintf("%s returns the %s values matching the %s filter.", funcName, g.TypeName, filter.Name),
+			"The result is computed at generation time and shared between calls.",
+		).Id(funcName).Params().Index().Op("*").Add(typeStmt.Clone()).Block(
+			jen.Return(jen.Id(varName)),
+		)
+	}
+}
@@ -0,0 +1,67 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFilterMethod tests that filters are precomputed into slices returned by
+// generated functions
+func TestFilterMethod(t *testing.T) {
+	type Animal struct {
+		ID         string
+		Name       string
+		Endangered bool
+		Legs       int
+	}
+	animals := []Animal{
+		{ID: "animal-1", Name: "Leo", Endangered: false, Legs: 4},
+		{ID: "animal-2", Name: "Kiwi", Endangered: true, Legs: 2},
+		{ID: "animal-3", Name: "Tigger", Endangered: true, Legs: 4},
+	}
+
+	dir := t.TempDir()
+	err := NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile("animals.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Name"}),
+		WithFilterMethod("EndangeredAnimals", func(a Animal) bool { return a.Endangered }),
+		WithFilterMethod("Bipeds", func(a *Animal) bool { return a.Legs == 2 }),
+	).Generate(animals)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "animals.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	output := string(content)
+
+	expected := []string{
+		"var endangeredAnimalsRecords = []*Animal{&AnimalKiwi, &AnimalTigger}",
+		"func EndangeredAnimals() []*Animal {\n\treturn endangeredAnimalsRecords\n}",
+		"var bipedsRecords = []*Animal{&AnimalKiwi}",
+		"func Bipeds() []*Animal {",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q", exp)
+		}
+	}
+
+	var configErr ConfigError
+	err = NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile("animals.go"),
+		WithWorkingDir(dir),
+		WithFilterMethod("Named", func(s string) bool { return s != "" }),
+	).Generate(animals)
+	if !errors.As(err, &configErr) || configErr.Option != "Filters" {
+		t.Errorf("Expected Filters ConfigError for mismatched record type, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package genstruct
+
+import (
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// fixtureRecord returns a copy of the record with its structgen fields set to
+// the records they reference, so fixtures hold the same data as the Go output.
+// Referenced records are included as supplied, without resolving their own
+// structgen fields.
+func (g *Generator) fixtureRecord(elem reflect.Value) reflect.Value {
+	record := reflect.New(elem.Type()).Elem()
+	record.Set(elem)
+
+	for i := range record.NumField() {
+		field := record.Type().Field(i)
+		tagValue, ok := field.Tag.Lookup("structgen")
+		if !ok || tagValue == "" || !field.IsExported() {
+			continue
+		}
+		tag, err := parseStructgenTag(field, tagValue)
+		if err != nil {
+			continue
+		}
+		refDataObj, ok := g.refData(g.referencedTypeName(field.Type))
+		if !ok {
+			continue
+		}
+		refData := reflect.ValueOf(refDataObj)
+
+		// Collect the referenced records as values of the field's element type
+		target := record.Field(i)
+		elemType := field.Type
+		if elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Map {
+			elemType = elemType.Elem()
+		}
+		src := elem.FieldByName(tag.Source)
+		var refs, keys []reflect.Value
+		for j, key := range referenceKeys(src) {
+			refStruct, _, found := g.findReference(refData, key, tag)
+			if !found {
+				continue
+			}
+			if elemType.Kind() == reflect.Pointer {
+				ptr := reflect.New(refStruct.Type())
+				ptr.Elem().Set(refStruct)
+				refStruct = ptr
+			}
+			refs = append(refs, refStruct)
+			if field.Type.Kind() == reflect.Map {
+				keys = append(keys, src.Index(j).Convert(field.Type.Key()))
+			}
+		}
+
+		switch {
+		case field.Type.Kind() == reflect.Slice:
+			target.Set(reflect.Append(reflect.MakeSlice(field.Type, 0, len(refs)), refs...))
+		case field.Type.Kind() == reflect.Map:
+			target.Set(reflect.MakeMapWithSize(field.Type, len(refs)))
+			for j, ref := range refs {
+				target.SetMapIndex(keys[j], ref)
+			}
+		case len(refs) > 0:
+			target.Set(refs[0])
+		}
+	}
+	return record
+}
+
+// writeJSONFixtures writes the primary and reference datasets as JSON files to
+// JSONFixtureDir, either one array per dataset or one file per record
+func (g *Generator) writeJSONFixtures() error {
+	fg := *g
+	fg.OutputFile = filepath.Join(g.JSONFixtureDir, "fixture.json")
+	if err := fg.validateOutputPath(); err != nil {
+		g.Logger.Error("Invalid JSON fixture path", "error", err)
+		return err
+	}
+
+	g.loadAllRefs()
+	type dataset struct {
+		typeName  string
+		varPrefix string
+		data      any
+	}
+	datasets := []dataset{{g.TypeName, g.VarPrefix, g.Data}}
+	for _, typeName := range g.pendingRefs(nil) {
+		datasets = append(datasets, dataset{typeName, g.refPrefix(typeName), g.Refs[typeName]})
+	}
+
+	dir := g.resolvePath(g.JSONFixtureDir)
+	for _, ds := range datasets {
+		name := strings.ToLower(g.plural(refTypeIdent(ds.typeName)))
+		var records []any
+		var names []string
+		for _, elem := range g.unprunedRecords(reflect.ValueOf(ds.data)) {
+			records = append(records, g.fixtureRecord(elem).Interface())
+			names = append(names, g.prefixedIdentifier(ds.varPrefix, elem))
+		}
+
+		if !g.JSONFixturePerRecord {
+			if err := g.writeJSONFile(filepath.Join(dir, name+".json"), records); err != nil {
+				return err
+			}
+			continue
+		}
+		for i, record := range records {
+			if err := g.writeJSONFile(filepath.Join(dir, name, names[i]+".json"), record); err != nil {
+				return err
+			}
+		}
+	}
+
+	g.Logger.Debug(
+		"Wrote JSON fixtures",
+		slog.String("dir", g.JSONFixtureDir),
+		slog.Int("datasets", len(datasets)),
+	)
+	return nil
+}
+
+// writeJSONFile writes value as indented JSON, creating parent directories
+func (g *Generator) writeJSONFile(path string, value any) error {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := g.mkdirAll(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return g.writeFile(path, append(content, '\n'))
+}
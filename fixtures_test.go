@@ -0,0 +1,68 @@
+package genstruct
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// TestJSONFixtures tests writing datasets as JSON fixture files
+func TestJSONFixtures(t *testing.T) {
+	tags := []Tag{{ID: "tag-1", Name: "Go", Slug: "go"}}
+	posts := []Post{{ID: "post-1", Title: "Intro", TagSlugs: []string{"go"}}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+		WithJSONFixtures("fixtures"),
+	)
+	if err := generator.Generate(posts, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	var gotPosts []Post
+	readJSON(t, dir+"/fixtures/posts.json", &gotPosts)
+	if len(gotPosts) != 1 || len(gotPosts[0].Tags) != 1 || gotPosts[0].Tags[0].Name != "Go" {
+		t.Errorf("Expected post fixture with resolved tags, got %+v", gotPosts)
+	}
+	if len(posts[0].Tags) != 0 {
+		t.Error("Expected the input data to be left unchanged")
+	}
+	var gotTags []Tag
+	readJSON(t, dir+"/fixtures/tags.json", &gotTags)
+	if len(gotTags) != 1 || gotTags[0] != tags[0] {
+		t.Errorf("Expected tag fixture %+v, got %+v", tags, gotTags)
+	}
+
+	// One file per record
+	generator = NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+		WithJSONFixturesPerRecord("records"),
+	)
+	if err := generator.Generate(posts, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	var gotTag Tag
+	readJSON(t, dir+"/records/tags/TagGo.json", &gotTag)
+	if gotTag != tags[0] {
+		t.Errorf("Expected tag record %+v, got %+v", tags[0], gotTag)
+	}
+}
+
+// readJSON reads and decodes a JSON file
+func readJSON(t *testing.T, path string, value any) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error reading %s: %v", path, err)
+	}
+	if err := json.Unmarshal(content, value); err != nil {
+		t.Fatalf("Error decoding %s: %v", path, err)
+	}
+}
@@ -0,0 +1,100 @@
+package genstruct
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// maxFlags is the number of distinct values a generated flag set can hold
+const maxFlags = 64
+
+// isFlagSetField reports whether values of the field type can be turned into
+// a flag set, which takes a slice or array of strings
+func isFlagSetField(fieldType reflect.Type) bool {
+	return (fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array) &&
+		fieldType.Elem().Kind() == reflect.String
+}
+
+// generateFlagSets creates, for each configured string slice field, a bitmask
+// type with a constant per distinct value (e.g. PostPermissionsRead), a
+// constant holding the mask of every record (e.g. PostHelloPermissions) and a
+// Has method, so sets of strings are compared as flags at runtime
+func (g *Generator) generateFlagSets(dataValue reflect.Value) {
+	elems := g.unprunedRecords(dataValue)
+
+	for _, fieldName := range g.FlagSetFields {
+		seen := make(map[string]bool)
+		for _, elem := range elems {
+			values := elem.FieldByName(fieldName)
+			for i := range values.Len() {
+				if value := values.Index(i).String(); value != "" {
+					seen[value] = true
+				}
+			}
+		}
+		values := slices.Sorted(maps.Keys(seen))
+		if len(values) > maxFlags {
+			g.addGenError(ConfigError{
+				Option: "FlagSetFields",
+				Value:  fieldName,
+				Reason: fmt.Sprintf("has %d distinct values, more than the %d a flag set holds", len(values), maxFlags),
+			})
+			continue
+		}
+
+		flagType := g.safeName(g.TypeName + fieldName)
+		flagNames := make(map[string]string, len(values))
+		valuesByName := make(map[string]string, len(values))
+		for _, value := range values {
+			name := g.safeName(flagType + g.sanitizeIdentifier(value))
+			if previous, ok := valuesByName[name]; ok {
+				g.addGenError(DuplicateNameError{Name: name, Record: value, Previous: previous})
+			}
+			valuesByName[name] = value
+			flagNames[value] = name
+		}
+
+		g.File.Commentf("%s is a set of the %s values of %s records.", flagType, fieldName, g.TypeName)
+		g.File.Type().Id(flagType).Uint64()
+		g.File.Const().DefsFunc(func(group *jen.Group) {
+			for i, value := range values {
+				group.Id(flagNames[value]).Id(flagType).Op("=").Lit(1).Op("<<").Lit(i)
+			}
+		})
+
+		// Precomputed mask of each record, with every flag listed once
+		g.File.Const().DefsFunc(func(group *jen.Group) {
+			for _, elem := range elems {
+				var mask *jen.Statement
+				listed := make(map[string]bool)
+				recordValues := elem.FieldByName(fieldName)
+				for i := range recordValues.Len() {
+					value := recordValues.Index(i).String()
+					if value == "" || listed[value] {
+						continue
+					}
+					listed[value] = true
+					if mask == nil {
+						mask = jen.Id(flagNames[value])
+					} else {
+						mask.Op("|").Id(flagNames[value])
+					}
+				}
+				if mask == nil {
+					mask = jen.Lit(0)
+				}
+				group.Id(g.safeName(g.varName(elem) + fieldName)).Id(flagType).Op("=").Add(mask)
+			}
+		})
+
+		g.declareFunc(fmt.Sprintf("Has reports whether the set holds all of the given %s.", fieldName)).Params(
+			jen.Id("s").Id(flagType),
+		).Id("Has").Params(jen.Id("flags").Id(flagType)).Bool().Block(
+			jen.Return(jen.Id("s").Op("&").Id("flags").Op("==").Id("flags")),
+		)
+	}
+}
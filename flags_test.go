@@ -0,0 +1,119 @@
+package genstruct
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFlagSets tests that string slice fields are turned into bitmask types
+// with per-record masks that compile and answer Has
+func TestFlagSets(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	type Role struct {
+		ID          string
+		Permissions []string
+	}
+	roles := []Role{
+		{ID: "admin", Permissions: []string{"write", "read", "delete", "read"}},
+		{ID: "viewer", Permissions: []string{"read"}},
+		{ID: "guest"},
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module flags\n\ngo 1.24\n",
+		"types.go": `package main
+
+type Role struct {
+	ID          string
+	Permissions []string
+}
+`,
+		"main.go": `package main
+
+func main() {
+	if !RoleAdminPermissions.Has(RolePermissionsRead | RolePermissionsDelete) {
+		panic("admin is missing permissions")
+	}
+	if RoleViewerPermissions.Has(RolePermissionsWrite) || !RoleViewerPermissions.Has(RolePermissionsRead) {
+		panic("viewer has the wrong permissions")
+	}
+	if RoleGuestPermissions != 0 {
+		panic("guest has permissions")
+	}
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Error writing %s: %v", name, err)
+		}
+	}
+
+	err = NewGenerator(
+		WithPackageName("main"),
+		WithOutputFile("roles.go"),
+		WithWorkingDir(dir),
+		WithFlagSets("Permissions"),
+	).Generate(roles)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "roles.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	for _, want := range []string{
+		"type RolePermissions uint64",
+		"RolePermissionsDelete RolePermissions = 1 << 0",
+		"RoleAdminPermissions  RolePermissions = RolePermissionsWrite | RolePermissionsRead | RolePermissionsDelete",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, content)
+		}
+	}
+
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Generated program failed: %v\n%s", err, output)
+	}
+}
+
+// TestFlagSetLimits tests that flag sets are rejected for fields that aren't
+// string slices or hold more values than fit in the mask
+func TestFlagSetLimits(t *testing.T) {
+	type Role struct {
+		ID          string
+		Level       int
+		Permissions []string
+	}
+	var many []string
+	for i := range 65 {
+		many = append(many, fmt.Sprintf("p%d", i))
+	}
+	roles := []Role{{ID: "admin", Permissions: many}}
+
+	for _, field := range []string{"Level", "Permissions"} {
+		_, err := NewGenerator(
+			WithPackageName("roles"),
+			WithOutputFile("roles.go"),
+			WithFlagSets(field),
+		).Render(roles)
+		var configErr ConfigError
+		if !errors.As(err, &configErr) || configErr.Option != "FlagSetFields" || configErr.Value != field {
+			t.Errorf("Expected FlagSetFields ConfigError for %s, got %v", field, err)
+		}
+	}
+}
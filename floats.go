@@ -0,0 +1,75 @@
+package genstruct
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// Float literal formats
+const (
+	floatFormatDefault  byte = 0   // jennifer's default %#v formatting
+	floatFormatShortest byte = 'g' // shortest representation that round-trips
+	floatFormatFixed    byte = 'f' // fixed number of digits after the decimal point
+)
+
+// getFloatStatement generates a float literal using the configured float format.
+// bitSize is 32 for float32 values and 64 for float64 values.
+//
+// NaN, infinities and negative zero, which have no constant representation
+// (-0.0 is the constant zero), are generated as calls to the math package,
+// converted to float32 for float32 values since the calls return float64.
+func (g *Generator) getFloatStatement(f float64, bitSize int) *jen.Statement {
+	if stmt := nonConstantFloat(f); stmt != nil {
+		if bitSize == 32 {
+			return jen.Float32().Call(stmt)
+		}
+		return stmt
+	}
+	if g.FloatFormat == floatFormatDefault {
+		return jen.Lit(f)
+	}
+
+	precision := -1
+	if g.FloatFormat == floatFormatFixed {
+		precision = g.FloatPrecision
+	}
+	literal := strconv.FormatFloat(f, g.FloatFormat, precision, bitSize)
+
+	// Always keep a decimal point so the literal stays a float
+	if !strings.ContainsAny(literal, ".e") {
+		literal += ".0"
+	}
+	return jen.Op(literal)
+}
+
+// nonConstantFloat returns the math package call producing f when f can't be
+// written as a constant, or nil when it can
+func nonConstantFloat(f float64) *jen.Statement {
+	switch {
+	case math.IsNaN(f):
+		return jen.Qual("math", "NaN").Call()
+	case math.IsInf(f, 1):
+		return jen.Qual("math", "Inf").Call(jen.Lit(1))
+	case math.IsInf(f, -1):
+		return jen.Qual("math", "Inf").Call(jen.Lit(-1))
+	case f == 0 && math.Signbit(f):
+		return jen.Qual("math", "Copysign").Call(jen.Lit(0), jen.Lit(-1))
+	}
+	return nil
+}
+
+// getComplexStatement generates a complex literal. bitSize is 64 for
+// complex64 values and 128 for complex128 values. Parts without a constant
+// representation are combined with the complex builtin instead.
+func (g *Generator) getComplexStatement(c complex128, bitSize int) *jen.Statement {
+	if nonConstantFloat(real(c)) == nil && nonConstantFloat(imag(c)) == nil {
+		return jen.Lit(c)
+	}
+	return jen.Complex(
+		g.getFloatStatement(real(c), bitSize/2),
+		g.getFloatStatement(imag(c), bitSize/2),
+	)
+}
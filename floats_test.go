@@ -0,0 +1,39 @@
+package genstruct
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// TestFloatFormatting tests the float literal formatting options
+func TestFloatFormatting(t *testing.T) {
+	tests := []struct {
+		name    string
+		opt     Option
+		value   float64
+		bitSize int
+		want    string
+	}{
+		{"shortest float32", WithShortestFloats(), float64(float32(160.3)), 32, "160.3"},
+		{"shortest whole", WithShortestFloats(), 1100, 64, "1100.0"},
+		{"fixed precision", WithFloatPrecision(2), 180.5, 64, "180.50"},
+		{"fixed zero precision", WithFloatPrecision(0), 42, 64, "42.0"},
+		{"NaN", WithShortestFloats(), math.NaN(), 64, "math.NaN()"},
+		{"float32 infinity", WithShortestFloats(), math.Inf(1), 32, "float32(math.Inf(1))"},
+		{"negative infinity", WithFloatPrecision(1), math.Inf(-1), 64, "math.Inf(-1)"},
+		{"negative zero", WithShortestFloats(), math.Copysign(0, -1), 64, "math.Copysign(0, -1)"},
+		{"default negative zero", WithFloatPrecision(3), math.Copysign(0, -1), 32, "float32(math.Copysign(0, -1))"},
+		{"zero", WithShortestFloats(), 0, 64, "0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator(tt.opt)
+			got := fmt.Sprintf("%#v", g.getFloatStatement(tt.value, tt.bitSize))
+			if got != tt.want {
+				t.Errorf("getFloatStatement(%v) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
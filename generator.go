@@ -2,15 +2,15 @@ package genstruct
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"log/slog"
-	"os"
 	"path/filepath"
 	"reflect"
 	"regexp"
 	"runtime/debug"
+	"slices"
 	"strings"
-	"time"
 
 	"github.com/dave/jennifer/jen"
 )
@@ -18,19 +18,127 @@ import (
 // Generator is responsible for generating code for static struct arrays
 type Generator struct {
 	// Primary configuration options
-	PackageName      string
-	TypeName         string
-	ConstantIdent    string
-	VarPrefix        string
-	OutputFile       string
-	IdentifierFields []string
-	CustomVarNameFn  func(structValue reflect.Value) string
-	Logger           *slog.Logger
+	PackageName          string
+	TypeName             string
+	ConstantIdent        string
+	VarPrefix            string
+	OutputFile           string
+	IdentifierFields     []string
+	TypeIdentifierFields map[string][]string
+	RefPrefixes          map[string]string
+	SliceNames           map[string]string
+	Pluralizer           func(typeName string) string
+	StripTypePrefix      bool
+	PartitionField       string
+	Checksum             bool
+	CustomVarNameFn      func(structValue reflect.Value) string
+	NameFn               func(ctx NameContext) string
+	IdentifierSanitizer  func(s string) string
+	Initialisms          []string
+	Logger               *slog.Logger
+	LangVersion          string
+	ExampleFile          bool
+	PrunedRecords        []string
+	AllowExternalPath    bool
+	WorkingDir           string
+	IdentifierConsts     bool
+	LookupMaps           bool
+	LookupMapFields      []string
+	Accessors            bool
+	ConstantRefs         bool
+	RefMatchNormalizer   func(string) string
+	FloatFormat          byte
+	FloatPrecision       int
+	CharLiterals         bool
+	ModuleRewrites       map[string]string
+	EncryptionKey        []byte
+	EncryptedFields      []string
+	OpaqueNames          bool
+	Views                []View
+	TimeZoneMode         TimeZoneMode
+	MapKeyConsts         bool
+	DuplicateReport      bool
+	WarnDuplicateNames   bool
+	CollisionPolicy      CollisionPolicy
+	SkipUnchanged        bool
+	NoWrite              bool
+	SymbolMap            bool
+	RangeIndexFields     []string
+	SortOrderFields      []string
+	FlagSetFields        []string
+	DateIndexFields      []string
+	UsageCounts          bool
+	Feeds                []Feed
+	SlugFields           []string
+	RouteNamespaces      [][]string
+	SchemaVersion        string
+	FuncsFile            string
+	SplitOutput          bool
+	MaxFileKB            int
+	MaxFileDecls         int
+	DryRun               bool
+	CloneMethods         bool
+	ExportMode           bool
+	RefOutputFiles       map[string]string
+	CoverageMarker       string
+	TypeScriptFile       string
+	JSONFixtureDir       string
+	JSONFixturePerRecord bool
+	SyntheticIDFn        func(typeName string, index int) string
+	FallbackNameFn       func(typeName string, index int) string
+	MaxIdentifierLen     int
+	MaxDepth             int
+	ReservedNames        []string
+	RenameReserved       bool
+	EqualityTestSource   string
+	EqualityTestVar      string
+	PackageConstructors  map[string]string
+	LiteralPackages      []string
+	Filters              []Filter
+	PostRenderFns        []func([]byte) ([]byte, error)
+	HeaderTemplate       string
+	OutputFS             WriteFS
+	AssetHashes          []AssetHash
+	Attributions         *AttributionConfig
 
 	// Internal state
-	Data any            // The primary array of structs to generate code for
-	Refs map[string]any // Additional arrays that can be referenced
-	File *jen.File
+	Data     any            // The primary array of structs to generate code for
+	Refs     map[string]any // Additional arrays that can be referenced
+	File     *jen.File
+	FuncFile *jen.File            // Receives the generated functions when FuncsFile is set
+	RefFiles map[string]*jen.File // Receive the reference datasets written to their own files, by path
+	Hash     string               // Hash of the data and configuration of the last Generate call
+	Diff     string               // Unified diff of the files the last dry run would change
+	Report   ResolutionReport     // Resolution of the structgen references of the last Generate call
+
+	viewType   reflect.Type    // Struct type of the view being generated, if any
+	viewFields map[string]bool // Fields included in the view being generated
+
+	fileProvided        bool                     // Whether File was supplied with WithFile
+	inferred            inferredConfig           // Settings filled in by the last run
+	identifierFieldsSet bool                     // Whether IdentifierFields was set with WithIdentifierFields
+	exportModeSet       bool                     // Whether ExportMode was set with WithExportMode
+	refKeys             map[reflect.Type]string  // Package-qualified Refs keys of types sharing a name
+	lazyRefs            map[string]LazyRef       // Reference datasets not loaded yet
+	currentRecord       string                   // Variable name of the record being generated
+	timeZoneRecords     map[string]bool          // Records holding times with non-UTC locations
+	genErrors           []error                  // Errors found while generating values
+	symbols             []string                 // Variable names collected for the symbol map
+	schemas             []typeSchema             // Schemas of the generated types when pinned
+	syntheticID         string                   // Synthetic ID of the record being generated
+	reservedWarned      map[string]bool          // Reserved names already reported as renamed
+	customNames         map[uintptr]string       // Names given by the naming hook by record address
+	recordIndexes       map[uintptr]int          // Positions of records in their datasets by record address
+	sourceIndexes       map[reflect.Type][]int   // Source positions of the records of datasets with skipped records
+	captured            map[string][]byte        // Outputs rendered instead of written by a dry run or Verify, by path
+	collisionNames      map[collisionKey]string  // Identifiers of records renamed by the CollisionPolicy
+	assetSums           map[string]string        // Content hashes of assets read during the run
+	sidecarAttributions map[string]reflect.Value // Attributions read from sidecar files by record
+	attribution         reflect.Value            // Sidecar attribution of the record being generated
+	rendering           map[cycleKey]bool        // Pointers being rendered, to detect cycles
+	valuePath           []string                 // Path of the value being rendered when MaxDepth is set
+	refIndexes          map[refIndexKey]refIndex // Reference datasets indexed by identifier
+	headerLines         []string                 // Comment lines rendered from HeaderTemplate
 }
 
 // Option is a functional option for customizing the generator.
@@ -64,8 +172,9 @@ func WithVarPrefix(name string) Option {
 
 // WithOutputFile sets the output file path for the generated code.
 // The path can include directories.
-// Export mode is automatically determined based on this path - if it contains
-// directory separators, qualified imports will be used for external types.
+// Export mode is automatically determined based on this path - if it is in
+// another directory, qualified imports will be used for external types,
+// unless set with WithExportMode.
 // If not specified, defaults to lowercase(typename_generated.go).
 func WithOutputFile(path string) Option {
 	return func(g *Generator) { g.OutputFile = path }
@@ -75,7 +184,136 @@ func WithOutputFile(path string) Option {
 // These fields are checked in order until a non-empty string field is found.
 // If not specified, defaults to ["ID", "Name", "Slug", "Title", "Key", "Code"].
 func WithIdentifierFields(fields []string) Option {
-	return func(g *Generator) { g.IdentifierFields = fields }
+	return func(g *Generator) {
+		g.IdentifierFields = fields
+		g.identifierFieldsSet = true
+	}
+}
+
+// WithExportMode sets whether the generated code lives in a package other
+// than the data types, referencing them and the types of their fields with
+// their package name. It takes precedence over the default of enabling it
+// when the output file is in another directory than the working directory.
+func WithExportMode(enabled bool) Option {
+	return func(g *Generator) {
+		g.ExportMode = enabled
+		g.exportModeSet = true
+	}
+}
+
+// WithIdentifierFieldsFor sets the fields to use for naming the records of
+// the struct type named typeName, overriding IdentifierFields for that type.
+// For example, posts can be named by Slug while authors are named by Name in
+// the same Generate call. References to records of that type are matched on
+// these fields as well.
+func WithIdentifierFieldsFor(typeName string, fields []string) Option {
+	return func(g *Generator) {
+		if g.TypeIdentifierFields == nil {
+			g.TypeIdentifierFields = make(map[string][]string)
+		}
+		g.TypeIdentifierFields[typeName] = fields
+	}
+}
+
+// WithRefPrefix sets the prefix of the constants and variables generated for
+// the reference dataset of the struct type named typeName, which defaults to
+// the type name (e.g. WithRefPrefix("Employment", "Job") for JobEngineer
+// instead of EmploymentEngineer). Identifiers already starting with the
+// prefix, compared by whole words regardless of case, don't repeat it, so
+// WithRefPrefix("Employment", "Employment") names the record
+// "employment-engineer" EmploymentEngineer.
+//
+// Types sharing their name with the type of another dataset are named by
+// their package-qualified name (e.g. "pkga.Tag"), and their prefix defaults to
+// the package and type names joined (PkgaTag).
+func WithRefPrefix(typeName, prefix string) Option {
+	return func(g *Generator) {
+		if g.RefPrefixes == nil {
+			g.RefPrefixes = make(map[string]string)
+		}
+		g.RefPrefixes[typeName] = prefix
+	}
+}
+
+// WithAllSliceName sets the name of the slice holding all records of the
+// struct type named typeName (e.g. WithAllSliceName("Person", "AllPeople")).
+// Names derived from the plural, such as lookup maps and sorted slices, use
+// the name without its "All" prefix (PeopleByName).
+func WithAllSliceName(typeName, name string) Option {
+	return func(g *Generator) {
+		if g.SliceNames == nil {
+			g.SliceNames = make(map[string]string)
+		}
+		g.SliceNames[typeName] = name
+	}
+}
+
+// DefaultInitialisms are the initialisms written in upper case by
+// WithInitialisms, following the Go naming conventions.
+var DefaultInitialisms = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "CSV", "DNS", "EOF", "GUID", "HTML",
+	"HTTP", "HTTPS", "ID", "IP", "JSON", "JWT", "LHS", "OS", "QPS", "RAM",
+	"RHS", "RPC", "SLA", "SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP",
+	"UI", "UID", "URI", "URL", "UTF8", "UUID", "VM", "XML", "XMPP", "XSRF",
+	"XSS",
+}
+
+// WithInitialisms writes the words of record identifiers that are
+// initialisms in upper case, as Go naming conventions do, producing UserIDAPI
+// rather than UserIdApi for "user-id-api". The DefaultInitialisms are extended
+// with the given ones (e.g. WithInitialisms("GPU", "SKU")), and repeated
+// calls add to each other.
+func WithInitialisms(initialisms ...string) Option {
+	return func(g *Generator) {
+		if g.Initialisms == nil {
+			g.Initialisms = slices.Clone(DefaultInitialisms)
+		}
+		for _, initialism := range initialisms {
+			g.Initialisms = append(g.Initialisms, strings.ToUpper(initialism))
+		}
+	}
+}
+
+// WithPluralizer sets the function returning the plural of a type name, used
+// for the slices holding all records (AllPeople) and the names derived from
+// them. The default appends "s", "es" or "ies" to the type name. Names set
+// with WithAllSliceName take precedence.
+func WithPluralizer(pluralizer func(typeName string) string) Option {
+	return func(g *Generator) { g.Pluralizer = pluralizer }
+}
+
+// WithIdentifierSanitizer replaces the conversion of record identifiers and
+// map keys into the Go identifiers of generated names, which by default title
+// cases the alphanumeric words of the identifier ("api-key" becomes ApiKey).
+// The sanitizer may preserve acronyms (APIKey), transliterate Unicode, or
+// enforce a maximum length, and must return only letters, digits and
+// underscores.
+func WithIdentifierSanitizer(sanitizer func(s string) string) Option {
+	return func(g *Generator) { g.IdentifierSanitizer = sanitizer }
+}
+
+// WithStripTypePrefix drops the type name from the start of record
+// identifiers that already begin with it, producing TagGo instead of
+// TagTagGo for the tag "tag-go". Records whose identifiers only differ by
+// that prefix (e.g. "tag-go" and "go") end up with the same name.
+func WithStripTypePrefix() Option {
+	return func(g *Generator) { g.StripTypePrefix = true }
+}
+
+// WithPartitionField groups the generated constants and variables into
+// sections by the value of field (e.g. one section per Region), each headed by
+// a comment, so large generated files are easier to navigate. Sections are
+// ordered by value and records keep their dataset order within a section.
+// Datasets without the field are not partitioned.
+func WithPartitionField(field string) Option {
+	return func(g *Generator) { g.PartitionField = field }
+}
+
+// WithChecksum records a checksum of the generated Go files on their last
+// line, so manual edits can be detected with VerifyChecksum or the donotedit
+// analyzer in CI. The checksum covers the output of the post-render hooks.
+func WithChecksum() Option {
+	return func(g *Generator) { g.Checksum = true }
 }
 
 // WithCustomVarNameFn sets a custom function to control variable naming.
@@ -86,12 +324,567 @@ func WithCustomVarNameFn(fn func(structValue reflect.Value) string) Option {
 	return func(g *Generator) { g.CustomVarNameFn = fn }
 }
 
+// WithNameFunc sets a custom function to control variable naming, taking
+// precedence over WithCustomVarNameFn and IdentifierFields. The function
+// receives a NameContext with the record, its index and type name, and the
+// names already given to earlier records of the same dataset, so naming
+// schemes can resolve collisions themselves.
+func WithNameFunc(fn func(ctx NameContext) string) Option {
+	return func(g *Generator) { g.NameFn = fn }
+}
+
 // WithLogger sets a custom slog.Logger instance for logging during generation.
 // If not specified, the default logger is used.
 func WithLogger(logger *slog.Logger) Option {
 	return func(g *Generator) { g.Logger = logger }
 }
 
+// WithLangVersion sets the minimum Go language version the generated code must
+// compile with, such as "1.17" or "go1.21".
+// Generated code spells the empty interface as interface{} when the version
+// predates any. If not specified, the latest features are used.
+func WithLangVersion(version string) Option {
+	return func(g *Generator) { g.LangVersion = version }
+}
+
+// WithExampleFile enables writing a godoc example file next to the output file,
+// named after it (e.g. animals_example_test.go for animals.go).
+// The file contains runnable godoc examples that access a generated variable,
+// look up a record by its ID constant, and iterate over the All-slice.
+func WithExampleFile() Option {
+	return func(g *Generator) { g.ExampleFile = true }
+}
+
+// WithPrunedRecords excludes records from the generated code by variable name.
+// ID constants are still generated for pruned records since they are plain strings.
+// Combine with FindUnusedRecords to keep embedded datasets lean by dropping
+// records that consuming code never references. Generation fails with a
+// PrunedReferenceError if another record references a pruned one.
+func WithPrunedRecords(varNames []string) Option {
+	return func(g *Generator) { g.PrunedRecords = varNames }
+}
+
+// WithAllowExternalPath allows the output file to resolve outside of the current
+// module or workspace. By default, Generate refuses to write outside of it.
+func WithAllowExternalPath() Option {
+	return func(g *Generator) { g.AllowExternalPath = true }
+}
+
+// WithWorkingDir sets the root directory that OutputFile and other relative
+// paths are resolved against. If not specified, paths resolve against the
+// process working directory, which depends on where go test or go run is invoked.
+func WithWorkingDir(dir string) Option {
+	return func(g *Generator) { g.WorkingDir = dir }
+}
+
+// WithIdentifierConstants emits one constant per populated identifier field per
+// struct (e.g. PostIntroToGoID and PostIntroToGoSlug) instead of only the ID
+// constant, along with a lookup map per identifier field (e.g. PostsBySlug).
+func WithIdentifierConstants() Option {
+	return func(g *Generator) { g.IdentifierConsts = true }
+}
+
+// WithLookupMaps emits a map from the ID of each record to its variable for
+// every dataset next to its All slice (e.g. var AnimalsByID =
+// map[string]*Animal{...}). Given fields, a map is emitted per field instead
+// (e.g. PostsByID and PostsBySlug for "ID" and "Slug"); datasets without a
+// string field of that name get no map for it.
+func WithLookupMaps(fields ...string) Option {
+	return func(g *Generator) {
+		g.LookupMaps = true
+		g.LookupMapFields = fields
+	}
+}
+
+// WithAccessors emits a pair of typed accessor functions per lookup map of
+// every dataset (e.g. func GetAnimalByID(id string) (*Animal, bool) and
+// func MustGetAnimalByID(id string) *Animal, which panics when there is no
+// such record), generating the lookup maps by ID unless set otherwise with
+// WithLookupMaps or WithIdentifierConstants.
+func WithAccessors() Option {
+	return func(g *Generator) { g.Accessors = true }
+}
+
+// WithConstantRefs emits the keys of structgen references as the constants
+// of the referenced records instead of repeating their string literals, e.g.
+// AuthorID: AuthorAliceID rather than AuthorID: "alice", so changing an ID in
+// the data is a single-point edit. Keys matched on an identifier field other
+// than the ID use its constant when WithIdentifierConstants is set; other keys
+// stay literals.
+func WithConstantRefs() Option {
+	return func(g *Generator) { g.ConstantRefs = true }
+}
+
+// WithRefMatchNormalizer sets a function applied to both sides of a structgen
+// reference before comparing them, such as strings.ToLower for case-insensitive
+// matching or NormalizeSlug for slug normalization.
+// If not specified, references must match exactly.
+func WithRefMatchNormalizer(fn func(string) string) Option {
+	return func(g *Generator) { g.RefMatchNormalizer = fn }
+}
+
+// WithShortestFloats renders float fields using the shortest representation that
+// round-trips to the same value (strconv.FormatFloat with 'g' and -1), so a
+// float32 field holding 160.3 is emitted as 160.3 rather than 160.3000030517578.
+func WithShortestFloats() Option {
+	return func(g *Generator) {
+		g.FloatFormat = floatFormatShortest
+		g.FloatPrecision = -1
+	}
+}
+
+// WithFloatPrecision renders float fields with a fixed number of digits after
+// the decimal point. For example, with precision 2, 180.5 is emitted as 180.50.
+func WithFloatPrecision(precision int) Option {
+	return func(g *Generator) {
+		g.FloatFormat = floatFormatFixed
+		g.FloatPrecision = precision
+	}
+}
+
+// WithCharLiterals renders rune and byte values as character literals where
+// printable, such as 'A' or '\n' rather than 65 or uint8(10). Since rune and
+// byte are aliases, every int32 and uint8 value is rendered this way. Bytes
+// are rendered as characters only in the ASCII range.
+func WithCharLiterals() Option {
+	return func(g *Generator) { g.CharLiterals = true }
+}
+
+// WithModulePathRewrite rewrites import paths starting with the module path from
+// to start with to instead, for generating into a different module of a
+// monorepo that imports the data types under another path.
+func WithModulePathRewrite(from, to string) Option {
+	return func(g *Generator) {
+		if g.ModuleRewrites == nil {
+			g.ModuleRewrites = make(map[string]string)
+		}
+		g.ModuleRewrites[from] = to
+	}
+}
+
+// WithEncryptedFields encrypts the named string fields with AES-GCM at generation
+// time so their contents are not readable in the compiled binary.
+// The key must be 16, 24, or 32 bytes long. A Decrypt(key, ciphertext) function
+// is generated to recover the plaintext at runtime with the same key.
+func WithEncryptedFields(key []byte, fields ...string) Option {
+	return func(g *Generator) {
+		g.EncryptionKey = key
+		g.EncryptedFields = fields
+	}
+}
+
+// WithOpaqueNames replaces the human-readable part of generated variable and
+// constant names with short hashed names (e.g. AnimalX1a2b3c4d5e instead of
+// AnimalLeo), so record titles do not leak through symbol tables of
+// distributed binaries. A map from original names to variables
+// (e.g. AnimalsByOriginalName) is generated for lookups.
+func WithOpaqueNames() Option {
+	return func(g *Generator) { g.OpaqueNames = true }
+}
+
+// WithView adds a view package generated from the same primary dataset that
+// exposes only a subset of its fields. It can be used multiple times to fan out
+// one source of truth into several generated packages.
+func WithView(view View) Option {
+	return func(g *Generator) { g.Views = append(g.Views, view) }
+}
+
+// WithTimeZoneMode sets how times with non-UTC locations are emitted.
+// By default they are converted to UTC. TimeZoneFixed keeps the offset using
+// time.FixedZone, and TimeZoneLocation keeps named locations by loading them
+// from an embedded time/tzdata database. A warning lists the affected records.
+func WithTimeZoneMode(mode TimeZoneMode) Option {
+	return func(g *Generator) { g.TimeZoneMode = mode }
+}
+
+// WithMapKeyConstants emits a constant for every key used in the string-keyed
+// map fields of the dataset (e.g. PostMetadataKeyAuthor = "author"), reducing
+// stringly-typed access to embedded metadata. Keys that produce the same
+// name (e.g. "a-b" and "a_b") are reported as a DuplicateNameError.
+func WithMapKeyConstants() Option {
+	return func(g *Generator) { g.MapKeyConsts = true }
+}
+
+// WithDuplicateReport logs a warning for every group of duplicate records found
+// in the primary and reference datasets before generating code.
+// See FindDuplicates for what is considered a duplicate.
+func WithDuplicateReport() Option {
+	return func(g *Generator) { g.DuplicateReport = true }
+}
+
+// WithDuplicateNameWarnings logs a warning instead of failing when records of
+// a dataset would be generated under the same variable or ID constant name.
+// The generated code won't compile until the records are renamed.
+func WithDuplicateNameWarnings() Option {
+	return func(g *Generator) { g.WarnDuplicateNames = true }
+}
+
+// WithCollisionPolicy sets how records whose identifiers produce the same
+// generated name as an earlier record of their dataset (e.g. "Go!" and "go")
+// are named: CollisionError, the default, fails generation, while
+// CollisionSuffix and CollisionHash rename the later records.
+func WithCollisionPolicy(policy CollisionPolicy) Option {
+	return func(g *Generator) { g.CollisionPolicy = policy }
+}
+
+// WithSkipUnchanged skips writing the output file when it was already generated
+// from identical data and configuration with the same genstruct version, as
+// recorded by the hash in its header.
+//
+// Function options such as WithCustomVarNameFn, WithNameFunc, WithPluralizer,
+// WithFilterMethod or WithPostRender are only hashed as set or unset, so
+// changing the function they are given is not detected; delete the output
+// file or run without WithSkipUnchanged after changing one.
+func WithSkipUnchanged() Option {
+	return func(g *Generator) { g.SkipUnchanged = true }
+}
+
+// WithFile supplies an existing jen.File to generate into, so genstruct output
+// can be combined with other jen-based generation in one file controlled by the
+// caller. The package comment is left to the caller.
+func WithFile(file *jen.File) Option {
+	return func(g *Generator) {
+		g.File = file
+		g.fileProvided = true
+	}
+}
+
+// WithNoWrite suppresses writing any files, leaving the generated code in the
+// File field for the caller to render.
+func WithNoWrite() Option {
+	return func(g *Generator) { g.NoWrite = true }
+}
+
+// WithSymbolMap emits a GeneratedSymbols map from each variable name to a
+// pointer to the variable, letting reflection-driven frameworks enumerate the
+// generated records of all datasets without maintaining their own registry.
+func WithSymbolMap() Option {
+	return func(g *Generator) { g.SymbolMap = true }
+}
+
+// WithRangeIndexes generates, for each of the given numeric fields, a slice of
+// the records sorted by the field (e.g. AnimalsSortedByWeight) and a range query
+// helper (e.g. AnimalsWithWeightBetween(lo, hi float64) []*Animal) that uses
+// binary search on the pre-sorted slice instead of sorting at runtime.
+func WithRangeIndexes(fields ...string) Option {
+	return func(g *Generator) { g.RangeIndexFields = append(g.RangeIndexFields, fields...) }
+}
+
+// WithFlagSets generates, for each of the given string slice fields, a bitmask
+// type (e.g. PostPermissions) with a constant for every distinct value found in
+// the records (e.g. PostPermissionsRead), a constant holding the mask of each
+// record (e.g. PostHelloPermissions) and a Has method, turning sets of strings
+// into flags computed at generation time:
+//
+//	if PostHelloPermissions.Has(PostPermissionsRead | PostPermissionsWrite) { ... }
+//
+// A field can hold at most 64 distinct values.
+func WithFlagSets(fields ...string) Option {
+	return func(g *Generator) { g.FlagSetFields = append(g.FlagSetFields, fields...) }
+}
+
+// WithSortOrders generates, for each of the given fields, slices of the
+// positions of the records in the slice of all records in ascending and
+// descending order of the field (e.g. AnimalsByWeightAsc and
+// AnimalsByWeightDesc of type []int), so the records can be iterated in
+// several orders without copying them per order:
+//
+//	for _, i := range AnimalsByWeightDesc {
+//		fmt.Println(AllAnimals[i].Name)
+//	}
+//
+// Fields may be numbers, strings, booleans or times.
+func WithSortOrders(fields ...string) Option {
+	return func(g *Generator) { g.SortOrderFields = append(g.SortOrderFields, fields...) }
+}
+
+// WithDateIndexes generates archive helpers for each of the given time.Time
+// fields: a slice of the records sorted by the field (e.g. PostsSortedByDate),
+// maps grouping them by year and year-month (e.g. PostsByDateYear[2023],
+// PostsByDateYearMonth["2023-01"]), and PostsWithDateBefore/PostsWithDateAfter
+// helpers that use binary search on the pre-sorted slice.
+func WithDateIndexes(fields ...string) Option {
+	return func(g *Generator) { g.DateIndexFields = append(g.DateIndexFields, fields...) }
+}
+
+// WithUsageCounts generates, for each dataset referenced through structgen
+// fields, a frequency map counting the records that reference each of its
+// records (e.g. TagUsageCount map[string]int keyed by the tag's identifier) and
+// a slice of its records ordered from most to least used (e.g. TagsByUsage).
+func WithUsageCounts() Option {
+	return func(g *Generator) { g.UsageCounts = true }
+}
+
+// WithFeed adds an RSS, Atom or JSON Feed document rendered from the primary
+// dataset, such as the feed of a static blog. It can be used multiple times to
+// write the same records in several formats.
+func WithFeed(feed Feed) Option {
+	return func(g *Generator) { g.Feeds = append(g.Feeds, feed) }
+}
+
+// WithSlugValidation checks before generating that the given slug-like fields
+// (Slug if none are given) are URL-safe and unique within each dataset,
+// returning a SlugError listing every violation. Lazily loaded reference
+// datasets are loaded for the check.
+func WithSlugValidation(fields ...string) Option {
+	return func(g *Generator) {
+		if len(fields) == 0 {
+			fields = []string{"Slug"}
+		}
+		g.SlugFields = append(g.SlugFields, fields...)
+	}
+}
+
+// WithRouteNamespace declares that the datasets of the named types share a
+// route namespace, so WithSlugValidation also requires slugs to be unique
+// across them (e.g. pages and posts both served under /{slug}).
+func WithRouteNamespace(typeNames ...string) Option {
+	return func(g *Generator) {
+		if len(typeNames) > 0 {
+			g.RouteNamespaces = append(g.RouteNamespaces, typeNames)
+		}
+	}
+}
+
+// WithSchemaVersion pins the schema of the generated types to version. The
+// fields and field types of each type are recorded in the package comment, and
+// generation fails with a SchemaChangedError if they change while the version
+// stays the same, guarding generated data used as a stable contract. Bump the
+// version to acknowledge an intended change.
+func WithSchemaVersion(version string) Option {
+	return func(g *Generator) { g.SchemaVersion = version }
+}
+
+// WithFuncsFile emits the generated helper functions (range queries, archive
+// helpers, Decrypt, Clone methods) into a separate file of the same package,
+// so coverage of helper functions can be tracked apart from the data
+// declarations.
+func WithFuncsFile(path string) Option {
+	return func(g *Generator) { g.FuncsFile = path }
+}
+
+// WithSplitOutput writes each reference dataset to its own file next to the
+// output file, named lowercase(typename_generated.go) like the default output
+// file, instead of one file holding every dataset. A dataset whose file would
+// be the output file stays in it.
+func WithSplitOutput() Option {
+	return func(g *Generator) { g.SplitOutput = true }
+}
+
+// WithMaxFileSize splits each generated file larger than kb kilobytes into
+// numbered pieces next to it (e.g. tags_part1.go, tags_part2.go), keeping
+// very large datasets from slowing down go vet and staticcheck. Declarations
+// are packed into the pieces in order, and the file itself is reduced to an
+// index holding the package comment and listing the pieces. A declaration
+// larger than the limit gets a piece of its own. Render returns the source
+// before splitting.
+func WithMaxFileSize(kb int) Option {
+	return func(g *Generator) { g.MaxFileKB = kb }
+}
+
+// WithMaxFileDecls splits each generated file with more than n top-level
+// declarations into numbered pieces like WithMaxFileSize.
+func WithMaxFileDecls(n int) Option {
+	return func(g *Generator) { g.MaxFileDecls = n }
+}
+
+// WithSplitLargeFiles splits generated files exceeding DefaultMaxFileKB or
+// DefaultMaxFileDecls, see WithMaxFileSize.
+func WithSplitLargeFiles() Option {
+	return func(g *Generator) {
+		g.MaxFileKB = DefaultMaxFileKB
+		g.MaxFileDecls = DefaultMaxFileDecls
+	}
+}
+
+// WithDryRun renders the code without writing any files, storing a unified
+// diff of the generated files against the existing ones in the Diff field and
+// logging it, to preview what a data change will do. The diff covers the Go
+// files and the outputs written next to them, such as equality tests,
+// TypeScript, JSON fixtures, feeds, notices and views. Diff is empty when
+// nothing would change.
+func WithDryRun() Option {
+	return func(g *Generator) { g.DryRun = true }
+}
+
+// WithRefOutputFile writes the reference dataset of the struct type named
+// typeName to path, with or without WithSplitOutput. Datasets given the same
+// path share the file.
+func WithRefOutputFile(typeName, path string) Option {
+	return func(g *Generator) {
+		if g.RefOutputFiles == nil {
+			g.RefOutputFiles = make(map[string]string)
+		}
+		g.RefOutputFiles[typeName] = path
+	}
+}
+
+// WithCloneMethods generates a Clone method for the struct types of the
+// datasets and the struct types they contain, returning a deep copy of the
+// record's exported slices, maps and pointers, so records can be modified
+// without changing the generated ones. The types must be declared in the
+// package the code is generated into and must not declare Clone themselves.
+// Types from other packages (e.g. time.Time) are copied by value.
+func WithCloneMethods() Option {
+	return func(g *Generator) { g.CloneMethods = true }
+}
+
+// WithCoverageMarker writes marker (e.g. "//coverage:ignore") directly above
+// every generated function for coverage tools that exclude marked functions.
+func WithCoverageMarker(marker string) Option {
+	return func(g *Generator) { g.CoverageMarker = marker }
+}
+
+// WithTypeScriptFile writes a TypeScript module of the same datasets next to
+// the Go output, with an interface per struct type, a const per record and an
+// array per dataset (e.g. export const allAnimals = [...] as const), so
+// front-ends can consume the static content without a second pipeline.
+//
+// Properties are named after json tags when present, times are ISO 8601
+// strings, and structgen fields refer to the consts of the referenced records.
+func WithTypeScriptFile(path string) Option {
+	return func(g *Generator) { g.TypeScriptFile = path }
+}
+
+// WithJSONFixtures writes each dataset as a JSON array file (e.g. posts.json)
+// into dir, keeping test fixtures and documentation samples in lockstep with
+// the generated code. Structgen fields hold the records they reference.
+func WithJSONFixtures(dir string) Option {
+	return func(g *Generator) { g.JSONFixtureDir = dir }
+}
+
+// WithJSONFixturesPerRecord is like WithJSONFixtures but writes one file per
+// record, named after its variable, into a directory per dataset
+// (e.g. posts/PostIntro.json).
+func WithJSONFixturesPerRecord(dir string) Option {
+	return func(g *Generator) {
+		g.JSONFixtureDir = dir
+		g.JSONFixturePerRecord = true
+	}
+}
+
+// WithSyntheticIDs sets the strategy assigning IDs to records whose ID field is
+// empty. The ID is used for the record's ID constant and written into the ID
+// field of its variable. Defaults to SequentialID; strategies should be
+// deterministic so regenerating produces the same IDs.
+func WithSyntheticIDs(fn func(typeName string, index int) string) Option {
+	return func(g *Generator) { g.SyntheticIDFn = fn }
+}
+
+// WithFallbackNames sets the strategy naming records without a non-empty
+// string field to identify them, given the name of their type and their
+// position in their dataset. Defaults to SequentialName; strategies should be
+// deterministic so regenerating produces the same names.
+func WithFallbackNames(fn func(typeName string, index int) string) Option {
+	return func(g *Generator) { g.FallbackNameFn = fn }
+}
+
+// WithMaxIdentifierLen caps the length of the record part of generated names
+// (e.g. "Leo" in AnimalLeo) at n characters. Longer identifiers are cut at a
+// word boundary and suffixed with a short hash to keep them unique; each
+// truncation is recorded in the package comment.
+func WithMaxIdentifierLen(n int) Option {
+	return func(g *Generator) { g.MaxIdentifierLen = n }
+}
+
+// WithMaxDepth fails generation when a record holds values nested more than n
+// levels deep, counting struct fields, elements and map entries (e.g.
+// PostHello.Comments[0].Replies is 3 levels deep). The error names the path
+// of the first value too deep, instead of exhausting the stack or taking
+// minutes on malformed data. The default of 0 sets no limit.
+func WithMaxDepth(n int) Option {
+	return func(g *Generator) { g.MaxDepth = n }
+}
+
+// WithReservedNames adds names that generated symbols must not use, on top of
+// the Go predeclared identifiers, init and main which are always reserved.
+func WithReservedNames(names ...string) Option {
+	return func(g *Generator) {
+		g.ReservedNames = append(g.ReservedNames, names...)
+	}
+}
+
+// WithRenameReserved renames generated symbols colliding with a reserved name
+// by appending an underscore, logging a warning, instead of failing.
+func WithRenameReserved() Option {
+	return func(g *Generator) { g.RenameReserved = true }
+}
+
+// WithEqualityTest writes a test next to the output file (e.g.
+// posts_equal_test.go) that compares each generated record with
+// reflect.DeepEqual against the source dataset it was generated from,
+// proving generation was lossless. sourceVar names the source slice and
+// sourcePath is the import path of its package, or empty when it lives in
+// the generated package. Fields populated from structgen references,
+// encrypted fields and synthetic IDs are ignored, and nil slices and maps
+// compare equal to the empty literals they are generated as.
+func WithEqualityTest(sourcePath, sourceVar string) Option {
+	return func(g *Generator) {
+		g.EqualityTestSource = sourcePath
+		g.EqualityTestVar = sourceVar
+	}
+}
+
+// WithPackageConstructor renders struct values of types from the package at
+// pkgPath as a call to the package function constructor with the value's
+// MarshalText or String form, e.g.
+// WithPackageConstructor("github.com/shopspring/decimal", "RequireFromString")
+// renders decimal.RequireFromString("1.50"). Use it for types with
+// unexported internals that cannot be written as composite literals.
+func WithPackageConstructor(pkgPath, constructor string) Option {
+	return func(g *Generator) {
+		if g.PackageConstructors == nil {
+			g.PackageConstructors = make(map[string]string)
+		}
+		g.PackageConstructors[pkgPath] = constructor
+	}
+}
+
+// WithPackageLiterals allows struct values of types from the given packages to
+// be rendered as qualified composite literals of their exported fields even
+// when the types have unexported fields, which are then left at their zero
+// value. Without it such values are reported as a VendorTypeError.
+func WithPackageLiterals(pkgPaths ...string) Option {
+	return func(g *Generator) {
+		g.LiteralPackages = append(g.LiteralPackages, pkgPaths...)
+	}
+}
+
+// WithPostRender adds a hook run on the rendered Go source of the output file
+// and the functions file before they are written, e.g. to stamp a license
+// header or apply extra formatting. Hooks run in the order they were added and
+// an error from any hook aborts the write.
+func WithPostRender(fn func(src []byte) ([]byte, error)) Option {
+	return func(g *Generator) {
+		g.PostRenderFns = append(g.PostRenderFns, fn)
+	}
+}
+
+// WithOutputFS writes all generated files to fsys instead of the operating
+// system's filesystem, e.g. a MemFS in tests or an overlay in build tools.
+// Existing outputs consulted by WithSkipUnchanged and WithSchemaVersion are
+// still read from disk.
+func WithOutputFS(fsys WriteFS) Option {
+	return func(g *Generator) { g.OutputFS = fsys }
+}
+
+// WithAssetHash hashes the content of the asset named by a path field at
+// generation time and writes the hash or the fingerprinted path into sibling
+// fields, so cache-busted URLs come straight from the static data. It can be
+// used multiple times for different path fields. Missing assets are reported
+// by Generate.
+func WithAssetHash(asset AssetHash) Option {
+	return func(g *Generator) { g.AssetHashes = append(g.AssetHashes, asset) }
+}
+
+// WithAttributions collects the license and attribution metadata of records
+// built from third-party content into a slice of the distinct attributions
+// (e.g. AnimalAttributions) and, if config.NoticeFile is set, a NOTICE-style
+// text file listing each attribution with the records carrying it.
+func WithAttributions(config AttributionConfig) Option {
+	return func(g *Generator) { g.Attributions = &config }
+}
+
 //
 
 // NewGenerator creates a new generator instance with the specified options.
@@ -115,9 +908,13 @@ func WithLogger(logger *slog.Logger) Option {
 //   - IdentifierFields: Uses default fields if not specified
 //   - Logger: Uses the default logger if not specified
 //
+// Inferred values are recomputed on every call to Generate, so one generator
+// can be reused for datasets of different types.
+//
 // Export mode (referencing types from other packages) is automatically determined
-// based on the output file path. If the path contains directory separators,
-// it will use qualified imports when referencing types from other packages.
+// based on the output file path. If the path is in another directory, it will
+// use qualified imports when referencing types from other packages.
+// WithExportMode sets it explicitly.
 func NewGenerator(opts ...Option) *Generator {
 	// Create a new generator with default values
 	g := &Generator{
@@ -130,7 +927,8 @@ func NewGenerator(opts ...Option) *Generator {
 			"Key",
 			"Code",
 		},
-		Logger: GetLogger(),
+		Logger:        GetLogger(),
+		SyntheticIDFn: SequentialID,
 	}
 
 	// Apply options
@@ -175,29 +973,36 @@ func (g *Generator) inferConfig(data any) error {
 
 	// Infer TypeName if not specified
 	if g.TypeName == "" {
-		g.TypeName = typeName
+		g.TypeName = typeIdent(typeName)
+		g.inferred.typeName = true
 	}
 
 	// Infer ConstantIdent if not specified
 	if g.ConstantIdent == "" {
 		g.ConstantIdent = g.TypeName
+		g.inferred.constantIdent = true
 	}
 
 	// Infer VarPrefix if not specified
 	if g.VarPrefix == "" {
 		g.VarPrefix = g.TypeName
+		g.inferred.varPrefix = true
 	}
 
 	// Infer OutputFile if not specified
 	if g.OutputFile == "" {
 		g.OutputFile = strings.ToLower(g.TypeName) + "_generated.go"
+		g.inferred.outputFile = true
 	}
 
 	// If PackageName is not specified, use the directory name from the output file
 	if g.PackageName == "" {
 		g.PackageName = GetPackageNameFromPath(g.OutputFile)
+		g.inferred.packageName = true
 	}
 
+	g.inferExportMode()
+
 	// Log the configuration
 	g.Logger.Debug(
 		"Configuration inferred",
@@ -209,6 +1014,52 @@ func (g *Generator) inferConfig(data any) error {
 	return nil
 }
 
+// inferredConfig records which settings inferConfig filled in, so a generator
+// reused for a different dataset infers them again instead of keeping the
+// values of the previous run
+type inferredConfig struct {
+	typeName      bool
+	constantIdent bool
+	varPrefix     bool
+	outputFile    bool
+	packageName   bool
+	exportMode    bool
+}
+
+// inferExportMode infers ExportMode unless it was set with WithExportMode:
+// an output file in another directory is assumed to be in a package other
+// than the data types. Paths are compared cleaned and with the separators of
+// the OS, so ./tags.go stays in the working directory.
+func (g *Generator) inferExportMode() {
+	if !g.exportModeSet {
+		g.ExportMode = filepath.Dir(filepath.Clean(g.OutputFile)) != "."
+		g.inferred.exportMode = true
+	}
+}
+
+// resetInferred clears the settings inferred by the previous run
+func (g *Generator) resetInferred() {
+	if g.inferred.typeName {
+		g.TypeName = ""
+	}
+	if g.inferred.constantIdent {
+		g.ConstantIdent = ""
+	}
+	if g.inferred.varPrefix {
+		g.VarPrefix = ""
+	}
+	if g.inferred.outputFile {
+		g.OutputFile = ""
+	}
+	if g.inferred.packageName {
+		g.PackageName = ""
+	}
+	if g.inferred.exportMode {
+		g.ExportMode = false
+	}
+	g.inferred = inferredConfig{}
+}
+
 // GetPackageNameFromPath extracts the containing folder name from a file path
 // This can be used to determine the package name for a given Go file
 // Example: "./out/penguin/gen.go" would return "penguin"
@@ -246,6 +1097,11 @@ func GetPackageNameFromPath(filePath string) string {
 //   - data: The primary array of structs to generate code for (can be slice, array, or pointer to slice/array)
 //   - refs: Optional additional arrays that can be referenced by the primary data
 //
+// Both may also be given as a JSONSource (see JSONFile and JSONReader), a
+// CSVSource (see CSVFile and CSVReader), a MarkdownSource (see MarkdownDir) or
+// an SQLSource (see SQLQuery), which is decoded into its struct type before
+// generating.
+//
 // The refs parameters enable struct references via the `structgen` tag. For example,
 // a Post struct with a TagSlugs field can reference Tag structs:
 //
@@ -258,6 +1114,13 @@ func GetPackageNameFromPath(filePath string) string {
 // Reference fields can be either direct structs or pointers to structs:
 //   - []Tag `structgen:"TagSlugs"` - Direct struct references
 //   - []*Tag `structgen:"TagSlugs"` - Pointer-based struct references (recommended)
+//   - map[string]*Tag `structgen:"TagSlugs"` - References keyed by their slugs
+//   - *Author `structgen:"AuthorID"` - References by numeric keys, e.g. AuthorID int
+//
+// Records whose boolean field tagged `genstruct:"skip"` is true are left out of
+// every dataset, e.g. Deleted bool `genstruct:"skip"` to soft-delete records.
+// The other records keep their source positions for synthetic IDs, fallback
+// names and the equality test.
 //
 // This method generates:
 // 1. Constants for the primary data's IDs
@@ -268,47 +1131,114 @@ func GetPackageNameFromPath(filePath string) string {
 // 6. A slice for each reference data set
 // 7. Creates references between primary data and reference data as specified by structgen tags
 //
-// All generated code is written to a single output file specified in the OutputFile field.
+// All generated code is written to a single output file specified in the OutputFile field,
+// unless reference datasets are split into their own files (see WithSplitOutput).
 //
 // Returns an error if:
 //   - The data is not a slice, array, or pointer to slice/array
 //   - The data is empty (no elements to analyze)
 //   - The data elements are not structs
 //   - Required fields couldn't be inferred
+//   - The configuration is invalid (see Validate)
 func (g *Generator) Generate(data any, refs ...any) error {
+	// Decode datasets given as JSON sources
+	data, refs, err := g.loadSources(data, refs)
+	if err != nil {
+		g.Logger.Error("Failed to load dataset", "error", err)
+		return err
+	}
+
 	// Handle both direct slices/arrays and pointers to slices/arrays
 	actualData := g.unwrapPointer(data)
 	g.Data = actualData
 
+	// Reset state left over from previous runs
+	g.resetInferred()
+	g.timeZoneRecords = nil
+	g.genErrors = nil
+	g.reservedWarned = nil
+	g.symbols = nil
+	g.schemas = nil
+	g.assetSums = nil
+
 	// Create a map of reference datasets
-	g.Refs = make(map[string]any)
-	for i, ref := range refs {
-		// Handle both direct and pointer references
-		actualRef := g.unwrapPointer(ref)
+	g.setRefs(refs)
 
-		// Get type name for this dataset
-		refType := reflect.TypeOf(actualRef)
-		if refType.Kind() == reflect.Slice || refType.Kind() == reflect.Array {
-			elemType := refType.Elem()
-			if elemType.Kind() == reflect.Struct {
-				g.Refs[elemType.Name()] = actualRef
-			} else if elemType.Kind() == reflect.Pointer &&
-				elemType.Elem().Kind() == reflect.Struct {
-				// Handle pointer slice ([]*Type)
-				g.Refs[elemType.Elem().Name()] = actualRef
-			} else {
-				g.Refs[fmt.Sprintf("Ref%d", i)] = actualRef
-			}
+	// Infer config options based on the actual data
+	if err := g.inferConfig(actualData); err != nil {
+		return err
+	}
+
+	// Catch invalid or contradictory settings before generation starts
+	if err := g.validateConfig(g.dataStructType()); err != nil {
+		g.Logger.Error("Invalid configuration", "error", err)
+		return err
+	}
+
+	// Make sure the output file stays inside the module
+	if err := g.validateOutputPath(); err != nil {
+		g.Logger.Error("Invalid output path", "error", err)
+		return err
+	}
+	if err := g.validateRefOutputPaths(); err != nil {
+		g.Logger.Error("Invalid reference output path", "error", err)
+		return err
+	}
+
+	// Make sure the target module can import the data types in export mode
+	if g.ExportMode {
+		if err := g.verifyTargetModule(); err != nil {
+			g.Logger.Error("Target module cannot import data types", "error", err)
+			return err
 		}
 	}
 
-	// Infer config options based on the actual data
-	if err := g.inferConfig(actualData); err != nil {
+	// Validate the encryption key before encrypting any fields
+	if len(g.EncryptedFields) > 0 {
+		if _, err := g.newEncryptionAEAD(); err != nil {
+			g.Logger.Error("Invalid encryption key", "error", err)
+			return fmt.Errorf("invalid encryption key: %w", err)
+		}
+	}
+
+	// Validate the target language version before emitting any code
+	if g.LangVersion != "" {
+		if _, _, err := parseLangVersion(g.LangVersion); err != nil {
+			g.Logger.Error("Invalid language version", "version", g.LangVersion)
+			return err
+		}
+	}
+
+	// Make sure the schema only changes together with its pinned version
+	if g.SchemaVersion != "" {
+		if err := g.checkSchema(); err != nil {
+			g.Logger.Error("Schema changed", "error", err)
+			return err
+		}
+	}
+
+	// Render the custom header of the generated files
+	if g.headerLines, err = g.renderHeader(); err != nil {
+		g.Logger.Error("Invalid header template", "error", err)
 		return err
 	}
 
-	// Initialize the file with the package name
-	g.File = jen.NewFile(g.PackageName)
+	// Initialize the file with the package name unless one was supplied
+	if !g.fileProvided {
+		g.File = jen.NewFile(g.PackageName)
+	}
+	g.FuncFile = nil
+	g.RefFiles = nil
+	g.Diff = ""
+	g.Report = ResolutionReport{}
+	if g.FuncsFile != "" {
+		if err := g.validateFuncsPath(); err != nil {
+			g.Logger.Error("Invalid functions file path", "error", err)
+			return err
+		}
+		g.FuncFile = jen.NewFile(g.PackageName)
+		g.writeHeader(g.FuncFile)
+	}
 
 	g.Logger.Info(
 		"Starting code generation",
@@ -317,32 +1247,29 @@ func (g *Generator) Generate(data any, refs ...any) error {
 		slog.String("output", g.OutputFile),
 	)
 
-	bi, ok := debug.ReadBuildInfo()
-	if !ok {
-		return fmt.Errorf("failed to read build info for version number")
+	// Hash the inputs so unchanged outputs can be detected
+	if g.SkipUnchanged {
+		g.loadAllRefs()
 	}
-
-	// Find github.com/conneroisu/genstruct dep
-	var dep *debug.Module
-	for _, d := range bi.Deps {
-		if d.Path == "github.com/conneroisu/genstruct" {
-			dep = d
-			break
-		}
+	hash, err := g.generationHash()
+	if err != nil {
+		return err
 	}
-	if dep == nil {
-		dep = &debug.Module{
-			Path:    "github.com/conneroisu/genstruct",
-			Version: "Unknown",
-		}
+	g.Hash = hash
+	if g.SkipUnchanged && readGeneratedHash(g.resolvePath(g.OutputFile)) == hash {
+		g.Logger.Info(
+			"Skipping unchanged output",
+			slog.String("output", g.OutputFile),
+			slog.String("hash", hash),
+		)
+		return nil
 	}
 
-	g.File.PackageComment(fmt.Sprintf(
-		"// Code generated by genstruct. DO NOT EDIT.\n// Package %s contains auto-generated %s data\n//\n// genstruct Version: %s\n//",
-		g.PackageName,
-		g.TypeName,
-		dep.Version,
-	))
+	if !g.fileProvided {
+		if err := g.writePackageComment(); err != nil {
+			return err
+		}
+	}
 
 	// Validate that we have an array or slice
 	dataValue := reflect.ValueOf(g.Data)
@@ -378,6 +1305,30 @@ func (g *Generator) Generate(data any, refs ...any) error {
 		return InvalidTypeError{firstElem.Kind()}
 	}
 
+	// Reject slugs that would produce broken or ambiguous routes
+	if len(g.SlugFields) > 0 {
+		if err := g.validateSlugs(); err != nil {
+			g.Logger.Error("Invalid slugs", "error", err)
+			return err
+		}
+	}
+
+	// Surface data-quality problems before they're baked into the code
+	if g.DuplicateReport {
+		g.reportDuplicates()
+	}
+
+	// Read the attributions of records that keep them in sidecar files
+	if g.Attributions != nil {
+		if err := g.loadAttributionSidecars(dataValue); err != nil {
+			g.Logger.Error("Invalid attribution sidecar", "error", err)
+			return err
+		}
+	}
+
+	// Records generated under the same name would not compile
+	g.checkDuplicateNames(dataValue)
+
 	// Generate constants for IDs if there's an ID field
 	g.Logger.Debug(
 		"Generating constants",
@@ -404,6 +1355,44 @@ func (g *Generator) Generate(data any, refs ...any) error {
 	)
 	g.generateSlice(dataValue)
 
+	// Generate per-field constants and lookup maps if requested
+	if g.IdentifierConsts {
+		g.generateIdentifierConstants(dataValue)
+	}
+	if g.IdentifierConsts || g.LookupMaps || g.Accessors {
+		g.generateLookupMaps(dataValue)
+	}
+	if g.Accessors {
+		g.generateAccessors(dataValue)
+	}
+	if g.OpaqueNames {
+		g.generateOpaqueNameMap(dataValue)
+	}
+	if g.MapKeyConsts {
+		g.generateMapKeyConstants(dataValue)
+	}
+	if len(g.RangeIndexFields) > 0 {
+		g.generateRangeIndexes(dataValue)
+	}
+	if len(g.DateIndexFields) > 0 {
+		g.generateDateIndexes(dataValue)
+	}
+	if len(g.SortOrderFields) > 0 {
+		g.generateSortOrders(dataValue)
+	}
+	if len(g.FlagSetFields) > 0 {
+		g.generateFlagSets(dataValue)
+	}
+	if len(g.Filters) > 0 {
+		g.generateFilters(dataValue)
+	}
+	if g.UsageCounts {
+		g.generateUsageCounts(dataValue)
+	}
+	if g.Attributions != nil {
+		g.generateAttributions(dataValue)
+	}
+
 	// Process reference datasets to generate their constants and variables
 	// This ensures that all referenced types (like Tag in Post.Tags) are properly defined
 	// in the generated code, making the references fully usable.
@@ -411,66 +1400,366 @@ func (g *Generator) Generate(data any, refs ...any) error {
 		"Processing reference datasets",
 		slog.Int("count", len(g.Refs)),
 	)
-	for typeName, refDataObj := range g.Refs {
-		g.Logger.Debug(
-			"Processing reference dataset",
-			slog.String("type", typeName),
-		)
-		refDataValue := reflect.ValueOf(refDataObj)
-		if refDataValue.Kind() == reflect.Slice ||
-			refDataValue.Kind() == reflect.Array {
-			if refDataValue.Len() > 0 {
-				refElem := refDataValue.Index(0)
-				// Support both direct structs and pointer-to-structs
-				if refElem.Kind() == reflect.Struct ||
-					(refElem.Kind() == reflect.Pointer &&
-						refElem.Elem().Kind() == reflect.Struct) {
-					// Store original config values so we can restore them after
-					// processing this reference type
-					originalTypeName := g.TypeName
-					originalVarPrefix := g.VarPrefix
-					originalConstantIdent := g.ConstantIdent
-
-					// Temporarily set config values for the reference type
-					// This ensures that constants and variables are named correctly
-					// (e.g., TagGoProgramming instead of PostGoProgramming)
-					g.TypeName = typeName
-					g.VarPrefix = typeName
-					g.ConstantIdent = typeName
-
-					// Generate constants, variables, and slice for this reference dataset
-					// using the same generation methods as for the primary dataset
-					g.generateConstants(refDataValue)
-					g.generateVariables(refDataValue)
-					g.generateSlice(refDataValue)
-
-					// Restore original config values for processing the next reference dataset
-					g.TypeName = originalTypeName
-					g.VarPrefix = originalVarPrefix
-					g.ConstantIdent = originalConstantIdent
-				}
+	// Reference datasets loaded lazily while generating are processed as well
+	processed := make(map[string]bool)
+	for {
+		pending := g.pendingRefs(processed)
+		if len(pending) == 0 {
+			break
+		}
+		for _, typeName := range pending {
+			processed[typeName] = true
+			g.generateRefDataset(typeName, g.Refs[typeName])
+		}
+	}
+
+	// Register the variables of all datasets by name
+	if g.SymbolMap {
+		g.generateSymbolMap()
+	}
+
+	// Report how the references resolved, also when strict ones failed
+	g.Report = g.resolutionReport()
+
+	// Fail on invalid structgen tags and unresolved strict references
+	if err := errors.Join(g.genErrors...); err != nil {
+		g.Logger.Error("Failed to generate references", "error", err)
+		return err
+	}
+
+	// Report records whose times depend on time zone handling
+	g.warnTimeZones()
+
+	// Generate the accessor for encrypted fields
+	if len(g.EncryptedFields) > 0 {
+		g.generateDecryptFunc()
+	}
+
+	// Generate deep copy methods for the dataset types
+	if g.CloneMethods {
+		g.generateCloneMethods()
+	}
+
+	// Report what would change instead of writing when previewing
+	if g.DryRun {
+		g.captured = make(map[string][]byte)
+		defer func() { g.captured = nil }()
+		if err := g.writeExtraOutputs(dataValue); err != nil {
+			return err
+		}
+		return g.dryRun()
+	}
+
+	// Leave rendering to the caller when writing is suppressed, rendering the
+	// other outputs in memory when Verify diffs them
+	if g.NoWrite {
+		if g.captured != nil {
+			return g.writeExtraOutputs(dataValue)
+		}
+		return nil
+	}
+
+	// Render the code and save it to the output file
+	if err := g.writeOutput(); err != nil {
+		return err
+	}
+	if g.FuncFile != nil {
+		if err := g.writeFuncsFile(); err != nil {
+			return err
+		}
+	}
+	if err := g.writeRefFiles(); err != nil {
+		return err
+	}
+
+	return g.writeExtraOutputs(dataValue)
+}
+
+// writeExtraOutputs writes the outputs generated next to the Go files, such
+// as tests, TypeScript, JSON fixtures, feeds, notices and views
+func (g *Generator) writeExtraOutputs(dataValue reflect.Value) error {
+	// Write the test proving the generated records match their source
+	if g.EqualityTestVar != "" {
+		if err := g.writeEqualityTest(dataValue); err != nil {
+			return err
+		}
+	}
+
+	// Write the godoc example file alongside the generated code
+	if g.ExampleFile {
+		if err := g.writeExampleFile(dataValue); err != nil {
+			return err
+		}
+	}
+
+	// Write the same datasets for TypeScript consumers
+	if g.TypeScriptFile != "" {
+		if err := g.writeTypeScript(); err != nil {
+			return err
+		}
+	}
+
+	// Write the datasets as JSON fixtures
+	if g.JSONFixtureDir != "" {
+		if err := g.writeJSONFixtures(); err != nil {
+			return err
+		}
+	}
+
+	// Render the syndication feeds of the primary dataset
+	for _, feed := range g.Feeds {
+		if err := g.writeFeed(feed, dataValue); err != nil {
+			return err
+		}
+	}
+
+	// List the third-party content the data was built from
+	if g.Attributions != nil && g.Attributions.NoticeFile != "" {
+		if err := g.writeNotice(dataValue); err != nil {
+			return err
+		}
+	}
+
+	// Fan out the primary dataset into its view packages
+	for _, view := range g.Views {
+		if err := g.generateView(view, dataValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setRefs creates the map of reference datasets keyed by their type name.
+// Types sharing their name with the type of another dataset (e.g. pkga.Tag
+// and pkgb.Tag) are keyed by their package-qualified name instead.
+func (g *Generator) setRefs(refs []any) {
+	g.Refs = make(map[string]any)
+	g.lazyRefs = make(map[string]LazyRef)
+	g.refKeys = nil
+	g.customNames = nil
+	g.recordIndexes = nil
+	g.collisionNames = nil
+	g.refIndexes = nil
+
+	// Find the element types of the datasets to detect shared names
+	refTypes := make([]reflect.Type, len(refs))
+	typesByName := make(map[string][]reflect.Type)
+	addType := func(t reflect.Type) {
+		if t != nil && !slices.Contains(typesByName[t.Name()], t) {
+			typesByName[t.Name()] = append(typesByName[t.Name()], t)
+		}
+	}
+	addType(g.dataStructType())
+	for i, ref := range refs {
+		if lazyRef, ok := ref.(LazyRef); ok {
+			refTypes[i] = lazyRef.elemType
+		} else if refType := reflect.TypeOf(g.unwrapPointer(ref)); refType != nil &&
+			(refType.Kind() == reflect.Slice || refType.Kind() == reflect.Array) {
+			elemType := refType.Elem()
+			if elemType.Kind() == reflect.Pointer {
+				elemType = elemType.Elem()
 			}
+			if elemType.Kind() == reflect.Struct {
+				refTypes[i] = elemType
+			}
+		}
+		addType(refTypes[i])
+	}
+	for _, refType := range refTypes {
+		if refType == nil || len(typesByName[refType.Name()]) < 2 {
+			continue
+		}
+		key := refType.String()
+		for _, other := range typesByName[refType.Name()] {
+			if other != refType && other.String() == key {
+				// Packages of the same name are told apart by path
+				key = refType.PkgPath() + "." + refType.Name()
+			}
+		}
+		if g.refKeys == nil {
+			g.refKeys = make(map[reflect.Type]string)
+		}
+		g.refKeys[refType] = key
+	}
+
+	for i, ref := range refs {
+		// Lazy references are only loaded once a structgen field needs them
+		if lazyRef, ok := ref.(LazyRef); ok {
+			if lazyRef.elemType != nil {
+				lazyRef.TypeName = g.refKey(lazyRef.elemType)
+			}
+			g.lazyRefs[lazyRef.TypeName] = lazyRef
+			continue
+		}
+
+		// Handle both direct and pointer references
+		actualRef := g.unwrapPointer(ref)
+		if refTypes[i] != nil {
+			g.Refs[g.refKey(refTypes[i])] = actualRef
+		} else if refType := reflect.TypeOf(actualRef); refType.Kind() == reflect.Slice || refType.Kind() == reflect.Array {
+			g.Refs[fmt.Sprintf("Ref%d", i)] = actualRef
 		}
 	}
+}
 
-	// Generate the code as a string
+// writeOutput renders the generated file and writes it to OutputFile
+func (g *Generator) writeOutput() error {
+	// Save the formatted code to file, split if it exceeds the file limits
+	g.Logger.Debug(
+		"Writing generated code to file",
+		slog.String("file", g.OutputFile),
+	)
+	return g.writeSplit(g.OutputFile, g.File)
+}
+
+// renderOutput renders the generated code and runs the post-render hooks
+func (g *Generator) renderOutput() ([]byte, error) {
 	g.Logger.Debug("Rendering generated code")
+	return g.renderFile(g.File)
+}
+
+// renderFile renders a generated file and runs the post-render hooks
+func (g *Generator) renderFile(file *jen.File) ([]byte, error) {
 	buf := &bytes.Buffer{}
-	if err := g.File.Render(buf); err != nil {
+	if err := file.Render(buf); err != nil {
 		g.Logger.Error("Failed to render code", "error", err)
-		return err
+		return nil, err
 	}
+	return g.postRender(buf.Bytes())
+}
 
-	// Save the formatted code to file
+// postRender runs the post-render hooks on rendered source in order, then
+// records the checksum of the result if enabled
+func (g *Generator) postRender(src []byte) ([]byte, error) {
+	for _, fn := range g.PostRenderFns {
+		var err error
+		if src, err = fn(src); err != nil {
+			g.Logger.Error("Post-render hook failed", "error", err)
+			return nil, fmt.Errorf("post-render hook: %w", err)
+		}
+	}
+	if g.Checksum {
+		src = appendChecksum(src)
+	}
+	return src, nil
+}
+
+// generateRefDataset generates constants, variables, and a slice for a
+// reference dataset, named after its struct type
+func (g *Generator) generateRefDataset(typeName string, refDataObj any) {
 	g.Logger.Debug(
-		"Writing generated code to file",
-		slog.String("file", g.OutputFile),
+		"Processing reference dataset",
+		slog.String("type", typeName),
 	)
-	return os.WriteFile(g.OutputFile, buf.Bytes(), 0644)
+	refDataValue := reflect.ValueOf(refDataObj)
+	if refDataValue.Kind() == reflect.Slice ||
+		refDataValue.Kind() == reflect.Array {
+		if refDataValue.Len() > 0 {
+			refElem := refDataValue.Index(0)
+			// Support both direct structs and pointer-to-structs
+			if refElem.Kind() == reflect.Struct ||
+				(refElem.Kind() == reflect.Pointer &&
+					refElem.Elem().Kind() == reflect.Struct) {
+				// Store original config values so we can restore them after
+				// processing this reference type
+				originalTypeName := g.TypeName
+				originalVarPrefix := g.VarPrefix
+				originalConstantIdent := g.ConstantIdent
+
+				// Temporarily set config values for the reference type
+				// This ensures that constants and variables are named correctly
+				// (e.g., TagGoProgramming instead of PostGoProgramming)
+				g.TypeName = refTypeIdent(typeName)
+				g.VarPrefix = g.refPrefix(typeName)
+				g.ConstantIdent = g.refPrefix(typeName)
+
+				// Declare the dataset in its own file when split
+				originalFile := g.File
+				g.File = g.refFile(typeName)
+
+				g.checkDuplicateNames(refDataValue)
+
+				// Generate constants, variables, and slice for this reference dataset
+				// using the same generation methods as for the primary dataset
+				g.generateConstants(refDataValue)
+				g.generateVariables(refDataValue)
+				g.generateSlice(refDataValue)
+				if g.IdentifierConsts {
+					g.generateIdentifierConstants(refDataValue)
+				}
+				if g.IdentifierConsts || g.LookupMaps || g.Accessors {
+					g.generateLookupMaps(refDataValue)
+				}
+				if g.Accessors {
+					g.generateAccessors(refDataValue)
+				}
+				if g.OpaqueNames {
+					g.generateOpaqueNameMap(refDataValue)
+				}
+				if g.MapKeyConsts {
+					g.generateMapKeyConstants(refDataValue)
+				}
+
+				// Restore original config values for processing the next reference dataset
+				g.TypeName = originalTypeName
+				g.VarPrefix = originalVarPrefix
+				g.ConstantIdent = originalConstantIdent
+				g.File = originalFile
+			}
+		}
+	}
+}
+
+// writePackageComment writes the "Code generated" header and package comment,
+// including the genstruct version, to the generated file
+func (g *Generator) writePackageComment() error {
+	version, err := genstructVersion()
+	if err != nil {
+		return err
+	}
+
+	// Keep the generated marker out of the package documentation
+	g.writeHeader(g.File)
+	g.File.PackageComment(fmt.Sprintf(
+		"// Package %s contains auto-generated %s data\n//\n// genstruct Version: %s\n%s%s%s%s\n//",
+		g.PackageName,
+		g.TypeName,
+		version,
+		hashCommentPrefix,
+		g.Hash,
+		g.schemaComment(),
+		g.truncationComment(),
+	))
+
+	return nil
+}
+
+// genstructVersion returns the version of the genstruct module the running
+// program was built with, or "Unknown" when it isn't a dependency
+func genstructVersion() (string, error) {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", fmt.Errorf("failed to read build info for version number")
+	}
+
+	// Find github.com/conneroisu/genstruct dep
+	for _, dep := range bi.Deps {
+		if dep.Path == "github.com/conneroisu/genstruct" {
+			return dep.Version, nil
+		}
+	}
+	return "Unknown", nil
 }
 
 // slugToIdentifier converts a string to a valid Go identifier
 func slugToIdentifier(s string) string {
+	return initialismIdentifier(s, nil)
+}
+
+// initialismIdentifier converts a string to a valid Go identifier, writing the
+// words listed in initialisms in upper case (e.g. UserIDAPI rather than
+// UserIdApi for "user-id-api")
+func initialismIdentifier(s string, initialisms []string) string {
 	// Replace non-alphanumeric characters with spaces
 	reg := regexp.MustCompile("[^a-zA-Z0-9]+")
 	processed := reg.ReplaceAllString(s, " ")
@@ -478,7 +1767,9 @@ func slugToIdentifier(s string) string {
 	// Title case each word and remove spaces
 	words := strings.Fields(processed)
 	for i, word := range words {
-		if len(word) > 0 {
+		if upper := strings.ToUpper(word); slices.Contains(initialisms, upper) {
+			words[i] = upper
+		} else if len(word) > 0 {
 			words[i] = strings.ToUpper(word[0:1]) + strings.ToLower(word[1:])
 		}
 	}
@@ -505,12 +1796,12 @@ func (g *Generator) getStructIdentifier(structValue reflect.Value) string {
 	}
 
 	// If a custom name function is provided, use it
-	if g.CustomVarNameFn != nil {
-		return g.CustomVarNameFn(structValue)
+	if fn := g.nameFunc(); fn != nil {
+		return g.customName(fn, structValue)
 	}
 
 	// Try all configured identifier fields
-	for _, fieldName := range g.IdentifierFields {
+	for _, fieldName := range g.identifierFields(structValue.Type()) {
 		field := structValue.FieldByName(fieldName)
 		if field.IsValid() && field.Kind() == reflect.String && field.String() != "" {
 			return field.String()
@@ -525,6 +1816,6 @@ func (g *Generator) getStructIdentifier(structValue reflect.Value) string {
 		}
 	}
 
-	// Fallback 2: Generate a name based on the type
-	return fmt.Sprintf("%s-%d", g.TypeName, time.Now().UnixNano())
+	// Fallback 2: Name the record by its position in its dataset
+	return g.fallbackName(structValue)
 }
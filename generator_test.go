@@ -1,11 +1,14 @@
 package genstruct
 
 import (
+	"fmt"
 	"os"
 	"reflect"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/dave/jennifer/jen"
 )
 
 // Tag is a test struct for reference embedding
@@ -209,3 +212,160 @@ func TestConfigInference(t *testing.T) {
 	}
 }
 
+// TestRefMatchNormalizer tests that references match after normalization
+func TestRefMatchNormalizer(t *testing.T) {
+	tags := []Tag{
+		{ID: "tag-1", Name: "Go", Slug: "go-programming"},
+	}
+	posts := []Post{
+		{ID: "post-1", Title: "Intro", TagSlugs: []string{"Go Programming"}},
+	}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("testdata"),
+		WithOutputFile("test_posts.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+		WithRefMatchNormalizer(NormalizeSlug),
+	)
+	if err := generator.Generate(posts, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(dir + "/test_posts.go")
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "[]*Tag{&TagGoProgramming}") {
+		t.Errorf("Expected normalized reference to TagGoProgramming, got:\n%s", content)
+	}
+}
+
+// TestLazyRefs tests that lazy reference datasets are only loaded when referenced
+func TestLazyRefs(t *testing.T) {
+	type Author struct {
+		ID   string
+		Name string
+	}
+
+	posts := []Post{
+		{ID: "post-1", Title: "Intro", TagSlugs: []string{"go"}},
+	}
+
+	tagsLoaded, authorsLoaded := false, false
+	loadTags := func() []Tag {
+		tagsLoaded = true
+		return []Tag{{ID: "tag-1", Name: "Go", Slug: "go"}}
+	}
+	loadAuthors := func() any {
+		authorsLoaded = true
+		return []Author{{ID: "author-1", Name: "Ana"}}
+	}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("testdata"),
+		WithOutputFile("test_posts.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+	)
+	err := generator.Generate(posts, Lazy(loadTags), LazyNamed("Author", loadAuthors))
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	if !tagsLoaded {
+		t.Error("Expected referenced Tag dataset to be loaded")
+	}
+	if authorsLoaded {
+		t.Error("Expected unreferenced Author dataset to not be loaded")
+	}
+
+	content, err := os.ReadFile(dir + "/test_posts.go")
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	for _, want := range []string{"var TagGo = Tag{", "[]*Tag{&TagGo}"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in generated code", want)
+		}
+	}
+}
+
+// TestWithFile tests generating into a caller-supplied file without writing it
+func TestWithFile(t *testing.T) {
+	tags := []Tag{{ID: "tag-1", Name: "Go", Slug: "go"}}
+
+	file := jen.NewFile("shared")
+	file.Comment("Hand-written declarations")
+	file.Var().Id("Version").Op("=").Lit("1.0.0")
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithOutputFile("tags.go"),
+		WithWorkingDir(dir),
+		WithFile(file),
+		WithNoWrite(),
+	)
+	if err := generator.Generate(tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	if _, err := os.Stat(dir + "/tags.go"); err == nil {
+		t.Error("Expected no output file to be written")
+	}
+
+	rendered := fmt.Sprintf("%#v", file)
+	for _, want := range []string{"package shared", `var Version = "1.0.0"`, "var TagTag1 = Tag{"} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("Expected to find %q in rendered file:\n%s", want, rendered)
+		}
+	}
+}
+
+// TestSequentialGenerate tests that inferred settings don't leak between runs
+// of a reused generator
+func TestSequentialGenerate(t *testing.T) {
+	type Author struct {
+		ID   string
+		Name string
+	}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("testdata"),
+		WithWorkingDir(dir),
+	)
+
+	if err := generator.Generate([]Tag{{ID: "tag-1", Name: "Go", Slug: "go"}}); err != nil {
+		t.Fatalf("Error generating tags: %v", err)
+	}
+	if err := generator.Generate([]Author{{ID: "author-1", Name: "Ana"}}); err != nil {
+		t.Fatalf("Error generating authors: %v", err)
+	}
+
+	if generator.TypeName != "Author" || generator.VarPrefix != "Author" {
+		t.Errorf("Expected settings inferred from Author, got TypeName %q and VarPrefix %q",
+			generator.TypeName, generator.VarPrefix)
+	}
+	if generator.OutputFile != "author_generated.go" {
+		t.Errorf("Expected OutputFile author_generated.go, got %q", generator.OutputFile)
+	}
+	if generator.PackageName != "testdata" {
+		t.Errorf("Expected configured PackageName to be kept, got %q", generator.PackageName)
+	}
+
+	for file, want := range map[string]string{
+		"tag_generated.go":    "var TagTag1 = Tag{",
+		"author_generated.go": "var AuthorAuthor1 = Author{",
+	} {
+		content, err := os.ReadFile(dir + "/" + file)
+		if err != nil {
+			t.Fatalf("Error reading %s: %v", file, err)
+		}
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in %s", want, file)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package genstruct
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// splitTypeArgs splits the name of an instantiated generic type, as given by
+// reflect (e.g. "Pair[string,example.com/blog.Tag]"), into the name of the
+// generic type and the type arguments. Names without type arguments are
+// returned as is.
+func splitTypeArgs(name string) (string, []string) {
+	open := strings.IndexByte(name, '[')
+	if open < 0 || !strings.HasSuffix(name, "]") {
+		return name, nil
+	}
+	return name[:open], splitTopLevel(name[open+1:len(name)-1], ',')
+}
+
+// splitTopLevel splits s at the separators not nested in brackets,
+// parentheses or braces
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := range len(s) {
+		switch s[i] {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// namedTypeStatement returns a reference to the type named name declared in
+// pkgPath, qualified with its package unless local. The type arguments of
+// instantiated generic types are rendered as types in turn, so that e.g.
+// Set[example.com/blog.Tag] becomes Set[blog.Tag] with blog imported.
+func (g *Generator) namedTypeStatement(pkgPath, name string, local bool) *jen.Statement {
+	base, args := splitTypeArgs(name)
+	stmt := jen.Id(base)
+	if !local {
+		stmt = g.qual(pkgPath, base)
+	}
+	if len(args) == 0 {
+		return stmt
+	}
+	types := make([]jen.Code, len(args))
+	for i, arg := range args {
+		types[i] = g.typeStringStatement(arg)
+	}
+	return stmt.Types(types...)
+}
+
+// typeStringStatement returns the type written as a string by reflect, such
+// as a type argument of a generic type (e.g. "map[string]*example.com/blog.Tag")
+func (g *Generator) typeStringStatement(s string) *jen.Statement {
+	switch {
+	case strings.HasPrefix(s, "*"):
+		return jen.Op("*").Add(g.typeStringStatement(s[1:]))
+	case strings.HasPrefix(s, "[]"):
+		return jen.Index().Add(g.typeStringStatement(s[2:]))
+	case strings.HasPrefix(s, "["):
+		length, elem, _ := strings.Cut(s[1:], "]")
+		return jen.Index(jen.Op(length)).Add(g.typeStringStatement(elem))
+	case strings.HasPrefix(s, "map["):
+		// The key ends at the bracket closing the one after map
+		depth := 0
+		for i := 3; i < len(s); i++ {
+			switch s[i] {
+			case '[':
+				depth++
+			case ']':
+				depth--
+				if depth == 0 {
+					return jen.Map(g.typeStringStatement(s[4:i])).Add(g.typeStringStatement(s[i+1:]))
+				}
+			}
+		}
+	case s == "interface {}":
+		return g.anyType()
+	}
+
+	// Qualified names have a package path ending at the last dot outside of
+	// the type arguments
+	base, _, _ := strings.Cut(s, "[")
+	dot := strings.LastIndexByte(base, '.')
+	if dot < 0 || strings.ContainsAny(base, " ({") {
+		// Predeclared types and type literals such as func() are kept as is
+		return jen.Op(s)
+	}
+	pkgPath, name := s[:dot], s[dot+1:]
+	return g.namedTypeStatement(pkgPath, name, g.isLocalPkg(pkgPath))
+}
+
+// typeIdent returns an identifier standing for a type name in generated
+// symbols. Instantiated generic types are named after the generic type and
+// their type arguments, e.g. BoxPairStringValues for
+// Box[example.com/x.Pair[string,net/url.Values]].
+func typeIdent(name string) string {
+	base, args := splitTypeArgs(name)
+	return base + typeArgsIdent(args)
+}
+
+// typeArgsIdent joins the names of the types in the type arguments, without
+// their packages, into an identifier
+func typeArgsIdent(args []string) string {
+	var b strings.Builder
+	for _, arg := range args {
+		for _, part := range strings.FieldsFunc(arg, func(r rune) bool {
+			return r == '[' || r == ']' || r == ',' || r == '*' || r == ' '
+		}) {
+			if dot := strings.LastIndexByte(part, '.'); dot >= 0 {
+				part = part[dot+1:]
+			}
+			part = strings.Map(func(r rune) rune {
+				if unicode.IsLetter(r) || unicode.IsDigit(r) {
+					return r
+				}
+				return -1
+			}, part)
+			if part != "" {
+				b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,156 @@
+package genstruct
+
+import (
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// GenericSet is a generic container type held by records
+type GenericSet[T comparable] map[T]struct{}
+
+// GenericPair is a generic struct type held by records
+type GenericPair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// GenericLabel is an instantiated reference dataset type
+type GenericLabel[T any] struct {
+	ID    string
+	Value T
+}
+
+// GenericBox is an instantiated record type holding nested instantiations
+type GenericBox[T any] struct {
+	ID       string
+	Item     T
+	Tags     GenericSet[string]
+	Pairs    []GenericPair[string, *url.URL]
+	Nested   GenericPair[GenericPair[string, int], []GenericSet[int]]
+	LabelIDs []string
+	Labels   []*GenericLabel[int] `structgen:"LabelIDs"`
+}
+
+// TestGenericTypes tests generating records, lookup maps and accessors for
+// instantiated generic types, including nested and qualified type arguments
+func TestGenericTypes(t *testing.T) {
+	boxes := []GenericBox[GenericPair[string, url.Values]]{{
+		ID:       "box-1",
+		Item:     GenericPair[string, url.Values]{Key: "q", Value: url.Values{"a": {"b"}}},
+		Tags:     GenericSet[string]{"x": {}},
+		Pairs:    []GenericPair[string, *url.URL]{{Key: "home", Value: &url.URL{Scheme: "https", Host: "example.com"}}},
+		Nested:   GenericPair[GenericPair[string, int], []GenericSet[int]]{Key: GenericPair[string, int]{Key: "n", Value: 1}},
+		LabelIDs: []string{"label-1"},
+	}}
+	labels := []GenericLabel[int]{{ID: "label-1", Value: 7}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("main"),
+		WithOutputFile("boxes.go"),
+		WithWorkingDir(dir),
+		WithLookupMaps("ID"),
+		WithAccessors(),
+	)
+	if err := generator.Generate(boxes, labels); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "boxes.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	expected := []string{
+		"var GenericBoxGenericPairStringValuesBox1 = GenericBox[GenericPair[string, url.Values]]{",
+		"Nested: GenericPair[GenericPair[string, int], []GenericSet[int]]{",
+		"Pairs: []GenericPair[string, *url.URL]{",
+		"Tags: map[string]struct{}{",
+		"func GetGenericBoxGenericPairStringValuesByID(id string) (*GenericBox[GenericPair[string, url.Values]], bool) {",
+		"var GenericLabelIntsByID = map[string]*GenericLabel[int]{",
+		"[]*GenericLabel[int]{&GenericLabelIntLabel1},",
+	}
+	for _, want := range expected {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in generated code", want)
+		}
+	}
+	if t.Failed() {
+		t.Logf("Generated output:\n%s", content)
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+	files := map[string]string{
+		"go.mod": "module generics\n\ngo 1.24\n",
+		"types.go": `package main
+
+import "net/url"
+
+type GenericSet[T comparable] map[T]struct{}
+
+type GenericPair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+type GenericLabel[T any] struct {
+	ID    string
+	Value T
+}
+
+type GenericBox[T any] struct {
+	ID       string
+	Item     T
+	Tags     GenericSet[string]
+	Pairs    []GenericPair[string, *url.URL]
+	Nested   GenericPair[GenericPair[string, int], []GenericSet[int]]
+	LabelIDs []string
+	Labels   []*GenericLabel[int]
+}
+`,
+		"main.go": `package main
+
+func main() {
+	box := MustGetGenericBoxGenericPairStringValuesByID("box-1")
+	if box.Item.Value.Get("a") != "b" || box.Pairs[0].Value.Host != "example.com" {
+		panic("box values differ")
+	}
+	if label, ok := GetGenericLabelIntByID("label-1"); !ok || box.Labels[0] != label || label.Value != 7 {
+		panic("label not referenced")
+	}
+}
+`,
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatalf("Error writing %s: %v", name, err)
+		}
+	}
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Generated program failed: %v\n%s", err, output)
+	}
+}
+
+// TestTypeIdent tests naming generated symbols after instantiated types
+func TestTypeIdent(t *testing.T) {
+	tests := map[string]string{
+		"Post":        "Post",
+		"Set[string]": "SetString",
+		"Box[example.com/x.Pair[string,*net/url.URL]]": "BoxPairStringURL",
+		"Grid[[]int,map[string]bool]":                  "GridIntMapStringBool",
+	}
+	for name, want := range tests {
+		if got := typeIdent(name); got != want {
+			t.Errorf("typeIdent(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -2,4 +2,12 @@ module github.com/conneroisu/genstruct
 
 go 1.24.0
 
-require github.com/dave/jennifer v1.7.1
+require (
+	github.com/dave/jennifer v1.7.1
+	golang.org/x/tools v0.30.0
+)
+
+require (
+	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+)
@@ -0,0 +1,105 @@
+package genstruct
+
+import (
+	"bytes"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// update rewrites the golden files with the current output
+var update = flag.Bool("update", false, "update the golden files in testdata/golden")
+
+// GoldenCategory is referenced by GoldenItem in the golden test
+type GoldenCategory struct {
+	ID   string
+	Name string
+}
+
+// GoldenItem covers the values whose rendering could vary between runs or Go
+// versions: maps, floats, times and references
+type GoldenItem struct {
+	ID          string
+	Name        string
+	Price       float64
+	Ratio       float32
+	Count       int
+	Created     time.Time
+	Labels      []string
+	Attributes  map[string]string
+	Scores      map[string]float64
+	CategoryIDs []string
+	Categories  []*GoldenCategory `structgen:"CategoryIDs"`
+}
+
+// TestGoldenOutput tests that the generated source is byte for byte equal to
+// the golden file and identical across runs. CI runs it on every supported
+// Go version; run it with -update to accept intended changes.
+func TestGoldenOutput(t *testing.T) {
+	items := []GoldenItem{
+		{
+			ID:         "item-1",
+			Name:       "First Item",
+			Price:      19.99,
+			Ratio:      0.1,
+			Count:      3,
+			Created:    time.Date(2024, time.February, 29, 12, 30, 0, 0, time.UTC),
+			Labels:     []string{"new", "sale"},
+			Attributes: map[string]string{"size": "L", "color": "red", "brand": "acme", "material": "wool"},
+			Scores:     map[string]float64{"quality": 4.5, "value": 1e-7, "speed": 1e21, "zero": math.Copysign(0, -1)},
+			CategoryIDs: []string{
+				"tools", "garden",
+			},
+		},
+		{
+			ID:          "item-2",
+			Name:        "Second Item",
+			Price:       1000000,
+			Ratio:       float32(1) / 3,
+			Created:     time.Date(1999, time.December, 31, 23, 59, 59, 999, time.UTC),
+			Attributes:  map[string]string{},
+			CategoryIDs: []string{"garden"},
+		},
+	}
+	categories := []GoldenCategory{
+		{ID: "tools", Name: "Tools"},
+		{ID: "garden", Name: "Garden"},
+	}
+
+	render := func() []byte {
+		src, err := NewGenerator(
+			WithPackageName("golden"),
+			WithOutputFile("items.go"),
+			WithIdentifierConstants(),
+			WithMapKeyConstants(),
+		).Render(items, categories)
+		if err != nil {
+			t.Fatalf("Error rendering code: %v", err)
+		}
+		return src
+	}
+
+	src := render()
+	for range 10 {
+		if again := render(); !bytes.Equal(again, src) {
+			t.Fatalf("Expected identical output across runs, got:\n%s\nand:\n%s", src, again)
+		}
+	}
+
+	golden := filepath.Join("testdata", "golden", "items.golden")
+	if *update {
+		if err := os.WriteFile(golden, src, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Error reading golden file, run with -update to create it: %v", err)
+	}
+	if !bytes.Equal(src, want) {
+		t.Errorf("Generated source differs from %s, run with -update if intended:\n%s", golden, src)
+	}
+}
@@ -0,0 +1,103 @@
+package genstruct
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// hashCommentPrefix prefixes the line of the package comment recording the
+// hash of the data and configuration the file was generated from
+const hashCommentPrefix = "// genstruct Hash: "
+
+// HashDataset returns a stable hash of the contents of a dataset (a slice or
+// array, or a pointer to one), suitable as a cache key for build tooling.
+//
+// The hash is canonical and type-aware: struct fields are hashed in name
+// order and map entries in key order, so it depends only on the data, not on
+// field declaration order or map iteration order. Unexported fields are ignored.
+func HashDataset(data any) (string, error) {
+	dataValue := reflect.ValueOf(data)
+	if dataValue.Kind() == reflect.Pointer {
+		dataValue = dataValue.Elem()
+	}
+	if dataValue.Kind() != reflect.Slice && dataValue.Kind() != reflect.Array {
+		return "", NonSliceOrArrayError{Kind: dataValue.Kind()}
+	}
+
+	sum := sha256.Sum256([]byte(canonicalString(dataValue)))
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// generationHash returns a hash of the primary data, the reference data, and
+// the generator configuration, identifying the inputs of a generation run
+func (g *Generator) generationHash() (string, error) {
+	hash := sha256.New()
+
+	dataHash, err := HashDataset(g.Data)
+	if err != nil {
+		return "", err
+	}
+	hash.Write([]byte(dataHash))
+
+	refNames := make([]string, 0, len(g.Refs))
+	for name := range g.Refs {
+		refNames = append(refNames, name)
+	}
+	sort.Strings(refNames)
+	for _, name := range refNames {
+		refHash, err := HashDataset(g.Refs[name])
+		if err != nil {
+			return "", err
+		}
+		hash.Write([]byte(name + refHash))
+	}
+
+	// Asset contents end up in the output of WithAssetHash fields
+	hash.Write([]byte(g.assetSumsString()))
+
+	// So do attribution sidecar files
+	hash.Write([]byte(g.sidecarString()))
+
+	// Upgrading genstruct may change the generated code for the same inputs
+	version, err := genstructVersion()
+	if err != nil {
+		return "", err
+	}
+	hash.Write([]byte(version))
+
+	// Configuration changes must invalidate the hash too, except the settings
+	// deciding whether files are written, so that dry runs, Render, and Verify
+	// see the same output a real run writes. Function options are only hashed
+	// as set or unset, see WithSkipUnchanged.
+	config := canonicalString(reflect.ValueOf(*g), "Data", "Refs", "File", "FuncFile", "RefFiles", "Hash", "Diff", "Report", "DryRun", "NoWrite", "SkipUnchanged", "Logger", "OutputFS")
+	hash.Write([]byte(config))
+
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// readGeneratedHash returns the hash recorded in a previously generated file,
+// or an empty string if the file does not exist or has no hash
+func readGeneratedHash(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, hashCommentPrefix) {
+			return strings.TrimPrefix(line, hashCommentPrefix)
+		}
+		if strings.HasPrefix(line, "package ") {
+			break
+		}
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package genstruct
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestHashDataset tests that dataset hashes are stable and content sensitive
+func TestHashDataset(t *testing.T) {
+	type Article struct {
+		ID   string
+		Meta map[string]string
+	}
+	type ReorderedArticle struct {
+		Meta map[string]string
+		ID   string
+	}
+
+	first, err := HashDataset([]Article{{ID: "a", Meta: map[string]string{"x": "1", "y": "2"}}})
+	if err != nil {
+		t.Fatalf("Error hashing dataset: %v", err)
+	}
+	second, err := HashDataset(&[]Article{{ID: "a", Meta: map[string]string{"y": "2", "x": "1"}}})
+	if err != nil {
+		t.Fatalf("Error hashing dataset: %v", err)
+	}
+	if first != second {
+		t.Errorf("Expected equal hashes for equal data, got %s and %s", first, second)
+	}
+
+	changed, _ := HashDataset([]Article{{ID: "b", Meta: map[string]string{"x": "1", "y": "2"}}})
+	if changed == first {
+		t.Error("Expected different hashes for different data")
+	}
+
+	reordered, _ := HashDataset([]ReorderedArticle{{ID: "a", Meta: map[string]string{"x": "1", "y": "2"}}})
+	if reordered == first {
+		t.Error("Expected different hashes for different types")
+	}
+
+	// The same instant in another location may be rendered differently
+	instant := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	utc, _ := HashDataset([]time.Time{instant})
+	fixed, _ := HashDataset([]time.Time{instant.In(time.FixedZone("CET", 3600))})
+	if utc == fixed {
+		t.Error("Expected different hashes for times in different locations")
+	}
+
+	if _, err := HashDataset("not a slice"); err == nil {
+		t.Error("Expected error for non-slice data, got nil")
+	}
+}
+
+// TestSkipUnchanged tests that unchanged outputs are not rewritten
+func TestSkipUnchanged(t *testing.T) {
+	tags := []Tag{{ID: "tag-1", Name: "Go", Slug: "go"}}
+
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, "tags.go")
+	generate := func() {
+		generator := NewGenerator(
+			WithPackageName("tags"),
+			WithOutputFile("tags.go"),
+			WithWorkingDir(dir),
+			WithSkipUnchanged(),
+		)
+		if err := generator.Generate(tags); err != nil {
+			t.Fatalf("Error generating code: %v", err)
+		}
+	}
+
+	generate()
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(outputFile, past, past); err != nil {
+		t.Fatalf("Error changing file times: %v", err)
+	}
+
+	generate()
+	info, err := os.Stat(outputFile)
+	if err != nil {
+		t.Fatalf("Error reading output file: %v", err)
+	}
+	if !info.ModTime().Equal(past) {
+		t.Error("Expected unchanged output file to not be rewritten")
+	}
+}
@@ -0,0 +1,50 @@
+package genstruct
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestHeaderTemplate tests that the header template is rendered below the
+// generated code marker and that invalid templates are rejected
+func TestHeaderTemplate(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	posts := []Post{
+		{ID: "post-1", Title: "Intro"},
+		{ID: "post-2", Title: "Testing"},
+	}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithHeaderTemplate("Copyright {{.Year}} Example Corp.\n\n{{.Tool}} generated {{.Count}} {{.Type}} records\n"),
+	)
+	if err := generator.Generate(posts); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "posts.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	header := generatedHeader + "\n// Copyright 2023 Example Corp.\n//\n// genstruct generated 2 Post records\n\n// Package blog"
+	if !strings.HasPrefix(string(content), header) {
+		t.Errorf("Expected generated file to start with %q, got:\n%s", header, content)
+	}
+
+	for _, tmpl := range []string{"Copyright {{.Year", "Copyright {{.Owner}}"} {
+		err := NewGenerator(
+			WithPackageName("blog"),
+			WithOutputFile("posts.go"),
+			WithWorkingDir(dir),
+			WithHeaderTemplate(tmpl),
+		).Generate(posts)
+		if err == nil || !strings.Contains(err.Error(), "header") {
+			t.Errorf("Expected a header template error for %q, got %v", tmpl, err)
+		}
+	}
+}
@@ -0,0 +1,481 @@
+package genstruct
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestIdentifierConstants tests that one constant and lookup map is generated per identifier field
+func TestIdentifierConstants(t *testing.T) {
+	type Post struct {
+		ID    string
+		Slug  string
+		Title string
+	}
+
+	posts := []Post{
+		{ID: "post-1", Slug: "intro-to-go", Title: "Intro to Go"},
+		{ID: "post-2", Slug: "testing", Title: "Testing"},
+	}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID", "Title"}),
+		WithIdentifierConstants(),
+	)
+	if err := generator.Generate(posts); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "posts.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+
+	expected := []string{
+		`PostIntroToGoID = "post-1"`,
+		`PostIntroToGoSlug  = "intro-to-go"`,
+		`PostIntroToGoTitle = "Intro to Go"`,
+		"var PostsBySlug = map[string]*Post{",
+		`"intro-to-go": &PostIntroToGo,`,
+		"var PostsByID = map[string]*Post{",
+		"var PostsByTitle = map[string]*Post{",
+	}
+	for _, want := range expected {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in generated code", want)
+		}
+	}
+}
+
+// TestLookupMaps tests that lookup maps are generated by ID for every dataset,
+// by the given fields, and only once alongside identifier constants
+func TestLookupMaps(t *testing.T) {
+	tags := []Tag{{ID: "tag-1", Name: "Go", Slug: "go"}}
+	posts := []Post{
+		{ID: "post-1", Title: "Intro", TagSlugs: []string{"go"}},
+		{ID: "post-2", Title: "Testing"},
+	}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected []string
+		absent   []string
+	}{
+		{
+			name: "by ID",
+			opts: []Option{WithLookupMaps()},
+			expected: []string{
+				"var PostsByID = map[string]*Post{",
+				`"post-2": &PostPost2,`,
+				"var TagsByID = map[string]*Tag{",
+				`"tag-1": &TagGo`,
+			},
+			absent: []string{"PostsBySlug", "TagsBySlug"},
+		},
+		{
+			name:     "by fields",
+			opts:     []Option{WithLookupMaps("ID", "Slug")},
+			expected: []string{"var PostsByID = map[string]*Post{", "var TagsBySlug = map[string]*Tag{"},
+			absent:   []string{"PostsBySlug"},
+		},
+		{
+			name:     "with identifier constants",
+			opts:     []Option{WithLookupMaps(), WithIdentifierConstants()},
+			expected: []string{"var TagsBySlug = map[string]*Tag{"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			opts := append([]Option{
+				WithPackageName("blog"),
+				WithOutputFile("posts.go"),
+				WithWorkingDir(dir),
+				WithIdentifierFields([]string{"Slug", "ID"}),
+			}, tt.opts...)
+			if err := NewGenerator(opts...).Generate(posts, tags); err != nil {
+				t.Fatalf("Error generating code: %v", err)
+			}
+
+			content, err := os.ReadFile(filepath.Join(dir, "posts.go"))
+			if err != nil {
+				t.Fatalf("Error reading generated file: %v", err)
+			}
+			output := string(content)
+			for _, want := range tt.expected {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected to find %q in generated code", want)
+				}
+			}
+			for _, unwanted := range tt.absent {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("Expected not to find %q in generated code", unwanted)
+				}
+			}
+			if count := strings.Count(output, "var TagsByID ="); count != 1 {
+				t.Errorf("Expected TagsByID to be declared once, got %d", count)
+			}
+			if t.Failed() {
+				t.Logf("Generated output:\n%s", output)
+			}
+		})
+	}
+}
+
+// TestAccessors tests that the generated accessor functions look records up
+// by their lookup map fields and panic on unknown keys
+func TestAccessors(t *testing.T) {
+	tags := []Tag{{ID: "tag-1", Name: "Go", Slug: "go"}}
+	posts := []Post{{ID: "post-1", Title: "Intro", TagSlugs: []string{"go"}}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("main"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+		WithLookupMaps("ID", "Slug"),
+		WithAccessors(),
+	)
+	if err := generator.Generate(posts, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "posts.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	expected := []string{
+		"func GetPostByID(id string) (*Post, bool) {",
+		"func MustGetPostByID(id string) *Post {",
+		"func GetTagBySlug(slug string) (*Tag, bool) {",
+		"record, ok := TagsBySlug[slug]",
+	}
+	for _, want := range expected {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in generated code", want)
+		}
+	}
+	if t.Failed() {
+		t.Logf("Generated output:\n%s", content)
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+	files := map[string]string{
+		"go.mod": "module accessors\n\ngo 1.24\n",
+		"types.go": `package main
+
+import "time"
+
+type Tag struct {
+	ID   string
+	Name string
+	Slug string
+}
+
+type Post struct {
+	ID       string
+	Title    string
+	Date     time.Time
+	TagSlugs []string
+	Tags     []*Tag
+}
+`,
+		"main.go": `package main
+
+func main() {
+	if post, ok := GetPostByID("post-1"); !ok || post != &PostPost1 {
+		panic("post-1 not found")
+	}
+	if _, ok := GetTagBySlug("rust"); ok {
+		panic("unexpected tag rust")
+	}
+	if MustGetTagBySlug("go") != &TagGo {
+		panic("tag go not found")
+	}
+	defer func() {
+		if recover() == nil {
+			panic("expected MustGetPostByID to panic")
+		}
+	}()
+	MustGetPostByID("post-2")
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Error writing %s: %v", name, err)
+		}
+	}
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Generated program failed: %v\n%s", err, output)
+	}
+}
+
+// TestOpaqueNames tests that variable names are hashed and a name map is generated
+func TestOpaqueNames(t *testing.T) {
+	type Post struct {
+		ID    string
+		Title string
+	}
+	posts := []Post{{ID: "secret-launch", Title: "Secret Launch"}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithOpaqueNames(),
+	)
+	if err := generator.Generate(posts); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "posts.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+
+	varName := "Post" + opaqueIdentifier("secret-launch")
+	if strings.Contains(string(content), "PostSecretLaunch") {
+		t.Error("Expected human-readable variable name to be replaced")
+	}
+	expected := []string{
+		"var " + varName + " = Post{",
+		varName + "ID",
+		`"secret-launch": &` + varName,
+		"var PostsByOriginalName = map[string]*Post{",
+	}
+	for _, want := range expected {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in generated code:\n%s", want, content)
+		}
+	}
+}
+
+// TestMapKeyConstants tests that constants are generated for map keys
+func TestMapKeyConstants(t *testing.T) {
+	type Post struct {
+		ID       string
+		Metadata map[string]string
+	}
+	posts := []Post{
+		{ID: "intro", Metadata: map[string]string{"author": "conner", "reading-time": "5m"}},
+		{ID: "testing", Metadata: map[string]string{"author": "ana"}},
+	}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithMapKeyConstants(),
+	)
+	if err := generator.Generate(posts); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "posts.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+
+	expected := []string{
+		`PostMetadataKeyAuthor      = "author"`,
+		`PostMetadataKeyReadingTime = "reading-time"`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in generated code:\n%s", want, content)
+		}
+	}
+
+	posts[1].Metadata["reading_time"] = "7m"
+	var duplicateErr DuplicateNameError
+	if err := generator.Generate(posts); !errors.As(err, &duplicateErr) || duplicateErr.Name != "PostMetadataKeyReadingTime" {
+		t.Errorf("Expected DuplicateNameError for keys with the same name, got %v", err)
+	}
+
+	// Key constants are protected from reserved names like other symbols
+	delete(posts[1].Metadata, "reading_time")
+	posts[0].Metadata["type"] = "article"
+	generator = NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithMapKeyConstants(),
+		WithReservedNames("PostMetadataKeyType"),
+	)
+	var reservedErr ReservedNameError
+	if err := generator.Generate(posts); !errors.As(err, &reservedErr) || reservedErr.Name != "PostMetadataKeyType" {
+		t.Errorf("Expected ReservedNameError for a reserved key constant, got %v", err)
+	}
+
+	generator = NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithMapKeyConstants(),
+		WithReservedNames("PostMetadataKeyType"),
+		WithRenameReserved(),
+	)
+	if err := generator.Generate(posts); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	content, err = os.ReadFile(filepath.Join(dir, "posts.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	if !strings.Contains(string(content), `PostMetadataKeyType_       = "type"`) {
+		t.Errorf("Expected the reserved key constant to be renamed:\n%s", content)
+	}
+}
+
+// TestSyntheticIDs tests that records without IDs carry their synthetic ID in
+// both the constant and the variable
+func TestSyntheticIDs(t *testing.T) {
+	type Note struct {
+		ID   string
+		Name string
+	}
+	notes := []Note{{ID: "note-a", Name: "First"}, {Name: "Second"}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("notes"),
+		WithOutputFile("notes.go"),
+		WithWorkingDir(dir),
+	)
+	if err := generator.Generate(notes); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	content, err := os.ReadFile(dir + "/notes.go")
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	for _, want := range []string{
+		`NoteSecondID = "note-2"`,
+		"var NoteSecond = Note{\n\tID:   \"note-2\",",
+		"var NoteNoteA = Note{\n\tID:   \"note-a\",",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in generated code:\n%s", want, content)
+		}
+	}
+
+	generator = NewGenerator(
+		WithPackageName("notes"),
+		WithOutputFile("notes.go"),
+		WithWorkingDir(dir),
+		WithSyntheticIDs(func(typeName string, index int) string {
+			return fmt.Sprintf("%s_%03d", typeName, index)
+		}),
+	)
+	if err := generator.Generate(notes); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	content, err = os.ReadFile(dir + "/notes.go")
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	if !strings.Contains(string(content), `ID:   "Note_001",`) {
+		t.Errorf("Expected custom synthetic ID in generated code:\n%s", content)
+	}
+}
+
+// TestConstantRefs tests that reference keys are emitted as the constants of
+// the referenced records
+func TestConstantRefs(t *testing.T) {
+	type Author struct {
+		ID   string
+		Name string
+	}
+	type Tag struct {
+		ID   string
+		Slug string
+	}
+	type Post struct {
+		ID       string
+		Title    string
+		AuthorID string
+		Author   *Author `structgen:"AuthorID"`
+		TagSlugs []string
+		Tags     []*Tag `structgen:"TagSlugs,match=Slug"`
+		TagIDs   []string
+		TagList  []Tag `structgen:"TagIDs"`
+	}
+
+	posts := []Post{{
+		ID:       "post-1",
+		Title:    "Hello",
+		AuthorID: "alice",
+		TagSlugs: []string{"go"},
+		TagIDs:   []string{"tag-1", "missing"},
+	}}
+	authors := []Author{{ID: "alice", Name: "Alice"}}
+	tags := []Tag{{ID: "tag-1", Slug: "go"}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Name", "Slug", "Title", "ID"}),
+		WithConstantRefs(),
+	)
+	if err := generator.Generate(posts, authors, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "posts.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	output := string(content)
+	for _, want := range []string{
+		`AuthorAliceID = "alice"`,
+		"AuthorID: AuthorAliceID,",
+		`TagIDs:   []string{TagGoID, "missing"},`,
+		// Slugs have no constant without WithIdentifierConstants
+		`TagSlugs: []string{"go"},`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected to find %q in generated code:\n%s", want, output)
+		}
+	}
+
+	generator = NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts_slugs.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Name", "Slug", "Title", "ID"}),
+		WithIdentifierConstants(),
+		WithConstantRefs(),
+	)
+	if err := generator.Generate(posts, authors, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	content, err = os.ReadFile(filepath.Join(dir, "posts_slugs.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	if want := "TagSlugs: []string{TagGoSlug},"; !strings.Contains(string(content), want) {
+		t.Errorf("Expected to find %q in generated code:\n%s", want, content)
+	}
+}
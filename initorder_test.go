@@ -0,0 +1,87 @@
+package genstruct
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestReferenceWiringBeforeInit tests that references between generated
+// variables are wired before user init functions run, regardless of the order
+// of the files in the package.
+//
+// References are emitted as package-level variable initializers (e.g.
+// Tags: []*Tag{&TagGo}), which Go initializes in dependency order across all
+// files of a package before any init function runs.
+func TestReferenceWiringBeforeInit(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	tags := []Tag{{ID: "tag-1", Name: "Go", Slug: "go"}}
+	posts := []Post{{
+		ID:       "post-1",
+		Title:    "Testing in Go",
+		Date:     time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		TagSlugs: []string{"go"},
+	}}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module initorder\n\ngo 1.24\n",
+		"types.go": `package main
+
+import "time"
+
+type Tag struct {
+	ID   string
+	Name string
+	Slug string
+}
+
+type Post struct {
+	ID       string
+	Title    string
+	Date     time.Time
+	TagSlugs []string
+	Tags     []*Tag
+}
+`,
+		// Named so that it sorts before the generated files
+		"a_main.go": `package main
+
+func init() {
+	if len(PostPost1.Tags) != 1 || PostPost1.Tags[0] != &TagGo || AllTags[0].Name != "Go" {
+		panic("references were not wired before init")
+	}
+}
+
+func main() {}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Error writing %s: %v", name, err)
+		}
+	}
+
+	generator := NewGenerator(
+		WithPackageName("main"),
+		WithOutputFile("z_posts.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+	)
+	if err := generator.Generate(posts, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Generated program failed: %v\n%s", err, output)
+	}
+}
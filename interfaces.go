@@ -0,0 +1,119 @@
+package genstruct
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// generateInterfaceSlice generates a slice of an interface type for string
+// slice to interface slice references (e.g. []ContentItem), resolving each
+// key against the reference datasets implementing the interface
+func (g *Generator) generateInterfaceSlice(srcValue reflect.Value, targetType reflect.Type, tag structgenTag) *jen.Statement {
+	iface := targetType.Elem()
+	refTypes := g.implementingRefs(iface)
+	return g.getTypeStatement(targetType).ValuesFunc(func(group *jen.Group) {
+		for i := range srcValue.Len() {
+			key := srcValue.Index(i).String()
+			if ref, ok := g.interfaceRef(refTypes, key, iface, tag); ok {
+				group.Add(ref)
+			} else if tag.Strict {
+				g.addGenError(UnresolvedReferenceError{Record: g.currentRecord, Field: tag.Field, Key: key})
+			}
+		}
+	})
+}
+
+// generateInterfaceSingle generates the value of an interface field for a
+// string to interface reference, or nil when no record matches
+func (g *Generator) generateInterfaceSingle(srcValue reflect.Value, iface reflect.Type, tag structgenTag) *jen.Statement {
+	return g.interfaceRefOrNil(g.implementingRefs(iface), srcValue.String(), iface, tag)
+}
+
+// generateDiscriminatedReference generates the value of an interface field
+// resolved against the reference dataset named by the typeField of the tag
+// (e.g. RefType "Post" with RefID "hello" gives &PostHello). Type names are
+// compared ignoring case, and an empty type gives nil. tagValue is the raw
+// tag, reported when the type field is invalid.
+func (g *Generator) generateDiscriminatedReference(structValue, srcValue reflect.Value, iface reflect.Type, tag structgenTag, tagValue string) *jen.Statement {
+	typeField, found := structValue.Type().FieldByName(tag.TypeField)
+	if !found || typeField.Type.Kind() != reflect.String {
+		g.addGenError(StructgenTagError{
+			Field:  tag.Field,
+			Tag:    tagValue,
+			Reason: "type field " + tag.TypeField + " is not a string field",
+		})
+		return nil
+	}
+	typeValue := structValue.FieldByIndex(typeField.Index).String()
+	if typeValue == "" {
+		return jen.Nil()
+	}
+
+	var refTypes []string
+	for _, typeName := range g.implementingRefs(iface) {
+		if strings.EqualFold(typeName, typeValue) {
+			refTypes = []string{typeName}
+			break
+		}
+	}
+	return g.interfaceRefOrNil(refTypes, srcValue.String(), iface, tag)
+}
+
+// interfaceRefOrNil returns the reference found by interfaceRef, or nil when
+// no record matches, which is an error for strict references
+func (g *Generator) interfaceRefOrNil(refTypes []string, key string, iface reflect.Type, tag structgenTag) *jen.Statement {
+	if ref, ok := g.interfaceRef(refTypes, key, iface, tag); ok {
+		return ref
+	}
+	if tag.Strict {
+		g.addGenError(UnresolvedReferenceError{Record: g.currentRecord, Field: tag.Field, Key: key})
+	}
+	return jen.Nil()
+}
+
+// implementingRefs returns the names of the reference datasets whose struct
+// type, or a pointer to it, implements iface, sorted by name
+func (g *Generator) implementingRefs(iface reflect.Type) []string {
+	g.loadAllRefs()
+	var typeNames []string
+	for _, typeName := range slices.Sorted(maps.Keys(g.Refs)) {
+		dataType := reflect.TypeOf(g.Refs[typeName])
+		if dataType == nil || (dataType.Kind() != reflect.Slice && dataType.Kind() != reflect.Array) {
+			continue
+		}
+		structType := dataType.Elem()
+		if structType.Kind() == reflect.Pointer {
+			structType = structType.Elem()
+		}
+		if structType.Kind() == reflect.Struct && reflect.PointerTo(structType).Implements(iface) {
+			typeNames = append(typeNames, typeName)
+		}
+	}
+	return typeNames
+}
+
+// interfaceRef returns the generated variable of the record matching key in
+// the first of the refTypes datasets holding one. The variable is used as is
+// when its struct type implements iface, and by address when only the
+// pointer does.
+func (g *Generator) interfaceRef(refTypes []string, key string, iface reflect.Type, tag structgenTag) (*jen.Statement, bool) {
+	for _, typeName := range refTypes {
+		refStruct, _, found := g.findReference(reflect.ValueOf(g.Refs[typeName]), key, tag)
+		if !found {
+			continue
+		}
+		varName := g.refVarName(typeName, refStruct)
+		if g.prunedReference(varName) {
+			return jen.Nil(), true
+		}
+		if refStruct.Type().Implements(iface) {
+			return jen.Id(varName), true
+		}
+		return jen.Op("&").Id(varName), true
+	}
+	return nil, false
+}
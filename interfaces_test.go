@@ -0,0 +1,135 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ContentItem is implemented by the datasets of TestInterfaceReferences
+type ContentItem interface {
+	ItemTitle() string
+}
+
+type Article struct {
+	ID    string
+	Title string
+}
+
+func (a Article) ItemTitle() string { return a.Title }
+
+type Video struct {
+	ID  string
+	URL string
+}
+
+func (v *Video) ItemTitle() string { return v.URL }
+
+type Playlist struct {
+	ID       string
+	ItemIDs  []string
+	Items    []ContentItem `structgen:"ItemIDs,strict"`
+	Featured string
+	Feature  ContentItem `structgen:"Featured"`
+}
+
+// TestInterfaceReferences tests that interface references resolve across
+// every reference dataset implementing the interface
+func TestInterfaceReferences(t *testing.T) {
+	playlists := []Playlist{{ID: "mix", ItemIDs: []string{"intro", "clip"}, Featured: "clip"}}
+	articles := []Article{{ID: "intro", Title: "Intro"}}
+	videos := []*Video{{ID: "clip", URL: "https://example.com/clip"}}
+	tags := []Tag{{ID: "intro"}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("media"),
+		WithOutputFile("playlists.go"),
+		WithWorkingDir(dir),
+	)
+	if err := generator.Generate(playlists, articles, videos, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "playlists.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	output := string(content)
+	for _, want := range []string{
+		"Items:    []ContentItem{ArticleIntro, &VideoClip},",
+		"Feature:  &VideoClip,",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected to find %q in generated code:\n%s", want, output)
+		}
+	}
+
+	playlists[0].ItemIDs = []string{"missing"}
+	generator = NewGenerator(
+		WithPackageName("media"),
+		WithOutputFile("broken.go"),
+		WithWorkingDir(dir),
+	)
+	var unresolved UnresolvedReferenceError
+	if err := generator.Generate(playlists, articles, videos); !errors.As(err, &unresolved) {
+		t.Errorf("Expected UnresolvedReferenceError for a strict interface reference, got %v", err)
+	}
+}
+
+type Bookmark struct {
+	ID      string
+	RefType string
+	RefID   string
+	Target  ContentItem `structgen:"RefID,typeField=RefType,strict"`
+}
+
+// TestDiscriminatedReferences tests that a type field picks the dataset an
+// interface reference resolves against
+func TestDiscriminatedReferences(t *testing.T) {
+	bookmarks := []Bookmark{
+		{ID: "first", RefType: "article", RefID: "shared"},
+		{ID: "second", RefType: "Video", RefID: "shared"},
+		{ID: "none"},
+	}
+	articles := []Article{{ID: "shared", Title: "Shared"}}
+	videos := []Video{{ID: "shared", URL: "https://example.com/shared"}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("media"),
+		WithOutputFile("bookmarks.go"),
+		WithWorkingDir(dir),
+	)
+	if err := generator.Generate(bookmarks, articles, videos); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "bookmarks.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	output := string(content)
+	for _, want := range []string{
+		"Target:  ArticleShared,",
+		"Target:  &VideoShared,",
+		"Target:  nil,",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected to find %q in generated code:\n%s", want, output)
+		}
+	}
+
+	bookmarks = []Bookmark{{ID: "podcast", RefType: "Podcast", RefID: "shared"}}
+	generator = NewGenerator(
+		WithPackageName("media"),
+		WithOutputFile("broken.go"),
+		WithWorkingDir(dir),
+	)
+	var unresolved UnresolvedReferenceError
+	if err := generator.Generate(bookmarks, articles, videos); !errors.As(err, &unresolved) {
+		t.Errorf("Expected UnresolvedReferenceError for an unknown type, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package genstruct
+
+import (
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// LazyRef is a reference dataset that is only loaded when a structgen field
+// actually references its type. Create one with Lazy or LazyNamed and pass it
+// to Generate like any other reference dataset.
+type LazyRef struct {
+	TypeName string     // Name of the struct type the loader returns
+	Load     func() any // Loader returning a slice or array of structs
+
+	elemType reflect.Type // Struct type the loader returns, if known
+}
+
+// Lazy wraps a typed loader function as a lazily loaded reference dataset.
+// The element type T may be a struct or a pointer to a struct.
+//
+//	err := generator.Generate(posts, genstruct.Lazy(loadTags))
+func Lazy[T any](loader func() []T) LazyRef {
+	elemType := reflect.TypeOf((*T)(nil)).Elem()
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	return LazyRef{
+		TypeName: elemType.Name(),
+		Load:     func() any { return loader() },
+		elemType: elemType,
+	}
+}
+
+// LazyNamed wraps an untyped loader function as a lazily loaded reference
+// dataset for the named struct type.
+func LazyNamed(typeName string, loader func() any) LazyRef {
+	return LazyRef{TypeName: typeName, Load: loader}
+}
+
+// refKey returns the key of the reference dataset of the struct type t in
+// Refs: its name, or its package-qualified name (e.g. "pkga.Tag") when it
+// shares the name with the type of another dataset
+func (g *Generator) refKey(t reflect.Type) string {
+	if key, ok := g.refKeys[t]; ok {
+		return key
+	}
+	return t.Name()
+}
+
+// refTypeIdent returns the identifier a reference dataset key stands for in
+// generated symbols: the type name, or the package and type names joined for
+// package-qualified keys (e.g. PkgaTag for pkga.Tag)
+func refTypeIdent(key string) string {
+	key, args := splitTypeArgs(key)
+	if !strings.ContainsAny(key, "./") {
+		return key + typeArgsIdent(args)
+	}
+	parts := strings.FieldsFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, part := range parts {
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+	return strings.Join(parts, "") + typeArgsIdent(args)
+}
+
+// refData returns the reference dataset for the named struct type, loading it
+// first if it was provided lazily
+func (g *Generator) refData(typeName string) (any, bool) {
+	if data, ok := g.Refs[typeName]; ok {
+		return data, true
+	}
+
+	lazyRef, ok := g.lazyRefs[typeName]
+	if !ok {
+		return nil, false
+	}
+	g.Logger.Debug(
+		"Loading lazy reference dataset",
+		slog.String("type", typeName),
+	)
+	data := g.dropSkippedRecords(g.unwrapPointer(lazyRef.Load()))
+	delete(g.lazyRefs, typeName)
+	g.Refs[typeName] = data
+	return data, true
+}
+
+// loadAllRefs loads every lazily provided reference dataset
+func (g *Generator) loadAllRefs() {
+	for typeName := range g.lazyRefs {
+		g.refData(typeName)
+	}
+}
+
+// pendingRefs returns the names of the loaded reference datasets that have
+// not been processed yet, in sorted order
+func (g *Generator) pendingRefs(processed map[string]bool) []string {
+	var pending []string
+	for typeName := range g.Refs {
+		if !processed[typeName] {
+			pending = append(pending, typeName)
+		}
+	}
+	sort.Strings(pending)
+	return pending
+}
+
+// refDatasets returns the loaded reference datasets ordered by type name, so
+// everything derived from them is independent of map iteration order
+func (g *Generator) refDatasets() []any {
+	datasets := make([]any, 0, len(g.Refs))
+	for _, typeName := range slices.Sorted(maps.Keys(g.Refs)) {
+		datasets = append(datasets, g.Refs[typeName])
+	}
+	return datasets
+}
@@ -0,0 +1,113 @@
+package genstruct
+
+import (
+	"bytes"
+	"io/fs"
+)
+
+// Decoder unmarshals data into v. json.Unmarshal has this signature, as do
+// the Unmarshal functions of most YAML and TOML packages.
+type Decoder func(data []byte, v any) error
+
+// LoadFS decodes every file in fsys matching pattern into one record each,
+// ordered by path. It pairs with an embed.FS so generation tooling can carry
+// its source content and run hermetically:
+//
+//	//go:embed content/animals/*.json
+//	var content embed.FS
+//
+//	animals, err := genstruct.LoadFS[Animal](content, "content/animals/*.json", json.Unmarshal)
+func LoadFS[T any](fsys fs.FS, pattern string, decode Decoder) ([]T, error) {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]T, 0, len(names))
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		var record T
+		if err := decode(data, &record); err != nil {
+			return nil, LoadError{Path: name, Err: err}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// LoadFileFS decodes a single file in fsys that holds a list of records.
+func LoadFileFS[T any](fsys fs.FS, name string, decode Decoder) ([]T, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	var records []T
+	if err := decode(data, &records); err != nil {
+		return nil, LoadError{Path: name, Err: err}
+	}
+	return records, nil
+}
+
+// LoadMarkdownFS loads every markdown file in fsys matching pattern into one
+// record each, ordered by path. The front matter between leading "---" lines
+// is decoded into the record and the remaining content is passed to setBody,
+// which may be nil when the body isn't needed. Files without front matter are
+// treated as body only.
+func LoadMarkdownFS[T any](fsys fs.FS, pattern string, decode Decoder, setBody func(record *T, body string)) ([]T, error) {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]T, 0, len(names))
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		record, body, err := decodeMarkdown[T](data, decode)
+		if err != nil {
+			return nil, LoadError{Path: name, Err: err}
+		}
+		if setBody != nil {
+			setBody(&record, body)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// decodeMarkdown decodes the front matter of a markdown document into a
+// record and returns it with the body
+func decodeMarkdown[T any](data []byte, decode Decoder) (T, string, error) {
+	var record T
+	frontMatter, body, ok := splitFrontMatter(data)
+	if ok {
+		if err := decode(frontMatter, &record); err != nil {
+			return record, "", err
+		}
+	}
+	return record, string(body), nil
+}
+
+// splitFrontMatter splits a markdown document into its "---" delimited front
+// matter and body, reporting whether front matter was present
+func splitFrontMatter(data []byte) (frontMatter, body []byte, ok bool) {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	rest, found := bytes.CutPrefix(data, []byte("---\n"))
+	if !found {
+		return nil, data, false
+	}
+	if after, found := bytes.CutPrefix(rest, []byte("---\n")); found {
+		return nil, bytes.TrimLeft(after, "\n"), true
+	}
+	frontMatter, body, found = bytes.Cut(rest, []byte("\n---\n"))
+	if !found {
+		if !bytes.HasSuffix(rest, []byte("\n---")) {
+			return nil, data, false
+		}
+		frontMatter, body = bytes.TrimSuffix(rest, []byte("\n---")), nil
+	}
+	return frontMatter, bytes.TrimLeft(body, "\n"), true
+}
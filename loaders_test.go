@@ -0,0 +1,64 @@
+package genstruct
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+// TestLoadFS tests loading records from per-file, list and markdown content
+// in an fs.FS
+func TestLoadFS(t *testing.T) {
+	type Post struct {
+		ID    string
+		Title string
+		Body  string
+	}
+	fsys := fstest.MapFS{
+		"posts/b.json":  {Data: []byte(`{"ID": "post-2", "Title": "Second"}`)},
+		"posts/a.json":  {Data: []byte(`{"ID": "post-1", "Title": "First"}`)},
+		"posts.json":    {Data: []byte(`[{"ID": "post-1"}, {"ID": "post-2"}]`)},
+		"posts/a.md":    {Data: []byte("---\n{\"ID\": \"post-1\", \"Title\": \"First\"}\n---\n\n# First\n")},
+		"posts/b.md":    {Data: []byte("# No front matter\n")},
+		"broken/x.json": {Data: []byte(`{`)},
+	}
+
+	posts, err := LoadFS[Post](fsys, "posts/*.json", json.Unmarshal)
+	if err != nil {
+		t.Fatalf("Error loading posts: %v", err)
+	}
+	if len(posts) != 2 || posts[0].ID != "post-1" || posts[1].Title != "Second" {
+		t.Errorf("Expected posts ordered by path, got %+v", posts)
+	}
+
+	list, err := LoadFileFS[Post](fsys, "posts.json", json.Unmarshal)
+	if err != nil {
+		t.Fatalf("Error loading post list: %v", err)
+	}
+	if len(list) != 2 || list[1].ID != "post-2" {
+		t.Errorf("Expected two posts from the list file, got %+v", list)
+	}
+
+	markdown, err := LoadMarkdownFS(fsys, "posts/*.md", json.Unmarshal, func(p *Post, body string) {
+		p.Body = body
+	})
+	if err != nil {
+		t.Fatalf("Error loading markdown: %v", err)
+	}
+	if len(markdown) != 2 {
+		t.Fatalf("Expected two markdown posts, got %d", len(markdown))
+	}
+	if markdown[0].Title != "First" || markdown[0].Body != "# First\n" {
+		t.Errorf("Expected front matter and body to be split, got %+v", markdown[0])
+	}
+	if markdown[1].ID != "" || markdown[1].Body != "# No front matter\n" {
+		t.Errorf("Expected file without front matter to be body only, got %+v", markdown[1])
+	}
+
+	_, err = LoadFS[Post](fsys, "broken/*.json", json.Unmarshal)
+	var loadErr LoadError
+	if !errors.As(err, &loadErr) || loadErr.Path != "broken/x.json" {
+		t.Errorf("Expected LoadError for broken/x.json, got %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+package genstruct
+
+import (
+	"fmt"
+	"go/token"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// lookupMapName returns the name of the lookup map for the given field
+// (e.g. AnimalsBySlug)
+func (g *Generator) lookupMapName(fieldName string) string {
+	return strings.TrimPrefix(g.sliceName(), "All") + "By" + fieldName
+}
+
+// lookupMapFields returns the fields lookup maps are generated for: the
+// identifier fields with WithIdentifierConstants, followed by the fields set
+// with WithLookupMaps, or the ID field when none were given and lookup maps or
+// accessors are enabled
+func (g *Generator) lookupMapFields(first reflect.Value) []string {
+	var fields []string
+	if g.IdentifierConsts {
+		fields = append(fields, g.identifierFields(first.Type())...)
+	}
+	if g.LookupMaps || g.Accessors {
+		if len(g.LookupMapFields) > 0 {
+			fields = append(fields, g.LookupMapFields...)
+		} else if idFieldName := g.idFieldName(first); idFieldName != "" {
+			fields = append(fields, idFieldName)
+		}
+	}
+
+	var unique []string
+	for _, field := range fields {
+		if !slices.Contains(unique, field) {
+			unique = append(unique, field)
+		}
+	}
+	return unique
+}
+
+// generateLookupMaps creates one map per lookup map field that maps each
+// populated field value to a pointer to its struct variable
+func (g *Generator) generateLookupMaps(dataValue reflect.Value) {
+	typeStmt := g.elemTypeStatement(dataValue)
+
+	first := dataValue.Index(0)
+	if first.Kind() == reflect.Pointer {
+		first = first.Elem()
+	}
+	for _, fieldName := range g.lookupMapFields(first) {
+		if field, ok := first.Type().FieldByName(fieldName); !ok || field.Type.Kind() != reflect.String {
+			continue
+		}
+
+		g.File.Var().Id(
+			g.lookupMapName(fieldName),
+		).Op(
+			"=",
+		).Map(jen.String()).Op("*").Add(
+			typeStmt.Clone(),
+		).Values(jen.DictFunc(func(dict jen.Dict) {
+			seen := make(map[string]bool)
+			for i := range dataValue.Len() {
+				elem := dataValue.Index(i)
+				if elem.Kind() == reflect.Pointer {
+					elem = elem.Elem()
+				}
+				if g.isPruned(elem) {
+					continue
+				}
+
+				key := elem.FieldByName(fieldName).String()
+				if key == "" {
+					continue
+				}
+				// Duplicate keys are not allowed in map literals, keep the first one
+				if seen[key] {
+					g.Logger.Warn(
+						"Duplicate lookup key",
+						slog.String("field", fieldName),
+						slog.String("key", key),
+					)
+					continue
+				}
+				seen[key] = true
+
+				varName := g.varName(elem)
+				dict[jen.Lit(key)] = jen.Op("&").Id(varName)
+			}
+		}))
+	}
+}
+
+// generateAccessors creates a Get and a MustGet function per lookup map
+// field returning the record with a given field value from the lookup map
+// (e.g. GetAnimalByID and MustGetAnimalByID)
+func (g *Generator) generateAccessors(dataValue reflect.Value) {
+	typeStmt := g.elemTypeStatement(dataValue)
+	typeName := g.TypeName[strings.LastIndex(g.TypeName, ".")+1:]
+
+	first := dataValue.Index(0)
+	if first.Kind() == reflect.Pointer {
+		first = first.Elem()
+	}
+	for _, fieldName := range g.lookupMapFields(first) {
+		if field, ok := first.Type().FieldByName(fieldName); !ok || field.Type.Kind() != reflect.String {
+			continue
+		}
+
+		mapName := g.lookupMapName(fieldName)
+		param := accessorParam(fieldName)
+		getName := g.safeName("Get" + typeName + "By" + fieldName)
+		g.declareFunc(
+			fmt.Sprintf("%s returns the %s whose %s is %s, and whether there is one.", getName, typeName, fieldName, param),
+		).Id(getName).Params(jen.Id(param).String()).Params(jen.Op("*").Add(typeStmt.Clone()), jen.Bool()).Block(
+			jen.List(jen.Id("record"), jen.Id("ok")).Op(":=").Id(mapName).Index(jen.Id(param)),
+			jen.Return(jen.Id("record"), jen.Id("ok")),
+		)
+
+		mustName := g.safeName("MustGet" + typeName + "By" + fieldName)
+		g.declareFunc(
+			fmt.Sprintf("%s returns the %s whose %s is %s, and panics if there is none.", mustName, typeName, fieldName, param),
+		).Id(mustName).Params(jen.Id(param).String()).Op("*").Add(typeStmt.Clone()).Block(
+			jen.List(jen.Id("record"), jen.Id("ok")).Op(":=").Id(mapName).Index(jen.Id(param)),
+			jen.If(jen.Op("!").Id("ok")).Block(
+				jen.Panic(jen.Lit("no "+typeName+" with "+fieldName+" ").Op("+").Qual("strconv", "Quote").Call(jen.Id(param))),
+			),
+			jen.Return(jen.Id("record")),
+		)
+	}
+}
+
+// accessorParam returns the name of the parameter of an accessor looking up
+// records by fieldName, e.g. id for ID and slug for Slug
+func accessorParam(fieldName string) string {
+	param := lowerFirst(fieldName)
+	if strings.ToUpper(fieldName) == fieldName {
+		param = strings.ToLower(fieldName)
+	}
+	if !token.IsIdentifier(param) || param == "record" || param == "ok" {
+		return "key"
+	}
+	return param
+}
+
+// generateOpaqueNameMap creates a map from the original identifier of each
+// struct to its variable, so records remain discoverable when WithOpaqueNames
+// replaces human-readable variable names with hashed ones
+func (g *Generator) generateOpaqueNameMap(dataValue reflect.Value) {
+	g.File.Var().Id(
+		g.lookupMapName("OriginalName"),
+	).Op(
+		"=",
+	).Map(jen.String()).Op("*").Add(
+		g.elemTypeStatement(dataValue),
+	).Values(jen.DictFunc(func(dict jen.Dict) {
+		for i := range dataValue.Len() {
+			elem := dataValue.Index(i)
+			if g.isPruned(elem) {
+				continue
+			}
+			dict[jen.Lit(g.getStructIdentifier(elem))] = jen.Op("&").Id(g.varName(elem))
+		}
+	}))
+}
@@ -0,0 +1,108 @@
+package genstruct
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// MarkdownSource is a dataset loaded from a directory of markdown files when
+// passed to Generate, Validate or Explain, as the primary dataset or as a
+// reference dataset. Create one with MarkdownDir, which fixes the struct type
+// the files decode to.
+//
+// Every .md and .markdown file under Dir, including subdirectories, becomes
+// one record, ordered by path. The front matter between leading "---" lines
+// is decoded into the record and the rest of the file is stored in the string
+// field named ContentField. Files without front matter are treated as body
+// only.
+type MarkdownSource struct {
+	Dir          string // Directory holding the markdown files, resolved against WorkingDir
+	ContentField string // String field receiving the body, "Content" by default; empty to drop the body
+
+	load func(fsys fs.FS, contentField string) (any, error)
+}
+
+// MarkdownDir returns a dataset of T loaded from the markdown files under dir,
+// with the front matter decoded by decode, typically a YAML Unmarshal
+// function. The element type T may be a struct or a pointer to a struct.
+//
+//	posts := genstruct.MarkdownDir[Post]("content/posts", yaml.Unmarshal)
+//	posts.ContentField = "Body"
+//	err := generator.Generate(posts)
+func MarkdownDir[T any](dir string, decode Decoder) MarkdownSource {
+	return MarkdownSource{
+		Dir:          dir,
+		ContentField: "Content",
+		load: func(fsys fs.FS, contentField string) (any, error) {
+			return loadMarkdownDir[T](fsys, decode, contentField)
+		},
+	}
+}
+
+// loadMarkdownDir loads every markdown file in fsys into a record
+func loadMarkdownDir[T any](fsys fs.FS, decode Decoder, contentField string) ([]T, error) {
+	if contentField != "" {
+		structType := reflect.TypeFor[T]()
+		if structType.Kind() == reflect.Pointer {
+			structType = structType.Elem()
+		}
+		if field, ok := structType.FieldByName(contentField); !ok || field.Type.Kind() != reflect.String {
+			return nil, fmt.Errorf("%s has no string field %s for the markdown body", structType, contentField)
+		}
+	}
+
+	var records []T
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		if ext := strings.ToLower(path.Ext(name)); ext != ".md" && ext != ".markdown" {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		record, body, err := decodeMarkdown[T](data, decode)
+		if err != nil {
+			return LoadError{Path: name, Err: err}
+		}
+		if contentField != "" {
+			setMarkdownBody(reflect.ValueOf(&record).Elem(), contentField, body)
+		}
+		records = append(records, record)
+		return nil
+	})
+	return records, err
+}
+
+// setMarkdownBody stores body in the named field of the record, allocating
+// pointer records without front matter
+func setMarkdownBody(record reflect.Value, field, body string) {
+	if record.Kind() == reflect.Pointer {
+		if record.IsNil() {
+			record.Set(reflect.New(record.Type().Elem()))
+		}
+		record = record.Elem()
+	}
+	record.FieldByName(field).SetString(body)
+}
+
+// loadMarkdownSource loads the markdown files of source, naming files in
+// errors by their path including Dir
+func (g *Generator) loadMarkdownSource(source MarkdownSource) (any, error) {
+	records, err := source.load(os.DirFS(g.resolvePath(source.Dir)), source.ContentField)
+	if loadErr, ok := err.(LoadError); ok {
+		loadErr.Path = filepath.Join(source.Dir, loadErr.Path)
+		return nil, loadErr
+	}
+	if err != nil {
+		return nil, LoadError{Path: source.Dir, Err: err}
+	}
+	return records, nil
+}
@@ -0,0 +1,71 @@
+package genstruct
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestMarkdownDir tests generating from a directory of markdown files with
+// front matter, including files in subdirectories
+func TestMarkdownDir(t *testing.T) {
+	type Post struct {
+		Slug  string
+		Title string
+		Body  string
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"posts/hello.md":            "---\n{\"Slug\": \"hello\", \"Title\": \"Hello\"}\n---\nFirst post.\n",
+		"posts/2024/again.markdown": "---\n{\"Slug\": \"again\", \"Title\": \"Again\"}\n---\nSecond post.\n",
+		"posts/notes.txt":           "not a post",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	posts := MarkdownDir[Post]("posts", json.Unmarshal)
+	posts.ContentField = "Body"
+	err := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+	).Generate(posts)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "posts.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	output := string(content)
+	for _, want := range []string{"var PostHello = Post{", `Body:  "First post.\n"`, "var PostAgain = Post{", "AllPosts"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "not a post") {
+		t.Errorf("Expected non-markdown files to be skipped, got:\n%s", output)
+	}
+
+	err = NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("broken.go"),
+		WithWorkingDir(dir),
+	).Generate(MarkdownDir[Post]("posts", json.Unmarshal))
+	var loadErr LoadError
+	if !errors.As(err, &loadErr) || !strings.Contains(err.Error(), "no string field Content") {
+		t.Errorf("Expected LoadError for the missing Content field, got %v", err)
+	}
+}
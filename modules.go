@@ -0,0 +1,223 @@
+package genstruct
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// qual returns a qualified reference to name in the package at pkgPath,
+// applying any configured module path rewrites to the import path
+func (g *Generator) qual(pkgPath, name string) *jen.Statement {
+	return jen.Qual(g.rewriteImportPath(pkgPath), name)
+}
+
+// rewriteImportPath rewrites an import path using the longest matching module
+// path prefix configured with WithModulePathRewrite
+func (g *Generator) rewriteImportPath(pkgPath string) string {
+	bestFrom := ""
+	for from := range g.ModuleRewrites {
+		if (pkgPath == from || strings.HasPrefix(pkgPath, from+"/")) &&
+			len(from) > len(bestFrom) {
+			bestFrom = from
+		}
+	}
+	if bestFrom == "" {
+		return pkgPath
+	}
+	return g.ModuleRewrites[bestFrom] + strings.TrimPrefix(pkgPath, bestFrom)
+}
+
+// goModFile holds the parts of a go.mod file needed to verify imports
+type goModFile struct {
+	Path      string   // Path of the go.mod file
+	Module    string   // Module path declared by the module directive
+	Requires  []string // Module paths of all require directives
+	Workspace []string // Module paths of the other modules of its go.work workspace
+}
+
+// findGoMod returns the go.mod file of the module containing dir, or nil if
+// dir is not inside a module
+func findGoMod(dir string) (*goModFile, error) {
+	for current := dir; ; current = filepath.Dir(current) {
+		modPath := filepath.Join(current, "go.mod")
+		if _, err := os.Stat(modPath); err == nil {
+			return parseGoMod(modPath)
+		}
+		if filepath.Dir(current) == current {
+			return nil, nil
+		}
+	}
+}
+
+// parseGoMod reads the module and require directives of a go.mod file
+func parseGoMod(path string) (*goModFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	mod := &goModFile{Path: path}
+	inRequireBlock := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch {
+		case inRequireBlock && fields[0] == ")":
+			inRequireBlock = false
+		case inRequireBlock:
+			mod.Requires = append(mod.Requires, fields[0])
+		case fields[0] == "module" && len(fields) > 1:
+			mod.Module = strings.Trim(fields[1], `"`)
+		case fields[0] == "require" && len(fields) > 1 && fields[1] == "(":
+			inRequireBlock = true
+		case fields[0] == "require" && len(fields) > 1:
+			mod.Requires = append(mod.Requires, fields[1])
+		}
+	}
+	return mod, scanner.Err()
+}
+
+// findGoWork returns the module paths listed by the use directives of the
+// go.work file of the workspace containing dir, or nil if dir is not inside a
+// workspace or workspaces are disabled with GOWORK=off
+func findGoWork(dir string) ([]string, error) {
+	if os.Getenv("GOWORK") == "off" {
+		return nil, nil
+	}
+	for current := dir; ; current = filepath.Dir(current) {
+		workPath := filepath.Join(current, "go.work")
+		if _, err := os.Stat(workPath); err == nil {
+			return parseGoWork(workPath)
+		}
+		if filepath.Dir(current) == current {
+			return nil, nil
+		}
+	}
+}
+
+// parseGoWork reads the module paths of the modules in the use directives of
+// a go.work file
+func parseGoWork(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var dirs []string
+	inUseBlock := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch {
+		case inUseBlock && fields[0] == ")":
+			inUseBlock = false
+		case inUseBlock:
+			dirs = append(dirs, fields[0])
+		case fields[0] == "use" && len(fields) > 1 && fields[1] == "(":
+			inUseBlock = true
+		case fields[0] == "use" && len(fields) > 1:
+			dirs = append(dirs, fields[1])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var modules []string
+	for _, dir := range dirs {
+		dir = strings.Trim(dir, `"`)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(path), dir)
+		}
+		mod, err := parseGoMod(filepath.Join(dir, "go.mod"))
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, mod.Module)
+	}
+	return modules, nil
+}
+
+// provides reports whether the module can import the package at pkgPath,
+// either because the package is part of the module, of one of its
+// requirements or of another module of its workspace
+func (m *goModFile) provides(pkgPath string) bool {
+	modPaths := append([]string{m.Module}, m.Requires...)
+	for _, modPath := range append(modPaths, m.Workspace...) {
+		if pkgPath == modPath || strings.HasPrefix(pkgPath, modPath+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyTargetModule checks that the module the output file is written into
+// can import the packages of the primary and reference data struct types
+func (g *Generator) verifyTargetModule() error {
+	outputPath, err := filepath.Abs(g.resolvePath(g.OutputFile))
+	if err != nil {
+		return err
+	}
+	mod, err := findGoMod(filepath.Dir(outputPath))
+	if err != nil || mod == nil {
+		return err
+	}
+	if mod.Workspace, err = findGoWork(filepath.Dir(outputPath)); err != nil {
+		return err
+	}
+
+	datasets := append([]any{g.Data}, g.refDatasets()...)
+	for _, data := range datasets {
+		dataType := reflect.TypeOf(data)
+		if dataType == nil ||
+			(dataType.Kind() != reflect.Slice && dataType.Kind() != reflect.Array) {
+			continue
+		}
+		elemType := dataType.Elem()
+		if elemType.Kind() == reflect.Pointer {
+			elemType = elemType.Elem()
+		}
+
+		pkgPath := elemType.PkgPath()
+		if pkgPath == "" || pkgPath == "main" || pkgPath == g.PackageName {
+			continue
+		}
+		importPath := g.rewriteImportPath(pkgPath)
+
+		// Standard library packages have no dot in their first path element
+		if !strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			continue
+		}
+		if !mod.provides(importPath) {
+			return ModuleRequirementError{
+				Package:      importPath,
+				TargetModule: mod.Module,
+				GoMod:        mod.Path,
+			}
+		}
+	}
+	return nil
+}
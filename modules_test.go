@@ -0,0 +1,100 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestVerifyTargetModule tests that generating into a module without the
+// required source module fails with a ModuleRequirementError
+func TestVerifyTargetModule(t *testing.T) {
+	dir := t.TempDir()
+	goMod := "module example.com/other\n\ngo 1.24\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatalf("Error writing go.mod: %v", err)
+	}
+
+	tags := []Tag{{ID: "tag-1", Name: "Go", Slug: "go"}}
+	generator := NewGenerator(
+		WithOutputFile("out/tags.go"),
+		WithWorkingDir(dir),
+	)
+	var modErr ModuleRequirementError
+	if err := generator.Generate(tags); !errors.As(err, &modErr) {
+		t.Fatalf("Expected ModuleRequirementError, got %v", err)
+	}
+
+	// Rewriting the import path into the target module makes it importable
+	if err := os.MkdirAll(filepath.Join(dir, "out"), 0755); err != nil {
+		t.Fatalf("Error creating output directory: %v", err)
+	}
+	generator = NewGenerator(
+		WithOutputFile("out/tags.go"),
+		WithWorkingDir(dir),
+		WithModulePathRewrite("github.com/conneroisu/genstruct", "example.com/other/types"),
+	)
+	if err := generator.Generate(tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "out", "tags.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	if !strings.Contains(string(content), `"example.com/other/types"`) {
+		t.Errorf("Expected rewritten import path in generated code:\n%s", content)
+	}
+}
+
+// TestVerifyTargetModuleWorkspace tests that the modules of a go.work
+// workspace can import each other's types without requiring them
+func TestVerifyTargetModuleWorkspace(t *testing.T) {
+	t.Setenv("GOWORK", "")
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.work":          "go 1.24\n\nuse (\n\t./other\n\t./types // the data types\n)\n",
+		"other/go.mod":     "module example.com/other\n\ngo 1.24\n",
+		"types/go.mod":     "module github.com/conneroisu/genstruct\n\ngo 1.24\n",
+		"types/types.go":   "package genstruct\n\ntype Tag struct {\n\tID   string\n\tName string\n\tSlug string\n}\n",
+		"other/out/doc.go": "package out\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Error creating directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Error writing %s: %v", name, err)
+		}
+	}
+
+	tags := []Tag{{ID: "tag-1", Name: "Go", Slug: "go"}}
+	generator := NewGenerator(
+		WithOutputFile("out/tags.go"),
+		WithWorkingDir(filepath.Join(dir, "other")),
+	)
+	if err := generator.Generate(tags); err != nil {
+		t.Fatalf("Error generating code into a workspace module: %v", err)
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+	cmd := exec.Command(goBin, "vet", "./...")
+	cmd.Dir = filepath.Join(dir, "other")
+	cmd.Env = append(os.Environ(), "GOFLAGS=")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Generated code failed to compile in the workspace: %v\n%s", err, output)
+	}
+
+	// Without the workspace the types module isn't provided
+	t.Setenv("GOWORK", "off")
+	var modErr ModuleRequirementError
+	if err := generator.Generate(tags); !errors.As(err, &modErr) {
+		t.Errorf("Expected ModuleRequirementError with GOWORK=off, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package genstruct
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Kilograms is a defined float type used to test named numeric literals
+type Kilograms float64
+
+// Rank is a defined int type used to test named numeric literals
+type Rank int
+
+// TestNamedNumericTypes tests that defined numeric types are preserved in literals
+func TestNamedNumericTypes(t *testing.T) {
+	type Animal struct {
+		ID      string
+		Weight  Kilograms
+		Rank    Rank
+		Feeding time.Duration
+	}
+	animals := []Animal{{ID: "lion", Weight: 180.5, Rank: 3, Feeding: time.Hour}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("genstruct"),
+		WithOutputFile("animals.go"),
+		WithWorkingDir(dir),
+	)
+	if err := generator.Generate(animals); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "animals.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+
+	expected := []string{
+		"Weight:  Kilograms(180.5)",
+		"Rank:    Rank(3)",
+		"Feeding: time.Duration(3600000000000)",
+	}
+	for _, want := range expected {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in generated code:\n%s", want, content)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package genstruct
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// truncationCommentPrefix prefixes the package comment lines recording the
+// identifiers shortened by MaxIdentifierLen
+const truncationCommentPrefix = "// genstruct Truncated: "
+
+// truncationHashLen is the number of hex characters appended to a truncated
+// identifier to keep it unique
+const truncationHashLen = 6
+
+// recordIdentifier returns the identifier part of the names generated for a
+// struct instance (e.g. "Leo" in AnimalLeo and AnimalLeoID)
+func (g *Generator) recordIdentifier(structValue reflect.Value) string {
+	identValue := g.getStructIdentifier(structValue)
+	if g.OpaqueNames {
+		return opaqueIdentifier(identValue)
+	}
+	ident, _ := truncateIdentifier(g.sanitizeIdentifier(identValue), g.MaxIdentifierLen)
+	return ident
+}
+
+// sanitizeIdentifier converts a record identifier or map key into the Go
+// identifier used in generated names, with the configured IdentifierSanitizer
+// or Initialisms
+func (g *Generator) sanitizeIdentifier(s string) string {
+	if g.IdentifierSanitizer != nil {
+		return g.IdentifierSanitizer(s)
+	}
+	return initialismIdentifier(s, g.Initialisms)
+}
+
+// varName returns the name of the variable generated for a struct instance
+func (g *Generator) varName(structValue reflect.Value) string {
+	return g.safeName(g.prefixedIdentifier(g.VarPrefix, structValue))
+}
+
+// refPrefix returns the prefix of the names generated for the records of a
+// reference dataset, which is the type name unless set with WithRefPrefix
+func (g *Generator) refPrefix(typeName string) string {
+	if prefix, ok := g.RefPrefixes[typeName]; ok {
+		return prefix
+	}
+	return refTypeIdent(typeName)
+}
+
+// refVarName returns the name of the variable generated for a record of a
+// reference dataset
+func (g *Generator) refVarName(typeName string, refStruct reflect.Value) string {
+	return g.safeName(g.prefixedIdentifier(g.refPrefix(typeName), refStruct))
+}
+
+// prefixedIdentifier joins a name prefix and the identifier of a struct
+// instance. Records of reference datasets with a prefix set by WithRefPrefix
+// don't repeat it when their identifier already starts with it (e.g.
+// EmploymentEngineer rather than EmploymentEmploymentEngineer), and with
+// StripTypePrefix no record repeats its type name (e.g. TagGo rather than
+// TagTagGo).
+//
+// Records named like an earlier record of their dataset are renamed as set
+// by the CollisionPolicy.
+func (g *Generator) prefixedIdentifier(prefix string, structValue reflect.Value) string {
+	return prefix + g.resolveCollision(prefix, structValue)
+}
+
+// unprefixedIdentifier returns the identifier of a struct instance as joined
+// to prefix by prefixedIdentifier, before resolving collisions
+func (g *Generator) unprefixedIdentifier(prefix string, structValue reflect.Value) string {
+	ident := g.recordIdentifier(structValue)
+	if g.OpaqueNames {
+		return ident
+	}
+	structType := reflect.Indirect(structValue).Type()
+	if g.StripTypePrefix {
+		ident = trimPrefixWords(ident, structType.Name())
+	}
+	if refPrefix, ok := g.RefPrefixes[g.refKey(structType)]; ok && prefix == refPrefix && structType != g.dataStructType() {
+		ident = trimPrefixWords(ident, prefix)
+	}
+	return ident
+}
+
+// trimPrefixWords removes prefix from the start of ident if it matches whole
+// words regardless of case, so acronyms match too (URL in UrlHome). ident is
+// returned unchanged if nothing would remain.
+func trimPrefixWords(ident, prefix string) string {
+	if prefix == "" || len(ident) <= len(prefix) || !strings.EqualFold(ident[:len(prefix)], prefix) {
+		return ident
+	}
+	rest := ident[len(prefix):]
+	if next := rune(rest[0]); !unicode.IsUpper(next) && !unicode.IsDigit(next) {
+		return ident
+	}
+	return rest
+}
+
+// opaqueIdentifier returns a short hashed identifier that does not reveal the
+// original value in symbol tables
+func opaqueIdentifier(identValue string) string {
+	sum := sha256.Sum256([]byte(identValue))
+	return "X" + hex.EncodeToString(sum[:5])
+}
+
+// truncateIdentifier shortens ident to at most maxLen characters, cutting at
+// word boundaries and appending a short hash of the full identifier so that
+// identifiers sharing a prefix stay distinct. A maxLen of zero or less, or one
+// too short to hold the hash, leaves ident unchanged.
+func truncateIdentifier(ident string, maxLen int) (string, bool) {
+	if maxLen <= truncationHashLen || len(ident) <= maxLen {
+		return ident, false
+	}
+
+	sum := sha256.Sum256([]byte(ident))
+	suffix := hex.EncodeToString(sum[:])[:truncationHashLen]
+	budget := maxLen - truncationHashLen
+
+	kept := ""
+	for _, word := range identifierWords(ident) {
+		if len(kept)+len(word) > budget {
+			break
+		}
+		kept += word
+	}
+	// A leading word longer than the budget is cut mid-word
+	if kept == "" {
+		kept = ident[:budget]
+	}
+	return kept + suffix, true
+}
+
+// identifierWords splits a CamelCase identifier into its words, keeping runs
+// of digits as separate words
+func identifierWords(ident string) []string {
+	var words []string
+	start := 0
+	for i := 1; i < len(ident); i++ {
+		prev, cur := rune(ident[i-1]), rune(ident[i])
+		if unicode.IsUpper(cur) || unicode.IsDigit(cur) != unicode.IsDigit(prev) {
+			words = append(words, ident[start:i])
+			start = i
+		}
+	}
+	if ident != "" {
+		words = append(words, ident[start:])
+	}
+	return words
+}
+
+// truncationComment returns the package comment lines mapping each truncated
+// identifier to the value it was derived from
+func (g *Generator) truncationComment() string {
+	if g.MaxIdentifierLen <= 0 || g.OpaqueNames || g.Data == nil {
+		return ""
+	}
+
+	datasets := []any{g.Data}
+	for _, typeName := range g.pendingRefs(nil) {
+		datasets = append(datasets, g.Refs[typeName])
+	}
+
+	var lines []string
+	for _, data := range datasets {
+		dataValue := reflect.ValueOf(data)
+		if dataValue.Kind() != reflect.Slice {
+			continue
+		}
+		for i := range dataValue.Len() {
+			identValue := g.getStructIdentifier(dataValue.Index(i))
+			ident, truncated := truncateIdentifier(g.sanitizeIdentifier(identValue), g.MaxIdentifierLen)
+			if truncated {
+				// Quoted so that newlines in the value cannot end the comment
+				lines = append(lines, truncationCommentPrefix+ident+" = "+strconv.Quote(identValue))
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "\n" + strings.Join(lines, "\n")
+}
+
+// NameContext describes the record being named by a NameFn.
+type NameContext struct {
+	Value    reflect.Value   // The struct value of the record
+	Index    int             // Index of the record in its source dataset, or -1 if unknown
+	TypeName string          // Name of the record's struct type
+	Used     map[string]bool // Names returned for the earlier records of the dataset; must not be modified
+}
+
+// nameFunc returns the custom naming hook, adapting a CustomVarNameFn to the
+// NameContext signature, or nil if neither is set
+func (g *Generator) nameFunc() func(NameContext) string {
+	if g.NameFn != nil {
+		return g.NameFn
+	}
+	if fn := g.CustomVarNameFn; fn != nil {
+		return func(ctx NameContext) string { return fn(ctx.Value) }
+	}
+	return nil
+}
+
+// customName returns the name the naming hook gives a record. Names are
+// computed once per dataset, in order, so each call sees the same index and
+// used names.
+func (g *Generator) customName(fn func(NameContext) string, structValue reflect.Value) string {
+	if !structValue.CanAddr() {
+		return fn(NameContext{Value: structValue, Index: -1, TypeName: structValue.Type().Name()})
+	}
+
+	addr := structValue.UnsafeAddr()
+	if name, ok := g.customNames[addr]; ok {
+		return name
+	}
+
+	if g.customNames == nil {
+		g.customNames = make(map[uintptr]string)
+	}
+	datasets := append([]any{g.Data}, g.refDatasets()...)
+	for _, data := range datasets {
+		dataValue := reflect.ValueOf(data)
+		if dataValue.Kind() != reflect.Slice && dataValue.Kind() != reflect.Array {
+			continue
+		}
+		used := make(map[string]bool)
+		for i := range dataValue.Len() {
+			elem := reflect.Indirect(dataValue.Index(i))
+			if !elem.CanAddr() || elem.Kind() != reflect.Struct {
+				break
+			}
+			name := fn(NameContext{Value: elem, Index: g.sourceIndex(dataValue, i), TypeName: elem.Type().Name(), Used: used})
+			used[name] = true
+			g.customNames[elem.UnsafeAddr()] = name
+		}
+	}
+
+	if name, ok := g.customNames[addr]; ok {
+		return name
+	}
+	return fn(NameContext{Value: structValue, Index: -1, TypeName: structValue.Type().Name()})
+}
+
+// SequentialName is the default fallback naming strategy, returning the
+// 1-based position of the record (e.g. "3"), which names its variable Animal3
+func SequentialName(typeName string, index int) string {
+	return strconv.Itoa(index + 1)
+}
+
+// fallbackName returns the identifier of a record without a string field to
+// identify it, from its position in its dataset
+func (g *Generator) fallbackName(structValue reflect.Value) string {
+	fn := g.FallbackNameFn
+	if fn == nil {
+		fn = SequentialName
+	}
+	return fn(structValue.Type().Name(), g.recordIndex(structValue))
+}
+
+// recordIndex returns the position of a record in its source dataset, or -1
+// if it isn't found in the primary or reference datasets. Records are found by
+// address, or by value when they aren't addressable.
+func (g *Generator) recordIndex(structValue reflect.Value) int {
+	datasets := append([]any{g.Data}, g.refDatasets()...)
+	if !structValue.CanAddr() {
+		for _, data := range datasets {
+			dataValue := reflect.ValueOf(data)
+			if dataValue.Kind() != reflect.Slice && dataValue.Kind() != reflect.Array {
+				continue
+			}
+			for i := range dataValue.Len() {
+				elem := reflect.Indirect(dataValue.Index(i))
+				if elem.IsValid() && elem.Type() == structValue.Type() && reflect.DeepEqual(elem.Interface(), structValue.Interface()) {
+					return g.sourceIndex(dataValue, i)
+				}
+			}
+		}
+		return -1
+	}
+
+	if index, ok := g.recordIndexes[structValue.UnsafeAddr()]; ok {
+		return index
+	}
+
+	// Index the datasets, including reference datasets loaded since the last
+	// time they were indexed
+	g.recordIndexes = make(map[uintptr]int)
+	for _, data := range datasets {
+		dataValue := reflect.ValueOf(data)
+		if dataValue.Kind() != reflect.Slice && dataValue.Kind() != reflect.Array {
+			continue
+		}
+		for i := range dataValue.Len() {
+			if elem := reflect.Indirect(dataValue.Index(i)); elem.IsValid() && elem.CanAddr() {
+				g.recordIndexes[elem.UnsafeAddr()] = g.sourceIndex(dataValue, i)
+			}
+		}
+	}
+	if index, ok := g.recordIndexes[structValue.UnsafeAddr()]; ok {
+		return index
+	}
+	return -1
+}
@@ -0,0 +1,508 @@
+package genstruct
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestMaxIdentifierLen tests that long identifiers are truncated at word
+// boundaries, kept unique and recorded in the package comment
+func TestMaxIdentifierLen(t *testing.T) {
+	type Post struct {
+		ID    string
+		Title string
+	}
+	posts := []Post{
+		{ID: "post-1", Title: "A Very Long Title About Generating Static Data"},
+		{ID: "post-2", Title: "A Very Long Title About Generating Dynamic Data"},
+		{ID: "post-3", Title: "Short"},
+		{ID: "post-4", Title: "A Very Long Title\nSpanning Several Lines"},
+	}
+
+	dir := t.TempDir()
+	err := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Title"}),
+		WithMaxIdentifierLen(20),
+	).Generate(posts)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "posts.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	output := string(content)
+
+	first, _ := truncateIdentifier("AVeryLongTitleAboutGeneratingStaticData", 20)
+	second, _ := truncateIdentifier("AVeryLongTitleAboutGeneratingDynamicData", 20)
+	if first == second {
+		t.Fatalf("Truncated identifiers collide: %s", first)
+	}
+	for _, ident := range []string{first, second} {
+		if len(ident) > 20 {
+			t.Errorf("Identifier %s exceeds 20 characters", ident)
+		}
+		if !strings.HasPrefix(ident, "AVeryLongTitle") {
+			t.Errorf("Identifier %s not cut at a word boundary", ident)
+		}
+		if !strings.Contains(output, "var Post"+ident+" = ") {
+			t.Errorf("Output missing variable Post%s", ident)
+		}
+	}
+	if !strings.Contains(output, "var PostShort = ") {
+		t.Error("Short identifier should not be truncated")
+	}
+	if !strings.Contains(output, truncationCommentPrefix+first+` = "A Very Long Title About Generating Static Data"`) {
+		t.Error("Package comment missing truncation mapping")
+	}
+	multiLine, _ := truncateIdentifier("AVeryLongTitleSpanningSeveralLines", 20)
+	if !strings.Contains(output, truncationCommentPrefix+multiLine+` = "A Very Long Title\nSpanning Several Lines"`) {
+		t.Errorf("Package comment should quote multi-line values:\n%s", output)
+	}
+	if strings.Contains(output, truncationCommentPrefix+"Short") {
+		t.Error("Package comment should only record truncated identifiers")
+	}
+
+	var configErr ConfigError
+	err = NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithMaxIdentifierLen(4),
+	).Generate(posts)
+	if !errors.As(err, &configErr) || configErr.Option != "MaxIdentifierLen" {
+		t.Errorf("Expected MaxIdentifierLen ConfigError, got %v", err)
+	}
+}
+
+// TestNameFunc tests that the naming hook receives the record index, type
+// name and names used so far, and that the old signature still works
+func TestNameFunc(t *testing.T) {
+	type Animal struct {
+		ID      string
+		Species string
+	}
+	animals := []*Animal{
+		{ID: "animal-1", Species: "Lion"},
+		{ID: "animal-2", Species: "Lion"},
+		{ID: "animal-3", Species: "Zebra"},
+	}
+
+	dir := t.TempDir()
+	var contexts []NameContext
+	err := NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile("animals.go"),
+		WithWorkingDir(dir),
+		WithNameFunc(func(ctx NameContext) string {
+			contexts = append(contexts, ctx)
+			name := ctx.Value.FieldByName("Species").String()
+			if ctx.Used[name] {
+				name += fmt.Sprint(ctx.Index)
+			}
+			return name
+		}),
+	).Generate(animals)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	if len(contexts) != len(animals) {
+		t.Errorf("Expected the hook to run once per record, ran %d times", len(contexts))
+	}
+	for i, ctx := range contexts {
+		if ctx.Index != i || ctx.TypeName != "Animal" {
+			t.Errorf("Unexpected context for record %d: index %d, type %s", i, ctx.Index, ctx.TypeName)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "animals.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	for _, name := range []string{"AnimalLion = ", "AnimalLion1 = ", "AnimalZebra = "} {
+		if !strings.Contains(string(content), "var "+name) {
+			t.Errorf("Expected output to declare %s", name)
+		}
+	}
+
+	// The old signature is adapted to the context hook
+	err = NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile("animals.go"),
+		WithWorkingDir(dir),
+		WithCustomVarNameFn(func(v reflect.Value) string {
+			return v.FieldByName("ID").String() + "-" + v.FieldByName("Species").String()
+		}),
+	).Generate(animals)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	content, err = os.ReadFile(filepath.Join(dir, "animals.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	if !strings.Contains(string(content), "var AnimalAnimal2Lion = ") {
+		t.Error("Expected the legacy naming function to be used")
+	}
+}
+
+// TestIdentifierFieldsFor tests that each type is named and matched by its
+// own identifier fields within one Generate call
+func TestIdentifierFieldsFor(t *testing.T) {
+	type Author struct {
+		ID   string
+		Name string
+	}
+	type Post struct {
+		ID         string
+		Slug       string
+		AuthorName string
+		Author     *Author `structgen:"AuthorName"`
+	}
+	authors := []Author{{ID: "author-1", Name: "Ada Lovelace"}}
+	posts := []Post{{ID: "post-1", Slug: "first-post", AuthorName: "Ada Lovelace"}}
+
+	dir := t.TempDir()
+	err := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFieldsFor("Post", []string{"Slug"}),
+		WithIdentifierFieldsFor("Author", []string{"Name"}),
+	).Generate(posts, authors)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "posts.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	output := string(content)
+	for _, want := range []string{"var PostFirstPost = ", "var AuthorAdaLovelace = ", "Author:     &AuthorAdaLovelace"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	var configErr ConfigError
+	err = NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFieldsFor("Post", []string{"Missing"}),
+	).Generate(posts)
+	if !errors.As(err, &configErr) || configErr.Option != "TypeIdentifierFields" {
+		t.Errorf("Expected TypeIdentifierFields ConfigError, got %v", err)
+	}
+}
+
+// TestRefPrefix tests that reference datasets use their configured prefix
+// without repeating it in identifiers that already start with it
+func TestRefPrefix(t *testing.T) {
+	type Employment struct {
+		ID   string
+		Role string
+	}
+	type URL struct {
+		ID   string
+		Path string
+	}
+	type Person struct {
+		ID           string
+		EmploymentID string
+		Employment   *Employment `structgen:"EmploymentID"`
+		HomeID       string
+		Home         *URL `structgen:"HomeID"`
+	}
+	jobs := []Employment{{ID: "employment-engineer", Role: "Engineer"}}
+	urls := []URL{{ID: "url-home", Path: "/"}}
+	people := []Person{{ID: "ada", EmploymentID: "employment-engineer", HomeID: "url-home"}}
+
+	dir := t.TempDir()
+	err := NewGenerator(
+		WithPackageName("people"),
+		WithOutputFile("people.go"),
+		WithWorkingDir(dir),
+		WithRefPrefix("Employment", "Employment"),
+		WithRefPrefix("URL", "URL"),
+	).Generate(people, jobs, urls)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "people.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	output := string(content)
+	for _, want := range []string{
+		"var EmploymentEngineer = ",
+		"EmploymentEngineerID",
+		"var URLHome = ",
+		"&EmploymentEngineer",
+		"&URLHome",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "EmploymentEmployment") || strings.Contains(output, "URLUrl") {
+		t.Errorf("Expected prefixes not to be repeated, got:\n%s", output)
+	}
+
+	err = NewGenerator(
+		WithPackageName("people"),
+		WithOutputFile("jobs.go"),
+		WithWorkingDir(dir),
+		WithRefPrefix("Employment", "Job"),
+	).Generate(people, jobs)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	content, err = os.ReadFile(filepath.Join(dir, "jobs.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	if !strings.Contains(string(content), "var JobEmploymentEngineer = ") {
+		t.Errorf("Expected the configured prefix, got:\n%s", content)
+	}
+}
+
+// TestStripTypePrefix tests that identifiers repeating the type name are
+// stripped for the primary and reference datasets
+func TestStripTypePrefix(t *testing.T) {
+	type Tag struct {
+		ID   string
+		Name string
+	}
+	type Post struct {
+		ID     string
+		TagIDs []string
+		Tags   []*Tag `structgen:"TagIDs"`
+	}
+	tags := []Tag{{ID: "tag-go", Name: "Go"}, {ID: "tagging", Name: "Tagging"}}
+	posts := []Post{{ID: "post-hello", TagIDs: []string{"tag-go", "tagging"}}}
+
+	dir := t.TempDir()
+	err := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithStripTypePrefix(),
+	).Generate(posts, tags)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "posts.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	output := string(content)
+	for _, want := range []string{
+		"var PostHello = ",
+		"PostHelloID",
+		"var TagGo = ",
+		"var TagTagging = ",
+		"&TagGo",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "TagTagGo") || strings.Contains(output, "PostPost") {
+		t.Errorf("Expected type names not to be repeated, got:\n%s", output)
+	}
+}
+
+// TestSliceNames tests that slice names come from WithAllSliceName or the
+// configured pluralizer, including the names derived from them
+func TestSliceNames(t *testing.T) {
+	type Criterion struct {
+		ID   string
+		Name string
+	}
+	type Person struct {
+		ID           string
+		Name         string
+		CriterionIDs []string
+		Criteria     []*Criterion `structgen:"CriterionIDs"`
+	}
+	criteria := []Criterion{{ID: "speed", Name: "Speed"}}
+	people := []Person{{ID: "ada", Name: "Ada", CriterionIDs: []string{"speed"}}}
+
+	dir := t.TempDir()
+	err := NewGenerator(
+		WithPackageName("people"),
+		WithOutputFile("people.go"),
+		WithWorkingDir(dir),
+		WithAllSliceName("Person", "AllPeople"),
+		WithPluralizer(func(typeName string) string {
+			return strings.TrimSuffix(typeName, "on") + "a"
+		}),
+		WithLookupMaps("Name"),
+	).Generate(people, criteria)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "people.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	output := string(content)
+	for _, want := range []string{
+		"var AllPeople = ",
+		"var PeopleByName = ",
+		"var AllCriteria = ",
+		"var CriteriaByName = ",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	err = NewGenerator(
+		WithPackageName("people"),
+		WithOutputFile("invalid.go"),
+		WithWorkingDir(dir),
+		WithAllSliceName("Person", "All People"),
+	).Generate(people)
+	var configErr ConfigError
+	if !errors.As(err, &configErr) || configErr.Option != "SliceNames" {
+		t.Errorf("Expected SliceNames ConfigError, got %v", err)
+	}
+}
+
+// TestIdentifierSanitizer tests that a configured sanitizer replaces the
+// default conversion of identifiers and map keys
+func TestIdentifierSanitizer(t *testing.T) {
+	type Setting struct {
+		ID       string
+		Metadata map[string]string
+	}
+	settings := []Setting{{ID: "api-key", Metadata: map[string]string{"http-url": "x"}}}
+
+	acronyms := map[string]string{"api": "API", "http": "HTTP", "url": "URL"}
+	sanitizer := func(s string) string {
+		var b strings.Builder
+		for _, word := range strings.FieldsFunc(s, func(r rune) bool { return r == '-' }) {
+			if acronym, ok := acronyms[word]; ok {
+				b.WriteString(acronym)
+				continue
+			}
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+		return b.String()
+	}
+
+	src, err := NewGenerator(
+		WithPackageName("settings"),
+		WithOutputFile("settings.go"),
+		WithIdentifierSanitizer(sanitizer),
+		WithMapKeyConstants(),
+	).Render(settings)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	output := string(src)
+	for _, want := range []string{
+		"var SettingAPIKey = ",
+		"SettingAPIKeyID = ",
+		"SettingMetadataKeyHTTPURL = ",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+// TestInitialisms tests that initialisms in identifiers are written in upper
+// case, including the ones added to the defaults
+func TestInitialisms(t *testing.T) {
+	type Endpoint struct {
+		ID   string
+		Path string
+	}
+	endpoints := []Endpoint{
+		{ID: "user-id-api", Path: "/users"},
+		{ID: "gpu-stats", Path: "/gpu"},
+		{ID: "idle", Path: "/idle"},
+		{ID: "sku-list", Path: "/skus"},
+	}
+
+	src, err := NewGenerator(
+		WithPackageName("api"),
+		WithOutputFile("endpoints.go"),
+		WithInitialisms("gpu"),
+		WithInitialisms("sku"),
+	).Render(endpoints)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	output := string(src)
+	for _, want := range []string{
+		"var EndpointUserIDAPI = ",
+		"EndpointUserIDAPIID = ",
+		"var EndpointGPUStats = ",
+		"var EndpointIdle = ",
+		"var EndpointSKUList = ",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	if got := slugToIdentifier("user-id-api"); got != "UserIdApi" {
+		t.Errorf("slugToIdentifier() = %q, want the default UserIdApi", got)
+	}
+}
+
+// TestFallbackNames tests that records without a string field to identify
+// them are named by their position, identically on every run
+func TestFallbackNames(t *testing.T) {
+	type Reading struct {
+		Value float64
+		Valid bool
+	}
+	readings := []Reading{{Value: 1.5, Valid: true}, {Value: 2.5}, {Value: 3.5, Valid: true}}
+
+	render := func(opts ...Option) string {
+		src, err := NewGenerator(append([]Option{
+			WithPackageName("sensors"),
+			WithOutputFile("readings.go"),
+		}, opts...)...).Render(readings)
+		if err != nil {
+			t.Fatalf("Error generating code: %v", err)
+		}
+		return string(src)
+	}
+
+	output := render()
+	for _, want := range []string{"var Reading1 = ", "var Reading2 = ", "var Reading3 = "} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if again := render(); again != output {
+		t.Errorf("Expected identical output on every run, got:\n%s\nthen:\n%s", output, again)
+	}
+
+	output = render(WithFallbackNames(func(typeName string, index int) string {
+		return fmt.Sprintf("%s-%c", typeName, 'a'+index)
+	}))
+	if !strings.Contains(output, "var ReadingReadingC = ") {
+		t.Errorf("Expected the configured fallback names, got:\n%s", output)
+	}
+}
@@ -0,0 +1,128 @@
+package genstruct
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// WriteFS is a filesystem generated files are written to. Names passed to
+// WriteFile are the resolved output paths, absolute unless WorkingDir is
+// relative. Implementations that need directories created up front, such as
+// the per-record JSON fixture directories, may also implement MkdirAllFS.
+type WriteFS interface {
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+}
+
+// MkdirAllFS is a WriteFS that creates directories before files are written
+// into them.
+type MkdirAllFS interface {
+	WriteFS
+	MkdirAll(path string, perm fs.FileMode) error
+}
+
+// RemoveFS is a WriteFS that removes generated files no longer produced,
+// such as the pieces of a split output file left over from an earlier run.
+type RemoveFS interface {
+	WriteFS
+	Remove(name string) error
+}
+
+// osFS writes to the operating system's filesystem
+type osFS struct{}
+
+// WriteFile writes data to the named file
+func (osFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+// MkdirAll creates a directory along with any necessary parents
+func (osFS) MkdirAll(path string, perm fs.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+// Remove removes the named file
+func (osFS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+// MemFS is an in-memory WriteFS, useful for inspecting generated files in
+// tests without touching the disk. The zero value is ready to use.
+type MemFS struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+// WriteFile stores a copy of data under name
+func (m *MemFS) WriteFile(name string, data []byte, _ fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.files == nil {
+		m.files = make(map[string][]byte)
+	}
+	m.files[filepath.Clean(name)] = append([]byte(nil), data...)
+	return nil
+}
+
+// ReadFile returns the contents written to name
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[filepath.Clean(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Remove deletes the file stored under name
+func (m *MemFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.files[filepath.Clean(name)]; !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	delete(m.files, filepath.Clean(name))
+	return nil
+}
+
+// outputFS returns the filesystem generated files are written to
+func (g *Generator) outputFS() WriteFS {
+	if g.OutputFS != nil {
+		return g.OutputFS
+	}
+	return osFS{}
+}
+
+// writeFile writes a generated file to the output filesystem, or keeps it in
+// memory while a dry run or Verify captures the outputs
+func (g *Generator) writeFile(path string, data []byte) error {
+	if g.captured != nil {
+		g.captured[path] = data
+		return nil
+	}
+	return g.outputFS().WriteFile(path, data, 0644)
+}
+
+// readFile reads a previously generated file back from the output
+// filesystem when it supports reading, and from the OS otherwise
+func (g *Generator) readFile(path string) ([]byte, error) {
+	if readFS, ok := g.outputFS().(interface {
+		ReadFile(name string) ([]byte, error)
+	}); ok {
+		return readFS.ReadFile(path)
+	}
+	return os.ReadFile(path)
+}
+
+// mkdirAll creates a directory on the output filesystem when it supports it
+func (g *Generator) mkdirAll(path string) error {
+	if g.captured != nil {
+		return nil
+	}
+	if mkdirFS, ok := g.outputFS().(MkdirAllFS); ok {
+		return mkdirFS.MkdirAll(path, 0755)
+	}
+	return nil
+}
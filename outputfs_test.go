@@ -0,0 +1,45 @@
+package genstruct
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestOutputFS tests that generated files are written to the configured
+// filesystem instead of the disk
+func TestOutputFS(t *testing.T) {
+	type Animal struct {
+		ID   string
+		Name string
+	}
+	animals := []Animal{{ID: "animal-1", Name: "Leo"}}
+
+	dir := t.TempDir()
+	memFS := &MemFS{}
+	err := NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile("animals.go"),
+		WithWorkingDir(dir),
+		WithJSONFixturesPerRecord("fixtures"),
+		WithOutputFS(memFS),
+	).Generate(animals)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := memFS.ReadFile(filepath.Join(dir, "animals.go"))
+	if err != nil {
+		t.Fatalf("Error reading output from MemFS: %v", err)
+	}
+	if !strings.Contains(string(content), `Name: "Leo"`) {
+		t.Error("Expected generated code in MemFS")
+	}
+	if _, err := memFS.ReadFile(filepath.Join(dir, "fixtures", "animals", "AnimalAnimal1.json")); err != nil {
+		t.Errorf("Expected per-record fixture in MemFS: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "animals.go")); err == nil {
+		t.Error("Output should not be written to disk when an output FS is set")
+	}
+}
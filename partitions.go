@@ -0,0 +1,71 @@
+package genstruct
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// section is a group of records sharing the value of the partition field
+type section struct {
+	label   string // Comment heading the section, empty when not partitioned
+	first   bool   // Whether this is the first section of the dataset
+	indices []int  // Indexes of the records in dataset order
+}
+
+// sections splits the records of the dataset into sections by the value of
+// PartitionField, ordered by value. Without a partition field, or for datasets
+// without it, all records form a single unlabeled section.
+func (g *Generator) sections(dataValue reflect.Value) []section {
+	var partitioned bool
+	if g.PartitionField != "" && dataValue.Len() > 0 {
+		_, partitioned = reflect.Indirect(dataValue.Index(0)).Type().FieldByName(g.PartitionField)
+	}
+	if !partitioned {
+		all := section{first: true}
+		for i := range dataValue.Len() {
+			all.indices = append(all.indices, i)
+		}
+		return []section{all}
+	}
+
+	byValue := make(map[string]*section)
+	var values []string
+	for i := range dataValue.Len() {
+		value := fmt.Sprint(reflect.Indirect(dataValue.Index(i)).FieldByName(g.PartitionField).Interface())
+		s, ok := byValue[value]
+		if !ok {
+			label := value
+			if label == "" {
+				label = "(empty)"
+			}
+			s = &section{label: g.PartitionField + ": " + label}
+			byValue[value] = s
+			values = append(values, value)
+		}
+		s.indices = append(s.indices, i)
+	}
+	sort.Strings(values)
+
+	sections := make([]section, 0, len(values))
+	for i, value := range values {
+		s := *byValue[value]
+		s.first = i == 0
+		sections = append(sections, s)
+	}
+	return sections
+}
+
+// writeComment writes the comment heading the section to group, separated
+// from the previous section by a blank line
+func (s section) writeComment(group *jen.Group) {
+	if s.label == "" {
+		return
+	}
+	if !s.first {
+		group.Line()
+	}
+	group.Comment(s.label)
+}
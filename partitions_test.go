@@ -0,0 +1,60 @@
+package genstruct
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestPartitionField tests that constants and variables are grouped into
+// commented sections ordered by the partition value
+func TestPartitionField(t *testing.T) {
+	type Animal struct {
+		ID     string
+		Name   string
+		Region string
+	}
+	animals := []Animal{
+		{ID: "lion-001", Name: "Leo", Region: "Africa"},
+		{ID: "panda-001", Name: "Bao", Region: "Asia"},
+		{ID: "zebra-001", Name: "Zed", Region: "Africa"},
+	}
+
+	dir := t.TempDir()
+	err := NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile("animals.go"),
+		WithWorkingDir(dir),
+		WithPartitionField("Region"),
+	).Generate(animals)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "animals.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	output := string(content)
+
+	// Each section appears once for constants and once for variables, with
+	// its records in dataset order
+	order := []string{
+		"// Region: Africa", "AnimalLion001ID", "AnimalZebra001ID",
+		"// Region: Asia", "AnimalPanda001ID",
+		"// Region: Africa", "var AnimalLion001 =", "var AnimalZebra001 =",
+		"// Region: Asia", "var AnimalPanda001 =",
+	}
+	rest := output
+	for _, want := range order {
+		i := strings.Index(rest, want)
+		if i < 0 {
+			t.Fatalf("Expected %q in section order, got:\n%s", want, output)
+		}
+		rest = rest[i+len(want):]
+	}
+	if !strings.Contains(output, "var AllAnimals = []*Animal{&AnimalLion001, &AnimalPanda001, &AnimalZebra001}") {
+		t.Errorf("Expected the slice to keep dataset order, got:\n%s", output)
+	}
+}
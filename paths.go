@@ -0,0 +1,90 @@
+package genstruct
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// findModuleRoot returns the root of the module or workspace containing dir.
+// A go.work file takes precedence over go.mod so that every module in a
+// workspace is considered inside the root. If neither is found, dir itself is
+// returned.
+func findModuleRoot(dir string) string {
+	root := ""
+	for current := dir; ; current = filepath.Dir(current) {
+		if _, err := os.Stat(filepath.Join(current, "go.work")); err == nil {
+			return current
+		}
+		if root == "" {
+			if _, err := os.Stat(filepath.Join(current, "go.mod")); err == nil {
+				root = current
+			}
+		}
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+	if root == "" {
+		return dir
+	}
+	return root
+}
+
+// validateOutputPath ensures that OutputFile resolves inside the current
+// module or workspace unless writing to external paths has been allowed.
+func (g *Generator) validateOutputPath() error {
+	if g.AllowExternalPath {
+		return nil
+	}
+
+	baseDir, err := g.baseDir()
+	if err != nil {
+		return err
+	}
+	root := findModuleRoot(baseDir)
+
+	outputPath, err := filepath.Abs(g.resolvePath(g.OutputFile))
+	if err != nil {
+		return err
+	}
+
+	rel, err := filepath.Rel(root, outputPath)
+	if err != nil ||
+		rel == ".." ||
+		strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ExternalPathError{Path: outputPath, Root: root}
+	}
+	return nil
+}
+
+// baseDir returns the absolute directory that relative paths resolve against:
+// WorkingDir if configured, otherwise the process working directory.
+func (g *Generator) baseDir() (string, error) {
+	if g.WorkingDir != "" {
+		return filepath.Abs(g.WorkingDir)
+	}
+	return os.Getwd()
+}
+
+// relativePath returns path relative to WorkingDir when it lies inside it,
+// undoing resolvePath for display
+func (g *Generator) relativePath(path string) string {
+	if g.WorkingDir == "" {
+		return path
+	}
+	rel, err := filepath.Rel(g.WorkingDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
+// resolvePath resolves a relative path against WorkingDir when one is
+// configured. Absolute paths and paths without a WorkingDir are returned as is.
+func (g *Generator) resolvePath(path string) string {
+	if g.WorkingDir == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(g.WorkingDir, path)
+}
@@ -0,0 +1,62 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestExternalOutputPath tests that writes outside of the module are rejected
+func TestExternalOutputPath(t *testing.T) {
+	type Animal struct {
+		ID   string
+		Name string
+	}
+	animals := []Animal{{ID: "lion", Name: "Leo"}}
+
+	outputFile := filepath.Join(t.TempDir(), "animals.go")
+	generator := NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile(outputFile),
+	)
+	var pathErr ExternalPathError
+	if err := generator.Generate(animals); !errors.As(err, &pathErr) {
+		t.Fatalf("Expected ExternalPathError, got %v", err)
+	}
+
+	generator = NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile(outputFile),
+		WithAllowExternalPath(),
+	)
+	if err := generator.Generate(animals); err != nil {
+		t.Fatalf("Expected external path to be allowed, got %v", err)
+	}
+}
+
+// TestWorkingDir tests that relative output paths resolve against the working dir
+func TestWorkingDir(t *testing.T) {
+	type Animal struct {
+		ID   string
+		Name string
+	}
+	animals := []Animal{{ID: "lion", Name: "Leo"}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile("animals.go"),
+		WithWorkingDir(dir),
+	)
+	if err := generator.Generate(animals); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "animals.go")); err != nil {
+		t.Errorf("Expected output file in working dir: %v", err)
+	}
+	if _, err := os.Stat("animals.go"); err == nil {
+		t.Error("Expected no output file in the process working dir")
+	}
+}
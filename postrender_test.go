@@ -0,0 +1,62 @@
+package genstruct
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestPostRender tests that post-render hooks transform the rendered source
+// in order and that hook errors abort the write
+func TestPostRender(t *testing.T) {
+	type Animal struct {
+		ID   string
+		Name string
+	}
+	animals := []Animal{{ID: "animal-1", Name: "Leo"}}
+
+	dir := t.TempDir()
+	license := []byte("// SPDX-License-Identifier: MIT\n\n")
+	err := NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile("animals.go"),
+		WithWorkingDir(dir),
+		WithPostRender(func(src []byte) ([]byte, error) {
+			return bytes.ReplaceAll(src, []byte("Leo"), []byte("Leonard")), nil
+		}),
+		WithPostRender(func(src []byte) ([]byte, error) {
+			return append(append([]byte(nil), license...), src...), nil
+		}),
+	).Generate(animals)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "animals.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	if !bytes.HasPrefix(content, license) {
+		t.Error("Expected output to start with the license header")
+	}
+	if !strings.Contains(string(content), `Name: "Leonard"`) {
+		t.Error("Expected hooks to run in order on the rendered source")
+	}
+
+	hookErr := errors.New("stamp failed")
+	err = NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile("failed.go"),
+		WithWorkingDir(dir),
+		WithPostRender(func([]byte) ([]byte, error) { return nil, hookErr }),
+	).Generate(animals)
+	if !errors.Is(err, hookErr) {
+		t.Errorf("Expected hook error, got %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(dir, "failed.go")); statErr == nil {
+		t.Error("Output should not be written when a hook fails")
+	}
+}
@@ -0,0 +1,171 @@
+package genstruct
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// generatedHeader is the marker written at the top of every file genstruct generates
+const generatedHeader = "// Code generated by genstruct. DO NOT EDIT."
+
+// FindUnusedRecords scans the Go module rooted at root for references to the
+// variables declared in generatedFile and returns the names of those that are
+// never referenced.
+//
+// References from other genstruct-generated files are ignored, as are the
+// All-slices inside generatedFile that list every record. References between
+// records (e.g. a Post variable pointing at a Tag variable) count as usage, so
+// the returned names can safely be passed to WithPrunedRecords.
+func FindUnusedRecords(root, generatedFile string) ([]string, error) {
+	fset := token.NewFileSet()
+	genAST, err := parser.ParseFile(fset, generatedFile, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the record variables declared in the generated file and the
+	// identifiers they reference
+	used := make(map[string]bool)
+	var records []string
+	for _, decl := range genAST.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			if isRecordIndex(valueSpec) {
+				continue
+			}
+			for _, name := range valueSpec.Names {
+				records = append(records, name.Name)
+			}
+			for _, value := range valueSpec.Values {
+				collectIdents(value, used)
+			}
+		}
+	}
+
+	absGenerated, err := filepath.Abs(generatedFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect identifiers referenced by consuming code in the module
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		if absPath == absGenerated {
+			return nil
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(string(src), generatedHeader) {
+			return nil
+		}
+		file, err := parser.ParseFile(fset, path, src, 0)
+		if err != nil {
+			return err
+		}
+		collectIdents(file, used)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var unused []string
+	for _, name := range records {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	return unused, nil
+}
+
+// isRecordIndex reports whether a variable declaration is an All-slice,
+// i.e. a slice literal made up only of &Var elements
+func isRecordIndex(spec *ast.ValueSpec) bool {
+	if len(spec.Values) != 1 {
+		return false
+	}
+	lit, ok := spec.Values[0].(*ast.CompositeLit)
+	if !ok {
+		return false
+	}
+	if _, ok := lit.Type.(*ast.ArrayType); !ok {
+		return false
+	}
+	for _, elt := range lit.Elts {
+		unary, ok := elt.(*ast.UnaryExpr)
+		if !ok || unary.Op != token.AND {
+			return false
+		}
+		if _, ok := unary.X.(*ast.Ident); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// collectIdents records the name of every identifier found in node
+func collectIdents(node ast.Node, used map[string]bool) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			used[ident.Name] = true
+		}
+		return true
+	})
+}
+
+// isPruned reports whether the record should be left out of the generated code
+func (g *Generator) isPruned(elem reflect.Value) bool {
+	if len(g.PrunedRecords) == 0 {
+		return false
+	}
+	return g.isPrunedName(g.varName(elem))
+}
+
+// isPrunedName reports whether the variable named varName is left out of the
+// generated code
+func (g *Generator) isPrunedName(varName string) bool {
+	return slices.Contains(g.PrunedRecords, varName)
+}
+
+// prunedReference reports whether the referenced variable varName was
+// pruned, in which case the reference can't be generated and a
+// PrunedReferenceError is recorded
+func (g *Generator) prunedReference(varName string) bool {
+	if !g.isPrunedName(varName) {
+		return false
+	}
+	g.addGenError(PrunedReferenceError{Record: g.currentRecord, Variable: varName})
+	return true
+}
@@ -0,0 +1,122 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestFindUnusedRecords tests that unreferenced records are reported and pruned
+func TestFindUnusedRecords(t *testing.T) {
+	type Animal struct {
+		ID   string
+		Name string
+	}
+
+	animals := []Animal{
+		{ID: "lion", Name: "Leo"},
+		{ID: "tiger", Name: "Stripes"},
+		{ID: "bear", Name: "Bruno"},
+	}
+
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, "animals.go")
+	generator := NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile(outputFile),
+		WithAllowExternalPath(),
+	)
+	if err := generator.Generate(animals); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	consumer := "package zoo\n\nvar favorite = AnimalLion\n\nconst tigerID = AnimalTigerID\n"
+	if err := os.WriteFile(filepath.Join(dir, "consumer.go"), []byte(consumer), 0644); err != nil {
+		t.Fatalf("Error writing consumer file: %v", err)
+	}
+
+	unused, err := FindUnusedRecords(dir, outputFile)
+	if err != nil {
+		t.Fatalf("Error finding unused records: %v", err)
+	}
+	if !reflect.DeepEqual(unused, []string{"AnimalBear", "AnimalTiger"}) {
+		t.Errorf("Expected [AnimalBear AnimalTiger], got %v", unused)
+	}
+
+	// Regenerate without the unused records
+	generator = NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile(outputFile),
+		WithAllowExternalPath(),
+		WithPrunedRecords(unused),
+	)
+	if err := generator.Generate(animals); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	if strings.Contains(string(content), "var AnimalBear =") {
+		t.Error("Expected AnimalBear to be pruned from generated code")
+	}
+	if !strings.Contains(string(content), "AnimalLion") {
+		t.Error("Expected AnimalLion to remain in generated code")
+	}
+}
+
+// TestPrunedReference tests that pruning a record referenced by another
+// record is reported instead of generating a reference to a missing variable
+func TestPrunedReference(t *testing.T) {
+	type Tag struct {
+		ID   string
+		Name string
+	}
+	type Post struct {
+		ID       string
+		TagSlugs []string
+		Tags     []*Tag `structgen:"TagSlugs"`
+		MainSlug string
+		Main     *Tag `structgen:"MainSlug"`
+	}
+	posts := []Post{{ID: "post-1", TagSlugs: []string{"go", "web"}, MainSlug: "go"}}
+	tags := []Tag{{ID: "go", Name: "Go"}, {ID: "web", Name: "Web"}}
+
+	generator := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+	)
+	if _, err := generator.Render(posts, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	generator = NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithPrunedRecords([]string{"TagGo"}),
+	)
+	_, err := generator.Render(posts, tags)
+	var prunedErr PrunedReferenceError
+	if !errors.As(err, &prunedErr) || prunedErr.Variable != "TagGo" || prunedErr.Record != "PostPost1" {
+		t.Errorf("Expected PrunedReferenceError for TagGo, got %v", err)
+	}
+
+	// Records no other record references can still be pruned
+	generator = NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithPrunedRecords([]string{"TagWeb"}),
+		WithUsageCounts(),
+	)
+	posts[0].TagSlugs = []string{"go"}
+	src, err := generator.Render(posts, tags)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	if strings.Contains(string(src), "var TagWeb =") || strings.Contains(string(src), "&TagWeb") {
+		t.Errorf("Expected TagWeb to be pruned, got:\n%s", src)
+	}
+}
@@ -0,0 +1,119 @@
+package genstruct
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// isOrderedKind reports whether values of the kind can be compared with <
+func isOrderedKind(kind reflect.Kind) bool {
+	return isNumericKind(kind) && kind != reflect.Complex64 && kind != reflect.Complex128
+}
+
+// lessNumeric reports whether the ordered numeric value a is less than b
+func lessNumeric(a, b reflect.Value) bool {
+	switch {
+	case a.CanInt():
+		return a.Int() < b.Int()
+	case a.CanUint():
+		return a.Uint() < b.Uint()
+	default:
+		return a.Float() < b.Float()
+	}
+}
+
+// rangeIndexName returns the name of the sorted index slice for the given
+// field (e.g. AnimalsSortedByWeight)
+func (g *Generator) rangeIndexName(fieldName string) string {
+	return strings.TrimPrefix(g.sliceName(), "All") + "SortedBy" + fieldName
+}
+
+// rangeQueryName returns the name of the range query helper for the given
+// field (e.g. AnimalsWithWeightBetween)
+func (g *Generator) rangeQueryName(fieldName string) string {
+	return strings.TrimPrefix(g.sliceName(), "All") + "With" + fieldName + "Between"
+}
+
+// generateRangeIndexes creates, for each configured numeric field, a slice of
+// the records sorted by that field and a helper returning the records whose
+// value lies within an inclusive range using binary search
+func (g *Generator) generateRangeIndexes(dataValue reflect.Value) {
+	typeStmt := g.elemTypeStatement(dataValue)
+	elems := g.unprunedRecords(dataValue)
+
+	structType := dataValue.Index(0).Type()
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	for _, fieldName := range g.RangeIndexFields {
+		field, _ := structType.FieldByName(fieldName)
+		indexName := g.rangeIndexName(fieldName)
+		g.generateSortedIndex(indexName, typeStmt, fieldName, elems, lessNumeric)
+
+		// Binary search for the first value >= lo and the first value > hi
+		search := func(op string, bound string) *jen.Statement {
+			return jen.Qual("sort", "Search").Call(
+				jen.Len(jen.Id(indexName)),
+				jen.Func().Params(jen.Id("i").Int()).Bool().Block(
+					jen.Return(jen.Id(indexName).Index(jen.Id("i")).Dot(fieldName).Op(op).Id(bound)),
+				),
+			)
+		}
+
+		queryName := g.rangeQueryName(fieldName)
+		g.declareFunc(fmt.Sprintf("%s returns the %s values whose %s is between lo and hi inclusive, ordered by %s.",
+			queryName, g.TypeName, fieldName, fieldName)).Id(queryName).Params(
+			jen.List(jen.Id("lo"), jen.Id("hi")).Add(g.getTypeStatement(field.Type)),
+		).Index().Op("*").Add(typeStmt.Clone()).Block(
+			jen.Id("start").Op(":=").Add(search(">=", "lo")),
+			jen.Id("end").Op(":=").Add(search(">", "hi")),
+			jen.If(jen.Id("start").Op(">=").Id("end")).Block(
+				jen.Return(jen.Nil()),
+			),
+			jen.Return(jen.Id(indexName).Index(jen.Id("start").Op(":").Id("end").Op(":").Id("end"))),
+		)
+	}
+}
+
+// unprunedRecords returns the records of the dataset left after pruning
+func (g *Generator) unprunedRecords(dataValue reflect.Value) []reflect.Value {
+	var elems []reflect.Value
+	for i := range dataValue.Len() {
+		elem := dataValue.Index(i)
+		if elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		if !g.isPruned(elem) {
+			elems = append(elems, elem)
+		}
+	}
+	return elems
+}
+
+// generateSortedIndex declares a slice of the records sorted by the given
+// field in ascending order. Sorting happens at generation time so none is
+// needed at runtime.
+func (g *Generator) generateSortedIndex(
+	indexName string,
+	typeStmt *jen.Statement,
+	fieldName string,
+	elems []reflect.Value,
+	less func(a, b reflect.Value) bool,
+) {
+	sorted := append([]reflect.Value(nil), elems...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(sorted[i].FieldByName(fieldName), sorted[j].FieldByName(fieldName))
+	})
+
+	g.File.Commentf("%s holds all %s values sorted by %s in ascending order.", indexName, g.TypeName, fieldName)
+	g.File.Var().Id(indexName).Op("=").Index().Op("*").Add(typeStmt.Clone()).ValuesFunc(func(group *jen.Group) {
+		for _, elem := range sorted {
+			group.Op("&").Id(g.varName(elem))
+		}
+	})
+}
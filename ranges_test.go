@@ -0,0 +1,61 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestRangeIndexes tests the sorted index slices and range query helpers
+func TestRangeIndexes(t *testing.T) {
+	type Animal struct {
+		ID         string
+		Weight     float64
+		Difficulty int
+	}
+	animals := []Animal{
+		{ID: "leo", Weight: 190.5, Difficulty: 3},
+		{ID: "mia", Weight: 4.2, Difficulty: 1},
+		{ID: "bo", Weight: 60, Difficulty: 3},
+	}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile("animals.go"),
+		WithWorkingDir(dir),
+		WithRangeIndexes("Weight", "Difficulty"),
+	)
+	if err := generator.Generate(animals); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(dir + "/animals.go")
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	for _, want := range []string{
+		"var AnimalsSortedByWeight = []*Animal{&AnimalMia, &AnimalBo, &AnimalLeo}",
+		"var AnimalsSortedByDifficulty = []*Animal{&AnimalMia, &AnimalLeo, &AnimalBo}",
+		"func AnimalsWithWeightBetween(lo, hi float64) []*Animal {",
+		"func AnimalsWithDifficultyBetween(lo, hi int) []*Animal {",
+		"return AnimalsSortedByWeight[start:end:end]",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in generated code:\n%s", want, content)
+		}
+	}
+
+	// Only ordered numeric fields can be indexed
+	generator = NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile("animals.go"),
+		WithWorkingDir(dir),
+		WithRangeIndexes("ID"),
+	)
+	var configErr ConfigError
+	if err := generator.Generate(animals); !errors.As(err, &configErr) || configErr.Option != "RangeIndexFields" {
+		t.Errorf("Expected RangeIndexFields error, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package genstruct
+
+import (
+	"go/token"
+	"log/slog"
+	"reflect"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// Record is a single declaration supplied directly by another code generator
+// instead of a typed slice. GenerateRecords emits each record as a
+// package-level variable whose value is rendered as a Go literal.
+type Record struct {
+	Name     string   // Name of the declared variable
+	Value    any      // Value rendered as the variable's literal
+	Comments []string // Doc comment lines written above the declaration
+}
+
+// GenerateRecords generates a variable declaration for each record, letting
+// other generators delegate literal emission to genstruct.
+//
+// Records are emitted in order. PackageName and OutputFile are used as
+// configured; if OutputFile is not specified it defaults to
+// records_generated.go.
+//
+// Returns an InvalidRecordError if a record has an invalid name or a nil value.
+func (g *Generator) GenerateRecords(records []Record) error {
+	for _, record := range records {
+		if !token.IsIdentifier(record.Name) {
+			return InvalidRecordError{Name: record.Name, Reason: "name is not a valid Go identifier"}
+		}
+		if record.Value == nil {
+			return InvalidRecordError{Name: record.Name, Reason: "value is nil"}
+		}
+	}
+
+	// Infer the configuration not derived from typed data
+	g.resetInferred()
+	if g.TypeName == "" {
+		g.TypeName = "Record"
+		g.inferred.typeName = true
+	}
+	if g.OutputFile == "" {
+		g.OutputFile = "records_generated.go"
+		g.inferred.outputFile = true
+	}
+	if g.PackageName == "" {
+		g.PackageName = GetPackageNameFromPath(g.OutputFile)
+		g.inferred.packageName = true
+	}
+	g.inferExportMode()
+	if err := g.validateOutputPath(); err != nil {
+		g.Logger.Error("Invalid output path", "error", err)
+		return err
+	}
+
+	hash, err := HashDataset(records)
+	if err != nil {
+		return err
+	}
+	g.Hash = hash
+
+	g.File = jen.NewFile(g.PackageName)
+	if err := g.writePackageComment(); err != nil {
+		return err
+	}
+
+	g.Logger.Info(
+		"Generating records",
+		slog.String("package", g.PackageName),
+		slog.Int("count", len(records)),
+		slog.String("output", g.OutputFile),
+	)
+	for _, record := range records {
+		for _, comment := range record.Comments {
+			g.File.Comment(comment)
+		}
+		g.currentRecord = record.Name
+		g.File.Var().Id(record.Name).Op("=").Add(g.getValueStatement(reflect.ValueOf(record.Value)))
+		g.currentRecord = ""
+	}
+
+	return g.writeOutput()
+}
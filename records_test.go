@@ -0,0 +1,93 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGenerateRecords tests that records supplied by other tools are emitted
+func TestGenerateRecords(t *testing.T) {
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("options"),
+		WithOutputFile("options.go"),
+		WithWorkingDir(dir),
+	)
+
+	records := []Record{
+		{
+			Name:     "DefaultTag",
+			Value:    Tag{ID: "tag-1", Name: "Go", Slug: "go"},
+			Comments: []string{"DefaultTag is the tag applied to new posts."},
+		},
+		{Name: "MaxRetries", Value: 3},
+		{Name: "Aliases", Value: []string{"golang", "go"}},
+	}
+	if err := generator.GenerateRecords(records); err != nil {
+		t.Fatalf("Error generating records: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "options.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	expected := []string{
+		"// DefaultTag is the tag applied to new posts.\nvar DefaultTag = Tag{",
+		"var MaxRetries = 3",
+		`var Aliases = []string{"golang", "go"}`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in generated code:\n%s", want, content)
+		}
+	}
+
+	var recordErr InvalidRecordError
+	err = generator.GenerateRecords([]Record{{Name: "not valid", Value: 1}})
+	if !errors.As(err, &recordErr) {
+		t.Errorf("Expected InvalidRecordError, got %v", err)
+	}
+}
+
+// TestGenerateRecordsIntegers tests that integer records keep their type, so
+// int values stay untyped constants and sized ones are converted instead of
+// being widened to int64 or uint64
+func TestGenerateRecordsIntegers(t *testing.T) {
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("options"),
+		WithOutputFile("options.go"),
+		WithWorkingDir(dir),
+	)
+
+	records := []Record{
+		{Name: "MaxRetries", Value: 3},
+		{Name: "MaxDepth", Value: int8(4)},
+		{Name: "Offset", Value: int64(-2)},
+		{Name: "Port", Value: uint16(8080)},
+		{Name: "Mask", Value: uint(255)},
+	}
+	if err := generator.GenerateRecords(records); err != nil {
+		t.Fatalf("Error generating records: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "options.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	expected := []string{
+		"var MaxRetries = 3\n",
+		"var MaxDepth = int8(4)\n",
+		"var Offset = int64(-2)\n",
+		"var Port = uint16(8080)\n",
+		"var Mask = uint(255)\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in generated code:\n%s", want, content)
+		}
+	}
+}
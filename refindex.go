@@ -0,0 +1,84 @@
+package genstruct
+
+import (
+	"reflect"
+	"strings"
+)
+
+// refIndexKey identifies the index of a reference dataset by the slice it
+// was built from and the fields it matches on
+type refIndexKey struct {
+	sliceType reflect.Type
+	data      uintptr
+	length    int
+	fields    string
+}
+
+// refIndex maps the identifiers of a reference dataset to its structs
+type refIndex map[string]refIndexEntry
+
+// refIndexEntry is the first reference struct found for an identifier, along
+// with the name of the field holding it
+type refIndexEntry struct {
+	index int
+	field string
+}
+
+// indexRefs returns the reference structs of refData by identifier, building
+// the index on first use. The identifiers are normalized with the configured
+// RefMatchNormalizer. Returns false for datasets that can't be indexed, such
+// as arrays or slices of interfaces, which are searched record by record.
+func (g *Generator) indexRefs(refData reflect.Value, tag structgenTag) (refIndex, bool) {
+	if refData.Kind() != reflect.Slice {
+		return nil, false
+	}
+	structType := refData.Type().Elem()
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	fields := g.matchFields(tag, structType)
+	key := refIndexKey{
+		sliceType: refData.Type(),
+		data:      refData.Pointer(),
+		length:    refData.Len(),
+		fields:    strings.Join(fields, ","),
+	}
+	if index, ok := g.refIndexes[key]; ok {
+		return index, true
+	}
+
+	index := make(refIndex, refData.Len())
+	for j := range refData.Len() {
+		refStruct := refData.Index(j)
+		if refStruct.Kind() == reflect.Pointer {
+			if refStruct.IsNil() {
+				continue
+			}
+			refStruct = refStruct.Elem()
+		}
+		for _, idField := range fields {
+			refIDField := refStruct.FieldByName(idField)
+			if !refIDField.IsValid() || !isReferenceKeyKind(refIDField.Kind()) {
+				continue
+			}
+			id := referenceKeyText(refIDField)
+			if g.RefMatchNormalizer != nil {
+				id = g.RefMatchNormalizer(id)
+			}
+			// Earlier records and fields take precedence, as when searching
+			if _, exists := index[id]; !exists {
+				index[id] = refIndexEntry{index: j, field: idField}
+			}
+		}
+	}
+
+	if g.refIndexes == nil {
+		g.refIndexes = make(map[refIndexKey]refIndex)
+	}
+	g.refIndexes[key] = index
+	return index, true
+}
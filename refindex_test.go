@@ -0,0 +1,70 @@
+package genstruct
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestRefIndexPrecedence tests that identifiers shared by several reference
+// structs resolve to the first of them, trying the identifier fields in order
+func TestRefIndexPrecedence(t *testing.T) {
+	tags := []Tag{
+		{ID: "go", Name: "Golang", Slug: "golang"},
+		{ID: "tag-2", Name: "Go", Slug: "go"},
+		{ID: "tag-3", Name: "Gopher", Slug: "golang"},
+	}
+	posts := []Post{
+		{ID: "post-1", Title: "Intro", TagSlugs: []string{"go", "golang"}},
+	}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("testdata"),
+		WithOutputFile("test_posts.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+		WithDuplicateNameWarnings(),
+	)
+	if err := generator.Generate(posts, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "test_posts.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "[]*Tag{&TagGolang, &TagGolang}") {
+		t.Errorf("Expected both references to resolve to the first tag, got:\n%s", content)
+	}
+}
+
+// TestRefIndexLargeDataset tests that datasets with many cross-references
+// resolve every reference
+func TestRefIndexLargeDataset(t *testing.T) {
+	const count = 20000
+	tags := make([]Tag, count)
+	posts := make([]Post, count)
+	for i := range count {
+		id := strconv.Itoa(i)
+		tags[i] = Tag{ID: "tag-" + id, Name: "Tag " + id, Slug: "tag-" + id}
+		posts[i] = Post{
+			ID:       "post-" + id,
+			Title:    "Post " + id,
+			TagSlugs: []string{"tag-" + id, "tag-" + strconv.Itoa((i+1)%count)},
+		}
+	}
+
+	src, err := NewGenerator(
+		WithPackageName("testdata"),
+		WithOutputFile("test_posts.go"),
+	).Render(posts, tags)
+	if err != nil {
+		t.Fatalf("Error rendering code: %v", err)
+	}
+	if !strings.Contains(string(src), "[]*Tag{&TagTag19999, &TagTag0}") {
+		t.Error("Expected the last post to reference the last and first tags")
+	}
+}
@@ -0,0 +1,50 @@
+package genstruct
+
+import "reflect"
+
+// ValuesSource is a dataset of records that are already reflected when passed
+// to Generate, Validate or Explain, as the primary dataset or as a reference
+// dataset. Create one with Values.
+type ValuesSource struct {
+	Values []reflect.Value // Records, each holding a value of Type
+	Type   reflect.Type    // Element type of the dataset, a struct or a pointer to a struct
+}
+
+// Values returns a dataset of the reflected records in values, which must
+// all hold a value of typ, for tools that already hold reflected data (e.g.
+// loaded through plugins). The records are copied into a slice of typ
+// without boxing each of them in an interface.
+//
+//	err := generator.Generate(genstruct.Values(records, postType), tags)
+func Values(values []reflect.Value, typ reflect.Type) ValuesSource {
+	return ValuesSource{Values: values, Type: typ}
+}
+
+// GenerateValues generates code for the reflected records like Generate,
+// as a shorthand for passing Values(values, typ) as the primary dataset.
+func (g *Generator) GenerateValues(values []reflect.Value, typ reflect.Type, refs ...any) error {
+	return g.Generate(Values(values, typ), refs...)
+}
+
+// loadValuesSource copies the reflected records into a slice of their type
+func loadValuesSource(source ValuesSource) (any, error) {
+	if source.Type == nil {
+		return nil, InvalidTypeError{Kind: reflect.Invalid}
+	}
+	structType := source.Type
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, InvalidTypeError{Kind: structType.Kind()}
+	}
+
+	records := reflect.MakeSlice(reflect.SliceOf(source.Type), len(source.Values), len(source.Values))
+	for i, value := range source.Values {
+		if !value.IsValid() || value.Type() != source.Type {
+			return nil, ValueTypeError{Index: i, Value: value, Want: source.Type}
+		}
+		records.Index(i).Set(value)
+	}
+	return records.Interface(), nil
+}
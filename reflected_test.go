@@ -0,0 +1,54 @@
+package genstruct
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestGenerateValues tests that reflected records generate the same code as
+// the records themselves, and that records of another type are rejected
+func TestGenerateValues(t *testing.T) {
+	tags := []Tag{{ID: "go", Name: "Go"}, {ID: "rust", Name: "Rust"}}
+	values := make([]reflect.Value, len(tags))
+	for i := range tags {
+		values[i] = reflect.ValueOf(&tags[i])
+	}
+	tagType := reflect.TypeFor[*Tag]()
+
+	want, err := NewGenerator(WithPackageName("blog"), WithOutputFile("tags.go")).Render(tags)
+	if err != nil {
+		t.Fatalf("Error rendering code: %v", err)
+	}
+	got, err := NewGenerator(WithPackageName("blog"), WithOutputFile("tags.go")).Render(Values(values, tagType))
+	if err != nil {
+		t.Fatalf("Error rendering reflected records: %v", err)
+	}
+	for _, exp := range []string{"var TagGo = Tag{", "var AllTags = []*Tag{&TagGo, &TagRust}"} {
+		if !strings.Contains(string(got), exp) {
+			t.Errorf("Expected output to contain %q, got:\n%s", exp, got)
+		}
+	}
+	// Only the hash differs, since the element type is a pointer
+	stripHash := func(src []byte) string {
+		var lines []string
+		for _, line := range strings.Split(string(src), "\n") {
+			if !strings.HasPrefix(line, hashCommentPrefix) {
+				lines = append(lines, line)
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+	if stripHash(got) != stripHash(want) {
+		t.Errorf("Expected reflected records to generate the same code, got:\n%s\nwant:\n%s", got, want)
+	}
+
+	dir := t.TempDir()
+	values = append(values, reflect.ValueOf(Post{ID: "post-1"}))
+	err = NewGenerator(WithPackageName("blog"), WithWorkingDir(dir)).GenerateValues(values, tagType)
+	var typeErr ValueTypeError
+	if !errors.As(err, &typeErr) || typeErr.Index != 2 {
+		t.Errorf("Expected a ValueTypeError for record 2, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package genstruct
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// Cookie shares its name with http.Cookie
+type Cookie struct {
+	ID   string
+	Name string
+}
+
+// Visit references datasets of two types named Cookie
+type Visit struct {
+	ID          string
+	CookieIDs   []string
+	Cookies     []*Cookie `structgen:"CookieIDs"`
+	HTTPCookie  string
+	Session     *http.Cookie  `structgen:"HTTPCookie,match=Name"`
+	HTTPCookies []http.Cookie `structgen:"CookieIDs,match=Name"`
+}
+
+// TestSameNamedRefTypes tests that reference datasets of types sharing a name
+// are keyed by their package-qualified names and referenced unambiguously
+func TestSameNamedRefTypes(t *testing.T) {
+	visits := []Visit{{ID: "visit-1", CookieIDs: []string{"session"}, HTTPCookie: "session"}}
+	cookies := []Cookie{{ID: "session", Name: "Session"}}
+	httpCookies := []*http.Cookie{{Name: "session", Value: "abc"}}
+
+	generator := NewGenerator(
+		WithPackageName("web"),
+		WithOutputFile("visits.go"),
+		WithWorkingDir(t.TempDir()),
+		WithRefPrefix("http.Cookie", "Browser"),
+	)
+	src, err := generator.Render(visits, cookies, httpCookies)
+	if err != nil {
+		t.Fatalf("Error rendering code: %v", err)
+	}
+	output := string(src)
+
+	if _, ok := generator.Refs["genstruct.Cookie"]; !ok {
+		t.Errorf("Expected Refs to be keyed by qualified names, got %v", generator.Refs)
+	}
+	expected := []string{
+		"var GenstructCookieSession = Cookie{",
+		"var AllGenstructCookies = []*Cookie{&GenstructCookieSession}",
+		"var BrowserSession = http.Cookie{",
+		"var AllHttpCookies = []*http.Cookie{&BrowserSession}",
+		"Cookies:     []*Cookie{&GenstructCookieSession},",
+		"HTTPCookies: []http.Cookie{BrowserSession},",
+		"Session:     &BrowserSession,",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q", exp)
+		}
+	}
+	if t.Failed() {
+		t.Logf("Generated output:\n%s", output)
+	}
+}
+
+// Editor is a reference dataset with numeric IDs, as exported from a database
+type Editor struct {
+	ID   int64
+	Name string
+}
+
+// Issue references editors by numeric keys
+type Issue struct {
+	ID            string
+	EditorID      int
+	Editor        *Editor `structgen:"EditorID,strict"`
+	ReviewerIDs   []uint
+	Reviewers     []Editor         `structgen:"ReviewerIDs"`
+	ByReviewer    map[uint]*Editor `structgen:"ReviewerIDs,ptr"`
+	Missing       *Editor          `structgen:"ReviewerCount,omitempty"`
+	ReviewerCount int
+}
+
+// TestNumericReferenceKeys tests that references with numeric keys resolve
+// against numeric identifier fields of another integer type
+func TestNumericReferenceKeys(t *testing.T) {
+	issues := []Issue{{ID: "issue-1", EditorID: 7, ReviewerIDs: []uint{9, 7}}}
+	editors := []Editor{{ID: 7, Name: "Ada"}, {ID: 9, Name: "Grace"}}
+
+	generator := NewGenerator(
+		WithPackageName("tracker"),
+		WithOutputFile("issues.go"),
+		WithWorkingDir(t.TempDir()),
+	)
+	src, err := generator.Render(issues, editors)
+	if err != nil {
+		t.Fatalf("Error rendering code: %v", err)
+	}
+	output := string(src)
+
+	expected := []string{
+		"Editor:        &EditorAda,",
+		"Reviewers:     []Editor{EditorGrace, EditorAda},",
+		"uint(7): &EditorAda,",
+		"uint(9): &EditorGrace,",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q", exp)
+		}
+	}
+	if strings.Contains(output, "Missing:") {
+		t.Error("Expected omitempty to leave out the reference of a zero key")
+	}
+	if t.Failed() {
+		t.Logf("Generated output:\n%s", output)
+	}
+
+	issues[0].EditorID = 8
+	generator = NewGenerator(
+		WithPackageName("tracker"),
+		WithOutputFile("issues.go"),
+		WithWorkingDir(t.TempDir()),
+	)
+	_, err = generator.Render(issues, editors)
+	var refErr UnresolvedReferenceError
+	if !errors.As(err, &refErr) || refErr.Key != "8" {
+		t.Errorf("Expected unresolved reference error for 8, got %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package genstruct
+
+import "io"
+
+// Render generates code for the datasets like Generate and returns the
+// source of the output file instead of writing it, e.g. for in-memory tests
+// or tools embedding genstruct. No files are written, including the ones
+// written next to the output file such as FuncsFile or split reference
+// datasets, which are left in FuncFile and RefFiles. SkipUnchanged is
+// ignored so the source is always rendered.
+func (g *Generator) Render(data any, refs ...any) ([]byte, error) {
+	noWrite, skipUnchanged := g.NoWrite, g.SkipUnchanged
+	g.NoWrite, g.SkipUnchanged = true, false
+	defer func() { g.NoWrite, g.SkipUnchanged = noWrite, skipUnchanged }()
+
+	if err := g.Generate(data, refs...); err != nil {
+		return nil, err
+	}
+	return g.renderOutput()
+}
+
+// GenerateTo generates code for the datasets like Render and writes the
+// source to w, e.g. os.Stdout.
+func (g *Generator) GenerateTo(w io.Writer, data any, refs ...any) error {
+	src, err := g.Render(data, refs...)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
@@ -0,0 +1,53 @@
+package genstruct
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRender tests that Render and GenerateTo return the generated source
+// without writing files
+func TestRender(t *testing.T) {
+	dir := t.TempDir()
+	tags := []Tag{{ID: "go", Name: "Go"}}
+
+	src, err := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("tags.go"),
+		WithWorkingDir(dir),
+		WithSkipUnchanged(),
+		WithChecksum(),
+	).Render(tags)
+	if err != nil {
+		t.Fatalf("Error rendering code: %v", err)
+	}
+	for _, want := range []string{"package blog", "var TagGo = Tag{", "genstruct Checksum: sha256:"} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Expected rendered source to contain %q, got:\n%s", want, src)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tags.go")); !os.IsNotExist(err) {
+		t.Errorf("Expected Render not to write the output file, got %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := NewGenerator(
+		WithPackageName("blog"),
+		WithWorkingDir(dir),
+	).GenerateTo(buf, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	if !strings.Contains(buf.String(), "var TagGo = Tag{") {
+		t.Errorf("Expected GenerateTo to write the generated source, got:\n%s", buf)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected no files to be written, got %v", entries)
+	}
+}
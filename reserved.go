@@ -0,0 +1,63 @@
+package genstruct
+
+import (
+	"log/slog"
+	"slices"
+)
+
+// predeclaredNames are the Go predeclared identifiers together with names
+// that are special at package level, none of which generated symbols may use
+var predeclaredNames = map[string]bool{
+	// Types
+	"any": true, "bool": true, "byte": true, "comparable": true,
+	"complex64": true, "complex128": true, "error": true,
+	"float32": true, "float64": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"rune": true, "string": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	// Constants and zero value
+	"true": true, "false": true, "iota": true, "nil": true,
+	// Functions
+	"append": true, "cap": true, "clear": true, "close": true, "complex": true,
+	"copy": true, "delete": true, "imag": true, "len": true, "make": true,
+	"max": true, "min": true, "new": true, "panic": true, "print": true,
+	"println": true, "real": true, "recover": true,
+	// Special package-level names
+	"init": true, "main": true,
+}
+
+// isReservedName reports whether a generated symbol may not be named name
+func (g *Generator) isReservedName(name string) bool {
+	return predeclaredNames[name] || slices.Contains(g.ReservedNames, name)
+}
+
+// safeName returns the name to declare a generated symbol under. Reserved
+// names are reported as a ReservedNameError, or renamed with a trailing
+// underscore when RenameReserved is set.
+func (g *Generator) safeName(name string) string {
+	if !g.isReservedName(name) {
+		return name
+	}
+
+	if !g.RenameReserved {
+		g.addGenError(ReservedNameError{Name: name})
+		return name
+	}
+
+	renamed := name + "_"
+	for g.isReservedName(renamed) {
+		renamed += "_"
+	}
+	if !g.reservedWarned[name] {
+		if g.reservedWarned == nil {
+			g.reservedWarned = make(map[string]bool)
+		}
+		g.reservedWarned[name] = true
+		g.Logger.Warn(
+			"Renamed generated symbol colliding with a reserved name",
+			slog.String("name", name),
+			slog.String("renamed", renamed),
+		)
+	}
+	return renamed
+}
@@ -0,0 +1,78 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestReservedNames tests that generated symbols colliding with reserved
+// names are refused or renamed
+func TestReservedNames(t *testing.T) {
+	type Builtin struct {
+		ID   string
+		Name string
+	}
+	builtins := []Builtin{
+		{ID: "builtin-1", Name: "len"},
+		{ID: "builtin-2", Name: "Version"},
+	}
+	dir := t.TempDir()
+	newGenerator := func(opts ...Option) *Generator {
+		return NewGenerator(append([]Option{
+			WithPackageName("builtins"),
+			WithOutputFile("builtins.go"),
+			WithWorkingDir(dir),
+			WithIdentifierFields([]string{"Name"}),
+			WithReservedNames("BuiltinVersion"),
+		}, opts...)...)
+	}
+
+	// Without renaming, every collision is reported
+	err := newGenerator().Generate(builtins)
+	var reservedErr ReservedNameError
+	if !errors.As(err, &reservedErr) {
+		t.Fatalf("Expected ReservedNameError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "BuiltinVersion") {
+		t.Errorf("Expected user-reserved name BuiltinVersion in error, got %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(dir, "builtins.go")); statErr == nil {
+		t.Error("Output should not be written when names collide")
+	}
+
+	// With renaming, colliding symbols get a trailing underscore
+	if err := newGenerator(WithRenameReserved()).Generate(builtins); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "builtins.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	output := string(content)
+	if !strings.Contains(output, "var BuiltinLen = ") {
+		t.Error("Unreserved names should be kept")
+	}
+	if !strings.Contains(output, "var BuiltinVersion_ = ") {
+		t.Error("Expected BuiltinVersion to be renamed to BuiltinVersion_")
+	}
+	if !strings.Contains(output, "&BuiltinVersion_") {
+		t.Error("Expected slice to reference the renamed variable")
+	}
+}
+
+// TestPredeclaredNamesReserved tests that Go predeclared identifiers and
+// special package-level names are always reserved
+func TestPredeclaredNamesReserved(t *testing.T) {
+	g := NewGenerator()
+	for _, name := range []string{"len", "min", "init", "main", "string", "nil"} {
+		if !g.isReservedName(name) {
+			t.Errorf("Expected %s to be reserved", name)
+		}
+	}
+	if g.isReservedName("Len") {
+		t.Error("Exported names should not be reserved by default")
+	}
+}
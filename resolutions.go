@@ -0,0 +1,164 @@
+package genstruct
+
+import (
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ResolutionReport describes how the structgen references of the last
+// Generate call resolved, so that pipelines can assert every key resolved
+// without parsing the generated code.
+type ResolutionReport struct {
+	Fields []FieldResolution
+}
+
+// FieldResolution counts the resolved reference keys of one structgen field.
+type FieldResolution struct {
+	Field      string       // Field as Type.Field, e.g. "Post.Tags"
+	Keys       int          // Number of reference keys in the source field
+	Resolved   int          // Number of keys that resolved to a record
+	Unresolved []Resolution // Keys without a matching record
+}
+
+// Complete reports whether every reference key resolved to a record.
+func (r ResolutionReport) Complete() bool {
+	for _, field := range r.Fields {
+		if field.Resolved != field.Keys {
+			return false
+		}
+	}
+	return true
+}
+
+// resolutionReport reports how the structgen fields of the primary dataset
+// and of the reference datasets resolve, in that order with the reference
+// datasets ordered by name. Pruned records of the primary dataset are left
+// out.
+func (g *Generator) resolutionReport() ResolutionReport {
+	var report ResolutionReport
+	add := func(typeName, varPrefix string, records []reflect.Value) {
+		if len(records) == 0 {
+			return
+		}
+		structType := records[0].Type()
+		for i := range structType.NumField() {
+			field := structType.Field(i)
+			tagValue, ok := field.Tag.Lookup("structgen")
+			if !ok || tagValue == "" || !field.IsExported() {
+				continue
+			}
+			tag, err := parseStructgenTag(field, tagValue)
+			if err != nil {
+				continue
+			}
+			fieldReport := FieldResolution{Field: typeName + "." + field.Name}
+			for _, resolution := range g.fieldResolutions(records, varPrefix, field, tag) {
+				fieldReport.Keys++
+				if resolution.Variable != "" {
+					fieldReport.Resolved++
+				} else {
+					fieldReport.Unresolved = append(fieldReport.Unresolved, resolution)
+				}
+			}
+			report.Fields = append(report.Fields, fieldReport)
+		}
+	}
+
+	if structType := g.dataStructType(); structType != nil {
+		add(structType.Name(), g.VarPrefix, g.unprunedRecords(reflect.ValueOf(g.Data)))
+	}
+	for _, typeName := range slices.Sorted(maps.Keys(g.Refs)) {
+		refData := reflect.ValueOf(g.Refs[typeName])
+		if refData.Kind() != reflect.Slice && refData.Kind() != reflect.Array {
+			continue
+		}
+		var records []reflect.Value
+		for i := range refData.Len() {
+			if elem := reflect.Indirect(refData.Index(i)); elem.Kind() == reflect.Struct {
+				records = append(records, elem)
+			}
+		}
+		add(typeName, g.refPrefix(typeName), records)
+	}
+
+	keys, resolved := 0, 0
+	for _, field := range report.Fields {
+		keys += field.Keys
+		resolved += field.Resolved
+		if field.Resolved != field.Keys {
+			g.Logger.Debug(
+				"Unresolved references",
+				slog.String("field", field.Field),
+				slog.Int("unresolved", field.Keys-field.Resolved),
+			)
+		}
+	}
+	if len(report.Fields) > 0 {
+		g.Logger.Info(
+			"Resolved references",
+			slog.Int("fields", len(report.Fields)),
+			slog.Int("keys", keys),
+			slog.Int("resolved", resolved),
+		)
+	}
+	return report
+}
+
+// fieldResolutions resolves the keys of a structgen field for each of the
+// records, named with varPrefix. Interface fields are resolved against every
+// reference dataset implementing the interface, or the one named by their
+// typeField. Empty single keys are not references and are left out.
+func (g *Generator) fieldResolutions(records []reflect.Value, varPrefix string, field reflect.StructField, tag structgenTag) []Resolution {
+	iface := field.Type
+	if iface.Kind() == reflect.Slice {
+		iface = iface.Elem()
+	}
+	var refTypes []string
+	if iface.Kind() == reflect.Interface {
+		refTypes = g.implementingRefs(iface)
+	} else if typeName := g.referencedTypeName(field.Type); typeName != "" {
+		refTypes = []string{typeName}
+	}
+
+	var resolutions []Resolution
+	for _, elem := range records {
+		record := g.safeName(g.prefixedIdentifier(varPrefix, elem))
+		src := elem.FieldByName(tag.Source)
+		if !src.IsValid() || (src.Kind() != reflect.Slice && src.IsZero()) {
+			continue
+		}
+
+		recordRefTypes := refTypes
+		if tag.TypeField != "" {
+			recordRefTypes = nil
+			if typeValue := elem.FieldByName(tag.TypeField); typeValue.Kind() == reflect.String {
+				for _, typeName := range refTypes {
+					if strings.EqualFold(typeName, typeValue.String()) {
+						recordRefTypes = []string{typeName}
+					}
+				}
+			}
+		}
+
+		for _, key := range referenceKeys(src) {
+			resolution := Resolution{Record: record, Key: key}
+			for _, typeName := range recordRefTypes {
+				refDataObj, ok := g.refData(typeName)
+				refData := reflect.ValueOf(refDataObj)
+				if !ok || (refData.Kind() != reflect.Slice && refData.Kind() != reflect.Array) {
+					continue
+				}
+				if refStruct, matchField, found := g.findReference(refData, key, tag); found {
+					resolution.Variable = g.refVarName(typeName, refStruct)
+					resolution.Field = matchField
+					break
+				}
+			}
+			resolutions = append(resolutions, resolution)
+		}
+	}
+	return resolutions
+}
@@ -0,0 +1,63 @@
+package genstruct
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestResolutionReport tests reporting how structgen references resolved
+func TestResolutionReport(t *testing.T) {
+	tags := []Tag{
+		{ID: "tag-1", Name: "Go", Slug: "go"},
+		{ID: "tag-2", Name: "Testing", Slug: "testing"},
+	}
+	posts := []Post{
+		{ID: "post-1", TagSlugs: []string{"go", "rust"}},
+		{ID: "post-2", TagSlugs: []string{"testing"}},
+	}
+
+	generator := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(t.TempDir()),
+	)
+	if err := generator.Generate(posts, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	want := ResolutionReport{Fields: []FieldResolution{{
+		Field:      "Post.Tags",
+		Keys:       3,
+		Resolved:   2,
+		Unresolved: []Resolution{{Record: "PostPost1", Key: "rust"}},
+	}}}
+	if !reflect.DeepEqual(generator.Report, want) {
+		t.Errorf("Expected %+v, got %+v", want, generator.Report)
+	}
+	if generator.Report.Complete() {
+		t.Error("Expected the report to be incomplete")
+	}
+
+	posts[0].TagSlugs = []string{"go"}
+	if err := generator.Generate(posts, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	if !generator.Report.Complete() {
+		t.Errorf("Expected every reference to resolve, got %+v", generator.Report)
+	}
+
+	// The report is available when strict references fail generation
+	type StrictPost struct {
+		ID       string
+		TagSlugs []string
+		Tags     []Tag `structgen:"TagSlugs,strict"`
+	}
+	err := generator.Generate([]StrictPost{{ID: "post-1", TagSlugs: []string{"zig"}}}, tags)
+	var refErr UnresolvedReferenceError
+	if !errors.As(err, &refErr) {
+		t.Fatalf("Expected unresolved reference error, got %v", err)
+	}
+	if len(generator.Report.Fields) != 1 || len(generator.Report.Fields[0].Unresolved) != 1 {
+		t.Errorf("Expected the unresolved key in the report, got %+v", generator.Report)
+	}
+}
@@ -0,0 +1,121 @@
+package genstruct
+
+import (
+	"bufio"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// schemaCommentPrefix prefixes the lines of the package comment recording the
+// pinned schema version and the fields of each generated type
+const schemaCommentPrefix = "// genstruct Schema"
+
+// typeSchema is the list of exported fields of a generated type
+type typeSchema struct {
+	Type   string
+	Fields string // Fields as "Name Type" pairs separated by "; "
+}
+
+// structSchema returns the exported fields of a struct type with their types
+func structSchema(structType reflect.Type) string {
+	var fields []string
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fields = append(fields, field.Name+" "+field.Type.String())
+	}
+	return strings.Join(fields, "; ")
+}
+
+// datasetSchemas returns the schemas of the primary and reference datasets,
+// primary first and references in sorted order
+func (g *Generator) datasetSchemas() []typeSchema {
+	datasets := []any{g.Data}
+	for _, typeName := range g.pendingRefs(nil) {
+		datasets = append(datasets, g.Refs[typeName])
+	}
+
+	var schemas []typeSchema
+	for _, data := range datasets {
+		elemType := reflect.TypeOf(data).Elem()
+		if elemType.Kind() == reflect.Pointer {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() != reflect.Struct {
+			continue
+		}
+		schemas = append(schemas, typeSchema{Type: g.refKey(elemType), Fields: structSchema(elemType)})
+	}
+	return schemas
+}
+
+// schemaComment returns the package comment lines recording the schema
+func (g *Generator) schemaComment() string {
+	if g.SchemaVersion == "" {
+		return ""
+	}
+	lines := []string{schemaCommentPrefix + " Version: " + g.SchemaVersion}
+	for _, schema := range g.schemas {
+		lines = append(lines, schemaCommentPrefix+" "+schema.Type+": "+schema.Fields)
+	}
+	return "\n" + strings.Join(lines, "\n")
+}
+
+// readGeneratedSchema returns the schema version and type schemas recorded in
+// the package comment of a previously generated file
+func readGeneratedSchema(path string) (string, map[string]string) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", nil
+	}
+	defer file.Close()
+
+	version := ""
+	schemas := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "package ") {
+			break
+		}
+		rest, ok := strings.CutPrefix(line, schemaCommentPrefix+" ")
+		if !ok {
+			continue
+		}
+		name, fields, _ := strings.Cut(rest, ": ")
+		if name == "Version" {
+			version = fields
+			continue
+		}
+		schemas[name] = fields
+	}
+	return version, schemas
+}
+
+// checkSchema compares the schemas of the datasets with those recorded in the
+// existing output file, failing if they changed under the same schema version
+func (g *Generator) checkSchema() error {
+	g.loadAllRefs()
+	g.schemas = g.datasetSchemas()
+
+	version, recorded := readGeneratedSchema(g.resolvePath(g.OutputFile))
+	if version != g.SchemaVersion {
+		// First pinned run, or the change was acknowledged by a version bump
+		return nil
+	}
+	for _, schema := range g.schemas {
+		if previous, ok := recorded[schema.Type]; ok && previous != schema.Fields {
+			return SchemaChangedError{
+				Type:     schema.Type,
+				Version:  g.SchemaVersion,
+				Previous: previous,
+				Current:  schema.Fields,
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestSchemaVersion tests that schema changes require a version bump
+func TestSchemaVersion(t *testing.T) {
+	var before, after any
+	{
+		type Item struct {
+			ID    string
+			Price int
+		}
+		before = []Item{{ID: "item-1", Price: 10}}
+	}
+	{
+		type Item struct {
+			ID    string
+			Price float64
+		}
+		after = []Item{{ID: "item-1", Price: 10.5}}
+	}
+
+	dir := t.TempDir()
+	generate := func(version string, data any) error {
+		return NewGenerator(
+			WithPackageName("shop"),
+			WithOutputFile("items.go"),
+			WithWorkingDir(dir),
+			WithSchemaVersion(version),
+		).Generate(data)
+	}
+
+	if err := generate("1", before); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	content, err := os.ReadFile(dir + "/items.go")
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	for _, want := range []string{"// genstruct Schema Version: 1", "// genstruct Schema Item: ID string; Price int"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in generated code:\n%s", want, content)
+		}
+	}
+
+	// Unchanged schemas regenerate fine under the same version
+	if err := generate("1", before); err != nil {
+		t.Errorf("Expected unchanged schema to pass, got %v", err)
+	}
+
+	var schemaErr SchemaChangedError
+	if err := generate("1", after); !errors.As(err, &schemaErr) || schemaErr.Type != "Item" {
+		t.Fatalf("Expected SchemaChangedError, got %v", err)
+	}
+	if schemaErr.Previous != "ID string; Price int" || schemaErr.Current != "ID string; Price float64" {
+		t.Errorf("Unexpected schemas in error: %+v", schemaErr)
+	}
+
+	// Bumping the version acknowledges the change
+	if err := generate("2", after); err != nil {
+		t.Errorf("Expected bumped version to pass, got %v", err)
+	}
+}
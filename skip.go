@@ -0,0 +1,82 @@
+package genstruct
+
+import (
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// skipFieldIndex returns the index of the boolean field of structType tagged
+// `genstruct:"skip"`, or -1 if it has none
+func skipFieldIndex(structType reflect.Type) int {
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		if field.Type.Kind() != reflect.Bool {
+			continue
+		}
+		if slices.Contains(strings.Split(field.Tag.Get("genstruct"), ","), "skip") {
+			return i
+		}
+	}
+	return -1
+}
+
+// dropSkippedRecords returns the dataset without the records whose boolean
+// field tagged `genstruct:"skip"` is true, so soft-deleted records can stay in
+// a dataset file without being generated. Datasets without skipped records
+// are returned unchanged. The source positions of the kept records are
+// recorded for sourceIndex.
+func (g *Generator) dropSkippedRecords(dataset any) any {
+	dataValue := reflect.Indirect(reflect.ValueOf(dataset))
+	if dataValue.Kind() != reflect.Slice && dataValue.Kind() != reflect.Array {
+		return dataset
+	}
+	structType := dataValue.Type().Elem()
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return dataset
+	}
+	skipField := skipFieldIndex(structType)
+	if skipField < 0 {
+		return dataset
+	}
+
+	kept := reflect.MakeSlice(reflect.SliceOf(dataValue.Type().Elem()), 0, dataValue.Len())
+	var positions []int
+	for i := range dataValue.Len() {
+		elem := dataValue.Index(i)
+		if record := reflect.Indirect(elem); record.IsValid() && record.Field(skipField).Bool() {
+			continue
+		}
+		kept = reflect.Append(kept, elem)
+		positions = append(positions, i)
+	}
+	if kept.Len() == dataValue.Len() {
+		return dataset
+	}
+
+	if g.sourceIndexes == nil {
+		g.sourceIndexes = make(map[reflect.Type][]int)
+	}
+	g.sourceIndexes[kept.Type().Elem()] = positions
+
+	g.Logger.Debug(
+		"Skipped records",
+		slog.String("type", structType.Name()),
+		slog.Int("count", dataValue.Len()-kept.Len()),
+	)
+	return kept.Interface()
+}
+
+// sourceIndex returns the position the record at index i of dataValue had in
+// its source dataset before skipped records were dropped, so that positions
+// used in generated code and names don't shift when a record is skipped
+func (g *Generator) sourceIndex(dataValue reflect.Value, i int) int {
+	if positions, ok := g.sourceIndexes[dataValue.Type().Elem()]; ok && i < len(positions) {
+		return positions[i]
+	}
+	return i
+}
@@ -0,0 +1,132 @@
+package genstruct
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSkipRecords tests that records whose skip field is true are left out of
+// the primary and reference datasets, including datasets decoded from JSON
+func TestSkipRecords(t *testing.T) {
+	type Tag struct {
+		ID      string
+		Name    string
+		Deleted bool `genstruct:"skip"`
+	}
+	type Post struct {
+		ID     string
+		Title  string
+		Draft  bool `json:"draft" genstruct:"skip"`
+		TagIDs []string
+		Tags   []*Tag `structgen:"TagIDs"`
+	}
+
+	posts := `[
+		{"ID": "post-1", "Title": "Hello", "TagIDs": ["go", "old"]},
+		{"ID": "post-2", "Title": "Unfinished", "draft": true}
+	]`
+	tags := []*Tag{
+		{ID: "go", Name: "Go"},
+		{ID: "old", Name: "Old", Deleted: true},
+	}
+
+	src, err := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+	).Render(JSONReader[Post](strings.NewReader(posts)), tags)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	output := string(src)
+	for _, want := range []string{"var PostPost1 = ", "var TagGo = ", "[]*Tag{&TagGo}"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	for _, unwanted := range []string{"PostPost2", "Unfinished", "TagOld"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("Expected skipped record %q to be left out, got:\n%s", unwanted, output)
+		}
+	}
+	if len(tags) != 2 {
+		t.Errorf("Expected the reference dataset not to be modified, got %d tags", len(tags))
+	}
+}
+
+// SkipReading is a record without a string identifier whose skip field drops
+// it from the generated dataset
+type SkipReading struct {
+	ID     string
+	Value  float64
+	Hidden bool `genstruct:"skip"`
+}
+
+// TestSkipRecordsPositions tests that the records after a skipped one keep
+// their source positions in the equality test, synthetic IDs and fallback
+// names, and that the generated equality test passes
+func TestSkipRecordsPositions(t *testing.T) {
+	readings := []SkipReading{{Value: 1.5, Hidden: true}, {Value: 2.5}, {Value: 3.5}}
+
+	dir := t.TempDir()
+	err := NewGenerator(
+		WithPackageName("sensors"),
+		WithOutputFile("readings.go"),
+		WithWorkingDir(dir),
+		WithEqualityTest("", "SourceReadings"),
+	).Generate(readings)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "readings.go"))
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	output := string(content)
+	for _, want := range []string{
+		`SkipReading2ID = "skipreading-2"`,
+		`SkipReading3ID = "skipreading-3"`,
+		"var SkipReading2 = ",
+		"var SkipReading3 = ",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "SkipReading1") {
+		t.Errorf("Expected the skipped record to be left out, got:\n%s", output)
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+	files := map[string]string{
+		"go.mod": "module sensors\n\ngo 1.24\n",
+		"types.go": `package sensors
+
+type SkipReading struct {
+	ID     string
+	Value  float64
+	Hidden bool
+}
+
+var SourceReadings = []SkipReading{{Value: 1.5, Hidden: true}, {Value: 2.5}, {Value: 3.5}}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Error writing %s: %v", name, err)
+		}
+	}
+
+	cmd := exec.Command(goBin, "test", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Generated equality test failed: %v\n%s", err, output)
+	}
+}
@@ -0,0 +1,124 @@
+package genstruct
+
+import (
+	"reflect"
+)
+
+// SlugViolation describes a record whose slug field is not URL-safe or
+// collides with the slug of another record in the same route namespace.
+type SlugViolation struct {
+	Type   string // Name of the struct type of the record
+	Record string // Identifier of the record
+	Field  string // Name of the slug field
+	Slug   string // Value of the slug field
+	Reason string // Why the slug is invalid
+}
+
+// isURLSafe reports whether s is non-empty and consists only of characters
+// that need no escaping in a URL path segment (RFC 3986 unreserved characters)
+func isURLSafe(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '.', r == '_', r == '~':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateSlugs checks the SlugFields of every record in the given datasets,
+// reporting slugs that are not URL-safe and slugs used by more than one record
+// of the same dataset, or of datasets sharing a route namespace (see
+// WithRouteNamespace). Datasets without any of the fields are skipped.
+//
+// Returns a SlugError listing every violation, or nil if there are none.
+func (g *Generator) ValidateSlugs(datasets ...any) error {
+	// Map each type sharing a route namespace to the first type of its namespace
+	namespaces := make(map[string]string)
+	for _, types := range g.RouteNamespaces {
+		for _, typeName := range types {
+			namespaces[typeName] = types[0]
+		}
+	}
+
+	var violations []SlugViolation
+	owners := make(map[string]map[string]string) // namespace -> slug -> "Type record"
+	for _, data := range datasets {
+		dataValue := reflect.ValueOf(g.unwrapPointer(data))
+		if (dataValue.Kind() != reflect.Slice && dataValue.Kind() != reflect.Array) ||
+			dataValue.Len() == 0 {
+			continue
+		}
+
+		first := dataValue.Index(0)
+		if first.Kind() == reflect.Pointer {
+			first = first.Elem()
+		}
+		if first.Kind() != reflect.Struct {
+			continue
+		}
+		typeName := first.Type().Name()
+		namespace, ok := namespaces[typeName]
+		if !ok {
+			namespace = typeName
+		}
+
+		for _, fieldName := range g.SlugFields {
+			field, ok := first.Type().FieldByName(fieldName)
+			if !ok || field.Type.Kind() != reflect.String {
+				continue
+			}
+
+			key := namespace + "." + fieldName
+			if owners[key] == nil {
+				owners[key] = make(map[string]string)
+			}
+			for i := range dataValue.Len() {
+				elem := dataValue.Index(i)
+				if elem.Kind() == reflect.Pointer {
+					elem = elem.Elem()
+				}
+				slug := elem.FieldByName(fieldName).String()
+				violation := SlugViolation{
+					Type:   typeName,
+					Record: g.getStructIdentifier(elem),
+					Field:  fieldName,
+					Slug:   slug,
+				}
+
+				if !isURLSafe(slug) {
+					violation.Reason = "not URL-safe"
+					violations = append(violations, violation)
+					continue
+				}
+				if owner, taken := owners[key][slug]; taken {
+					violation.Reason = "already used by " + owner
+					violations = append(violations, violation)
+					continue
+				}
+				owners[key][slug] = typeName + " " + violation.Record
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return SlugError{Violations: violations}
+	}
+	return nil
+}
+
+// validateSlugs checks the slugs of the primary and reference datasets
+func (g *Generator) validateSlugs() error {
+	// Every dataset is needed to check namespaces shared between them
+	g.loadAllRefs()
+	datasets := []any{g.Data}
+	for _, typeName := range g.pendingRefs(nil) {
+		datasets = append(datasets, g.Refs[typeName])
+	}
+	return g.ValidateSlugs(datasets...)
+}
@@ -0,0 +1,52 @@
+package genstruct
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestSlugValidation tests URL-safety and uniqueness checks of slug fields
+func TestSlugValidation(t *testing.T) {
+	type Page struct {
+		ID   string
+		Slug string
+	}
+	tags := []Tag{
+		{ID: "tag-1", Name: "Go", Slug: "go"},
+		{ID: "tag-2", Name: "Golang", Slug: "go"},
+		{ID: "tag-3", Name: "C Sharp", Slug: "c#"},
+	}
+	pages := []Page{{ID: "page-1", Slug: "about"}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("site"),
+		WithWorkingDir(dir),
+		WithSlugValidation(),
+	)
+	err := generator.Generate(tags)
+	var slugErr SlugError
+	if !errors.As(err, &slugErr) {
+		t.Fatalf("Expected SlugError, got %v", err)
+	}
+	want := []SlugViolation{
+		{Type: "Tag", Record: "tag-2", Field: "Slug", Slug: "go", Reason: "already used by Tag tag-1"},
+		{Type: "Tag", Record: "tag-3", Field: "Slug", Slug: "c#", Reason: "not URL-safe"},
+	}
+	if !reflect.DeepEqual(slugErr.Violations, want) {
+		t.Errorf("Expected violations %+v, got %+v", want, slugErr.Violations)
+	}
+
+	// Datasets only conflict when they share a route namespace
+	valid := []Tag{{ID: "tag-1", Name: "About", Slug: "about"}}
+	if err := generator.ValidateSlugs(pages, valid); err != nil {
+		t.Errorf("Expected separate namespaces to be valid, got %v", err)
+	}
+	generator = NewGenerator(WithSlugValidation(), WithRouteNamespace("Page", "Tag"))
+	err = generator.ValidateSlugs(pages, valid)
+	if !errors.As(err, &slugErr) || len(slugErr.Violations) != 1 ||
+		slugErr.Violations[0].Reason != "already used by Page page-1" {
+		t.Errorf("Expected shared namespace collision, got %v", err)
+	}
+}
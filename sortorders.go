@@ -0,0 +1,75 @@
+package genstruct
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// sortOrderLess returns the comparison ordering values of the field type, or
+// nil if they can't be sorted
+func sortOrderLess(fieldType reflect.Type) func(a, b reflect.Value) bool {
+	switch {
+	case fieldType == timeType:
+		return lessTime
+	case isOrderedKind(fieldType.Kind()):
+		return lessNumeric
+	case fieldType.Kind() == reflect.String:
+		return func(a, b reflect.Value) bool { return a.String() < b.String() }
+	case fieldType.Kind() == reflect.Bool:
+		return func(a, b reflect.Value) bool { return !a.Bool() && b.Bool() }
+	}
+	return nil
+}
+
+// generateSortOrders creates, for each configured field, slices of the
+// positions of the records in the slice of all records, in ascending and
+// descending order of the field (e.g. AnimalsByWeightAsc), so the records can
+// be iterated in several orders without a copy of the records per order.
+// Records with equal values keep their order in both.
+func (g *Generator) generateSortOrders(dataValue reflect.Value) {
+	elems := g.unprunedRecords(dataValue)
+	plural := strings.TrimPrefix(g.sliceName(), "All")
+
+	structType := dataValue.Index(0).Type()
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	for _, fieldName := range g.SortOrderFields {
+		field, _ := structType.FieldByName(fieldName)
+		less := sortOrderLess(field.Type)
+
+		for _, order := range []struct {
+			suffix string
+			desc   bool
+		}{{"Asc", false}, {"Desc", true}} {
+			positions := make([]int, len(elems))
+			for i := range positions {
+				positions[i] = i
+			}
+			sort.SliceStable(positions, func(i, j int) bool {
+				a, b := elems[positions[i]].FieldByName(fieldName), elems[positions[j]].FieldByName(fieldName)
+				if order.desc {
+					return less(b, a)
+				}
+				return less(a, b)
+			})
+
+			name := g.safeName(plural + "By" + fieldName + order.suffix)
+			direction := "ascending"
+			if order.desc {
+				direction = "descending"
+			}
+			g.File.Commentf("%s holds the positions in %s of the %s values in %s order of %s.",
+				name, g.sliceName(), g.TypeName, direction, fieldName)
+			g.File.Var().Id(name).Op("=").Index().Int().ValuesFunc(func(group *jen.Group) {
+				for _, position := range positions {
+					group.Lit(position)
+				}
+			})
+		}
+	}
+}
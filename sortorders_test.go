@@ -0,0 +1,57 @@
+package genstruct
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSortOrders tests the position slices of each sort order, which keep
+// records with equal values in their original order
+func TestSortOrders(t *testing.T) {
+	type Animal struct {
+		ID      string
+		Weight  float64
+		Name    string
+		Arrived time.Time
+		Tags    []string
+	}
+	animals := []Animal{
+		{ID: "leo", Weight: 190.5, Name: "Leo", Arrived: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "mia", Weight: 4.2, Name: "Mia", Arrived: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "bo", Weight: 60, Name: "Bo", Arrived: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "kit", Weight: 60, Name: "Kit", Arrived: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	src, err := NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile("animals.go"),
+		WithSortOrders("Weight", "Name", "Arrived"),
+	).Render(animals)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	for _, want := range []string{
+		"var AnimalsByWeightAsc = []int{1, 2, 3, 0}",
+		"var AnimalsByWeightDesc = []int{0, 2, 3, 1}",
+		"var AnimalsByNameAsc = []int{2, 3, 0, 1}",
+		"var AnimalsByArrivedDesc = []int{3, 0, 2, 1}",
+		"// AnimalsByWeightAsc holds the positions in AllAnimals of the Animal values in ascending order of Weight.",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("Expected to find %q in generated code:\n%s", want, src)
+		}
+	}
+
+	// Only fields with an order can be sorted
+	_, err = NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile("animals.go"),
+		WithSortOrders("Tags"),
+	).Render(animals)
+	var configErr ConfigError
+	if !errors.As(err, &configErr) || configErr.Option != "SortOrderFields" {
+		t.Errorf("Expected SortOrderFields error, got %v", err)
+	}
+}
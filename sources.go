@@ -0,0 +1,103 @@
+package genstruct
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// JSONSource is a dataset decoded from a JSON array of records when passed to
+// Generate, Validate or Explain, as the primary dataset or as a reference
+// dataset. Create one with JSONFile or JSONReader, which fix the struct type
+// the records decode to; structgen references are resolved as usual.
+type JSONSource struct {
+	Path   string    // File holding the JSON array, resolved against WorkingDir
+	Reader io.Reader // Reader holding the JSON array, used when Path is empty
+
+	decode func(content []byte) (any, error)
+}
+
+// JSONFile returns a dataset of T decoded from the JSON array in the file at
+// path. The element type T may be a struct or a pointer to a struct.
+//
+//	err := generator.Generate(genstruct.JSONFile[Post]("posts.json"), tags)
+func JSONFile[T any](path string) JSONSource {
+	return JSONSource{Path: path, decode: decodeJSONArray[T]}
+}
+
+// JSONReader returns a dataset of T decoded from the JSON array read from r.
+func JSONReader[T any](r io.Reader) JSONSource {
+	return JSONSource{Reader: r, decode: decodeJSONArray[T]}
+}
+
+// decodeJSONArray decodes a JSON array into a slice of T
+func decodeJSONArray[T any](content []byte) (any, error) {
+	var records []T
+	if err := json.Unmarshal(content, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// loadSources decodes the JSON, CSV, markdown, SQL and reflected sources
+// among the primary and reference datasets, leaving other datasets unchanged,
+// and drops the records marked with a `genstruct:"skip"` field from each
+func (g *Generator) loadSources(data any, refs []any) (any, []any, error) {
+	g.sourceIndexes = nil
+	data, err := g.loadSource(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	loaded := make([]any, len(refs))
+	for i, ref := range refs {
+		if loaded[i], err = g.loadSource(ref); err != nil {
+			return nil, nil, err
+		}
+		loaded[i] = g.dropSkippedRecords(loaded[i])
+	}
+	return g.dropSkippedRecords(data), loaded, nil
+}
+
+// loadSource decodes dataset if it is a JSONSource, a CSVSource, a
+// MarkdownSource, an SQLSource or a ValuesSource
+func (g *Generator) loadSource(dataset any) (any, error) {
+	var (
+		path   string
+		reader io.Reader
+		decode func(content []byte) (any, error)
+	)
+	switch source := dataset.(type) {
+	case JSONSource:
+		path, reader, decode = source.Path, source.Reader, source.decode
+	case CSVSource:
+		path, reader, decode = source.Path, source.Reader, source.decode
+	case MarkdownSource:
+		return g.loadMarkdownSource(source)
+	case SQLSource:
+		return g.loadSQLSource(source)
+	case ValuesSource:
+		return loadValuesSource(source)
+	default:
+		return dataset, nil
+	}
+
+	var (
+		content []byte
+		err     error
+		name    = path
+	)
+	if path != "" {
+		content, err = os.ReadFile(g.resolvePath(path))
+	} else {
+		name = "reader"
+		content, err = io.ReadAll(reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+	records, err := decode(content)
+	if err != nil {
+		return nil, LoadError{Path: name, Err: err}
+	}
+	return records, nil
+}
@@ -0,0 +1,61 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestJSONSources tests generating from JSON files and readers, including
+// structgen references between them
+func TestJSONSources(t *testing.T) {
+	type Tag struct {
+		ID   string
+		Name string
+	}
+	type Post struct {
+		ID     string
+		Title  string
+		TagIDs []string
+		Tags   []*Tag `structgen:"TagIDs"`
+	}
+
+	dir := t.TempDir()
+	posts := `[{"ID": "post-1", "Title": "Hello", "TagIDs": ["go"]}]`
+	if err := os.WriteFile(filepath.Join(dir, "posts.json"), []byte(posts), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tags := strings.NewReader(`[{"ID": "go", "Name": "Go"}]`)
+
+	err := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+	).Generate(JSONFile[Post]("posts.json"), JSONReader[*Tag](tags))
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "posts.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	output := string(content)
+	for _, want := range []string{"var PostPost1 = Post{", `Title:  "Hello"`, "var TagGo = Tag{", "Tags:   []*Tag{&TagGo}"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	err = NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("broken.go"),
+		WithWorkingDir(dir),
+	).Generate(JSONReader[Post](strings.NewReader(`{"ID": "not-an-array"}`)))
+	var loadErr LoadError
+	if !errors.As(err, &loadErr) {
+		t.Errorf("Expected LoadError for a JSON object, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package genstruct
+
+import (
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// refOutputFile returns the file the reference dataset of typeName is written
+// to, or an empty string when it shares the output file
+func (g *Generator) refOutputFile(typeName string) string {
+	path, ok := g.RefOutputFiles[typeName]
+	if !ok {
+		if !g.SplitOutput {
+			return ""
+		}
+		path = filepath.Join(filepath.Dir(g.OutputFile), strings.ToLower(refTypeIdent(typeName))+"_generated.go")
+	}
+	if filepath.Clean(path) == filepath.Clean(g.OutputFile) {
+		return ""
+	}
+	return path
+}
+
+// refFile returns the file the reference dataset of typeName is declared in,
+// creating it on first use when the dataset is split from the output file
+func (g *Generator) refFile(typeName string) *jen.File {
+	path := g.refOutputFile(typeName)
+	if path == "" {
+		return g.File
+	}
+	if file, ok := g.RefFiles[path]; ok {
+		return file
+	}
+	if g.RefFiles == nil {
+		g.RefFiles = make(map[string]*jen.File)
+	}
+	file := jen.NewFile(g.PackageName)
+	g.writeHeader(file)
+	g.RefFiles[path] = file
+	return file
+}
+
+// validateRefOutputPaths makes sure the files of split reference datasets
+// stay inside the module
+func (g *Generator) validateRefOutputPaths() error {
+	for _, typeName := range slices.Sorted(maps.Keys(g.RefOutputFiles)) {
+		fg := *g
+		fg.OutputFile = g.RefOutputFiles[typeName]
+		if err := fg.validateOutputPath(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRefFiles renders the files of split reference datasets and writes them
+func (g *Generator) writeRefFiles() error {
+	for _, path := range slices.Sorted(maps.Keys(g.RefFiles)) {
+		g.Logger.Debug(
+			"Writing reference dataset to file",
+			slog.String("file", path),
+		)
+		if err := g.writeSplit(path, g.RefFiles[path]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
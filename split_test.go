@@ -0,0 +1,78 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSplitOutput tests that reference datasets are written to their own
+// files while references to them still resolve
+func TestSplitOutput(t *testing.T) {
+	type Author struct {
+		ID   string
+		Name string
+	}
+	type Category struct {
+		ID   string
+		Name string
+	}
+	type Article struct {
+		ID         string
+		Title      string
+		AuthorID   string
+		Author     *Author `structgen:"AuthorID"`
+		CategoryID string
+		Category   *Category `structgen:"CategoryID"`
+	}
+
+	articles := []Article{{ID: "hello", Title: "Hello", AuthorID: "alice", CategoryID: "news"}}
+	authors := []Author{{ID: "alice", Name: "Alice"}}
+	categories := []Category{{ID: "news", Name: "News"}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("articles_generated.go"),
+		WithWorkingDir(dir),
+		WithSplitOutput(),
+		WithRefOutputFile("Category", "taxonomy.go"),
+		WithChecksum(),
+	)
+	if err := generator.Generate(articles, authors, categories); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	files := map[string][]string{
+		"articles_generated.go": {"var ArticleHello = Article{", "Author:     &AuthorAlice"},
+		"author_generated.go":   {"// Code generated by genstruct. DO NOT EDIT.", "package blog", "var AuthorAlice = Author{", "var AllAuthors"},
+		"taxonomy.go":           {"var CategoryNews = Category{", "genstruct Checksum: sha256:"},
+	}
+	for name, wants := range files {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Error reading %s: %v", name, err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("Expected %s to contain %q, got:\n%s", name, want, content)
+			}
+		}
+	}
+	main, _ := os.ReadFile(filepath.Join(dir, "articles_generated.go"))
+	if strings.Contains(string(main), "var AuthorAlice") || strings.Contains(string(main), "var CategoryNews") {
+		t.Errorf("Expected reference datasets to be left out of the output file, got:\n%s", main)
+	}
+
+	var configErr ConfigError
+	err := NewGenerator(
+		WithPackageName("blog"),
+		WithWorkingDir(dir),
+		WithRefOutputFile("Author", "authors"),
+	).Generate(articles, authors)
+	if !errors.As(err, &configErr) || configErr.Option != "RefOutputFiles" {
+		t.Errorf("Expected RefOutputFiles ConfigError, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package genstruct
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// SQLSource is a dataset of the rows returned by a database query when passed
+// to Generate, Validate or Explain, as the primary dataset or as a reference
+// dataset. Create one with SQLQuery, which fixes the struct type the rows
+// are scanned into, to snapshot tables such as countries or plans into
+// generated code.
+//
+// A column is mapped to the field whose db tag equals the column name, e.g.
+// `db:"iso_code"`, falling back to the field whose name matches the column
+// ignoring case, spaces, underscores and hyphens. Fields tagged `db:"-"` and
+// columns without a field are ignored. Values are converted as by
+// sql.Rows.Scan, and NULL leaves the field at its zero value.
+type SQLSource struct {
+	DB    *sql.DB
+	Query string
+	Args  []any
+
+	scan func(rows *sql.Rows) (any, error)
+}
+
+// SQLQuery returns a dataset of T scanned from the rows returned by running
+// query with args on db. The element type T may be a struct or a pointer to a
+// struct.
+//
+//	countries := genstruct.SQLQuery[Country](db, "SELECT code, name FROM countries ORDER BY code")
+//	err := generator.Generate(countries)
+func SQLQuery[T any](db *sql.DB, query string, args ...any) SQLSource {
+	return SQLSource{DB: db, Query: query, Args: args, scan: scanRows[T]}
+}
+
+// scanRows scans every row into a T
+func scanRows[T any](rows *sql.Rows) (any, error) {
+	elemType := reflect.TypeFor[T]()
+	structType := elemType
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not a struct type", elemType)
+	}
+
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	columns, err := mapColumns(structType, "db", names)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []T{}
+	for rows.Next() {
+		// Fields are scanned through pointers, which NULL leaves nil
+		dests := make([]any, len(columns))
+		for i, index := range columns {
+			if index == nil {
+				dests[i] = new(any)
+				continue
+			}
+			dests[i] = reflect.New(reflect.PointerTo(structType.FieldByIndex(index).Type)).Interface()
+		}
+		if err := rows.Scan(dests...); err != nil {
+			return nil, err
+		}
+
+		record := reflect.New(structType)
+		for i, index := range columns {
+			if value := reflect.ValueOf(dests[i]).Elem(); index != nil && !value.IsNil() {
+				record.Elem().FieldByIndex(index).Set(value.Elem())
+			}
+		}
+		if elemType.Kind() == reflect.Pointer {
+			records = append(records, record.Interface().(T))
+		} else {
+			records = append(records, record.Elem().Interface().(T))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// loadSQLSource runs the query of source and scans its rows
+func (g *Generator) loadSQLSource(source SQLSource) (any, error) {
+	if source.DB == nil {
+		return nil, LoadError{Path: source.Query, Err: fmt.Errorf("no database given")}
+	}
+	rows, err := source.DB.Query(source.Query, source.Args...)
+	if err != nil {
+		return nil, LoadError{Path: source.Query, Err: err}
+	}
+	defer rows.Close()
+	records, err := source.scan(rows)
+	if err != nil {
+		return nil, LoadError{Path: source.Query, Err: err}
+	}
+	return records, nil
+}
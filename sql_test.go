@@ -0,0 +1,121 @@
+package genstruct
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// staticConnector is a database/sql connector whose queries all return the
+// same rows
+type staticConnector struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (c staticConnector) Connect(context.Context) (driver.Conn, error) { return staticConn{c}, nil }
+func (c staticConnector) Driver() driver.Driver                        { return nil }
+
+type staticConn struct{ connector staticConnector }
+
+func (c staticConn) Prepare(string) (driver.Stmt, error) { return staticStmt(c), nil }
+func (c staticConn) Close() error                        { return nil }
+func (c staticConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+type staticStmt struct{ connector staticConnector }
+
+func (s staticStmt) Close() error  { return nil }
+func (s staticStmt) NumInput() int { return -1 }
+func (s staticStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (s staticStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &staticRows{connector: s.connector}, nil
+}
+
+type staticRows struct {
+	connector staticConnector
+	next      int
+}
+
+func (r *staticRows) Columns() []string { return r.connector.columns }
+func (r *staticRows) Close() error      { return nil }
+func (r *staticRows) Next(dest []driver.Value) error {
+	if r.next == len(r.connector.rows) {
+		return io.EOF
+	}
+	copy(dest, r.connector.rows[r.next])
+	r.next++
+	return nil
+}
+
+// TestSQLSource tests generating from the rows of a database query, mapping
+// columns to fields and leaving NULL columns at their zero value
+func TestSQLSource(t *testing.T) {
+	type Country struct {
+		ISOCode    string `db:"code"`
+		Name       string
+		Population int64
+		JoinedEU   time.Time
+		Motto      string
+	}
+
+	db := sql.OpenDB(staticConnector{
+		columns: []string{"code", "name", "population", "joined_eu", "motto", "capital"},
+		rows: [][]driver.Value{
+			{"fr", "France", int64(68000000), time.Date(1958, time.January, 1, 0, 0, 0, 0, time.UTC), []byte("Liberté"), "Paris"},
+			{"no", "Norway", int64(5500000), nil, nil, "Oslo"},
+		},
+	})
+	defer db.Close()
+
+	dir := t.TempDir()
+	err := NewGenerator(
+		WithPackageName("geo"),
+		WithOutputFile("countries.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Name"}),
+	).Generate(SQLQuery[Country](db, "SELECT * FROM countries"))
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "countries.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	output := string(content)
+	for _, want := range []string{
+		"var CountryFrance = Country{",
+		`ISOCode:    "fr"`,
+		"Population: int64(68000000)",
+		"time.Date(1958, time.January, 1, 0, 0, 0, 0, time.UTC)",
+		`Motto:      "Liberté"`,
+		"var CountryNorway = Country{",
+		`Motto:      "",`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Paris") {
+		t.Errorf("Expected the unmapped capital column to be ignored, got:\n%s", output)
+	}
+
+	err = NewGenerator(
+		WithPackageName("geo"),
+		WithOutputFile("broken.go"),
+		WithWorkingDir(dir),
+	).Generate(SQLQuery[string](db, "SELECT * FROM countries"))
+	var loadErr LoadError
+	if !errors.As(err, &loadErr) || loadErr.Path != "SELECT * FROM countries" {
+		t.Errorf("Expected LoadError naming the query, got %v", err)
+	}
+}
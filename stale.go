@@ -0,0 +1,42 @@
+package genstruct
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// Verify generates code for the datasets like Generate, without writing any
+// files, and returns a StaleOutputError if the generated files on disk differ
+// from it, e.g. to fail a CI build when data was edited but generation wasn't
+// re-run. Besides the Go files, the outputs written next to them (equality
+// tests, examples, TypeScript, JSON fixtures, feeds, notices and views) are
+// compared too. Missing files are stale. SkipUnchanged is ignored so the code
+// is always regenerated.
+func (g *Generator) Verify(data any, refs ...any) error {
+	noWrite, skipUnchanged := g.NoWrite, g.SkipUnchanged
+	g.NoWrite, g.SkipUnchanged = true, false
+	defer func() { g.NoWrite, g.SkipUnchanged = noWrite, skipUnchanged }()
+	g.captured = make(map[string][]byte)
+	defer func() { g.captured = nil }()
+
+	if err := g.Generate(data, refs...); err != nil {
+		return err
+	}
+	diffs, err := g.outputDiffs()
+	if err != nil {
+		return err
+	}
+	if len(diffs) == 0 {
+		return nil
+	}
+
+	stale := StaleOutputError{}
+	diff := &strings.Builder{}
+	for _, d := range diffs {
+		stale.Files = append(stale.Files, d.path)
+		diff.WriteString(d.diff)
+	}
+	stale.Diff = diff.String()
+	g.Logger.Error("Generated code is stale", slog.String("files", strings.Join(stale.Files, ", ")))
+	return stale
+}
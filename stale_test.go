@@ -0,0 +1,102 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestVerifyStale tests that Verify passes for up to date output and reports
+// edited data and missing files as stale without writing anything
+func TestVerifyStale(t *testing.T) {
+	dir := t.TempDir()
+	tags := []Tag{{ID: "go", Name: "Go"}}
+	newGenerator := func() *Generator {
+		return NewGenerator(
+			WithPackageName("blog"),
+			WithOutputFile("tags.go"),
+			WithFuncsFile("tags_funcs.go"),
+			WithWorkingDir(dir),
+			WithSkipUnchanged(),
+		)
+	}
+	if err := newGenerator().Generate(tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	if err := newGenerator().Verify(tags); err != nil {
+		t.Fatalf("Expected up to date output to verify, got %v", err)
+	}
+
+	tags[0].Name = "Golang"
+	err := newGenerator().Verify(tags)
+	var stale StaleOutputError
+	if !errors.As(err, &stale) {
+		t.Fatalf("Expected a StaleOutputError, got %v", err)
+	}
+	if len(stale.Files) != 1 || stale.Files[0] != "tags.go" {
+		t.Errorf("Expected only tags.go to be stale, got %v", stale.Files)
+	}
+	if !strings.Contains(stale.Diff, "+\tName: \"Golang\",\n") {
+		t.Errorf("Expected the diff to show the change, got:\n%s", stale.Diff)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "tags.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "Golang") {
+		t.Errorf("Expected Verify not to write the output file")
+	}
+
+	if err := os.Remove(filepath.Join(dir, "tags_funcs.go")); err != nil {
+		t.Fatal(err)
+	}
+	tags[0].Name = "Go"
+	err = newGenerator().Verify(tags)
+	if !errors.As(err, &stale) || len(stale.Files) != 1 || stale.Files[0] != "tags_funcs.go" {
+		t.Errorf("Expected the missing functions file to be stale, got %v", err)
+	}
+}
+
+// TestVerifyExtraOutputs tests that Verify reports stale and missing outputs
+// written next to the Go files, such as TypeScript and JSON fixtures
+func TestVerifyExtraOutputs(t *testing.T) {
+	dir := t.TempDir()
+	tags := []Tag{{ID: "go", Name: "Go"}}
+	newGenerator := func() *Generator {
+		return NewGenerator(
+			WithPackageName("blog"),
+			WithOutputFile("tags.go"),
+			WithTypeScriptFile("tags.ts"),
+			WithJSONFixtures("fixtures"),
+			WithWorkingDir(dir),
+		)
+	}
+	if err := newGenerator().Generate(tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	if err := newGenerator().Verify(tags); err != nil {
+		t.Fatalf("Expected up to date output to verify, got %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "tags.ts"), []byte("// edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "fixtures", "tags.json")); err != nil {
+		t.Fatal(err)
+	}
+	err := newGenerator().Verify(tags)
+	var stale StaleOutputError
+	if !errors.As(err, &stale) {
+		t.Fatalf("Expected a StaleOutputError, got %v", err)
+	}
+	want := []string{filepath.Join("fixtures", "tags.json"), "tags.ts"}
+	if strings.Join(stale.Files, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v to be stale, got %v", want, stale.Files)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "fixtures", "tags.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected Verify not to write the fixtures, got %v", err)
+	}
+}
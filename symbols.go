@@ -0,0 +1,19 @@
+package genstruct
+
+import (
+	"github.com/dave/jennifer/jen"
+)
+
+// symbolMapName is the name of the generated map of all record variables
+const symbolMapName = "GeneratedSymbols"
+
+// generateSymbolMap declares a map from every generated variable name to a
+// pointer to the variable
+func (g *Generator) generateSymbolMap() {
+	g.File.Comment(symbolMapName + " maps the name of every generated variable to a pointer to it.")
+	g.File.Var().Id(symbolMapName).Op("=").Map(jen.String()).Add(g.anyType()).Values(jen.DictFunc(func(dict jen.Dict) {
+		for _, name := range g.symbols {
+			dict[jen.Lit(name)] = jen.Op("&").Id(name)
+		}
+	}))
+}
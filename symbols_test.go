@@ -0,0 +1,58 @@
+package genstruct
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestSymbolMap tests the map of generated variables across datasets
+func TestSymbolMap(t *testing.T) {
+	tags := []Tag{{ID: "tag-1", Name: "Go", Slug: "go"}}
+	posts := []Post{{ID: "post-1", Title: "Intro", TagSlugs: []string{"go"}}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("testdata"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+		WithSymbolMap(),
+	)
+	if err := generator.Generate(posts, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(dir + "/posts.go")
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	for _, want := range []string{
+		"var GeneratedSymbols = map[string]any{",
+		`"PostPost1": &PostPost1,`,
+		`"TagGo":     &TagGo,`,
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in generated code:\n%s", want, content)
+		}
+	}
+
+	// Older language versions spell the empty interface out
+	generator = NewGenerator(
+		WithPackageName("testdata"),
+		WithOutputFile("tags.go"),
+		WithWorkingDir(dir),
+		WithLangVersion("go1.17"),
+		WithSymbolMap(),
+	)
+	if err := generator.Generate(tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	content, err = os.ReadFile(dir + "/tags.go")
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "map[string]interface{}{") {
+		t.Errorf("Expected interface{} map values for go1.17, got:\n%s", content)
+	}
+}
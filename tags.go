@@ -0,0 +1,135 @@
+package genstruct
+
+import (
+	"reflect"
+	"strings"
+)
+
+// structgenTag is a parsed structgen struct tag.
+//
+// The tag grammar is a source field name followed by comma-separated modifiers:
+//
+//	structgen:"TagSlugs,ptr,match=Slug,strict,omitempty"
+//
+// Supported modifiers:
+//   - ptr: the target must be a pointer (*T), a pointer slice ([]*T) or a
+//     pointer map (map[K]*T)
+//   - match=Field: match references on Field only instead of the identifier
+//     fields, e.g. on Email when both ID and Slug could match. Field must be a
+//     string, boolean or numeric field of the referenced struct.
+//   - strict: keys without a matching reference record are an error
+//   - omitempty: leave the target field out of the literal when the source is empty
+//   - typeField=Field: resolve an interface target only against the reference
+//     dataset whose type name is held by Field, e.g. "Post" or "project"
+type structgenTag struct {
+	Field     string
+	Source    string
+	Ptr       bool
+	Match     string
+	Strict    bool
+	OmitEmpty bool
+	TypeField string
+}
+
+// parseStructgenTag parses the value of a structgen tag on field
+func parseStructgenTag(field reflect.StructField, value string) (structgenTag, error) {
+	parts := strings.Split(value, ",")
+	tag := structgenTag{Field: field.Name, Source: strings.TrimSpace(parts[0])}
+	if tag.Source == "" {
+		return tag, StructgenTagError{Field: field.Name, Tag: value, Reason: "missing source field"}
+	}
+
+	for _, part := range parts[1:] {
+		modifier := strings.TrimSpace(part)
+		name, arg, hasArg := strings.Cut(modifier, "=")
+		switch name {
+		case "ptr", "strict", "omitempty":
+			if hasArg {
+				return tag, StructgenTagError{Field: field.Name, Tag: value, Reason: "modifier " + name + " takes no value"}
+			}
+		case "match", "typeField":
+			if arg == "" {
+				return tag, StructgenTagError{Field: field.Name, Tag: value, Reason: "modifier " + name + " requires a field name"}
+			}
+		default:
+			return tag, StructgenTagError{Field: field.Name, Tag: value, Reason: "unknown modifier " + modifier}
+		}
+
+		switch name {
+		case "ptr":
+			tag.Ptr = true
+		case "strict":
+			tag.Strict = true
+		case "omitempty":
+			tag.OmitEmpty = true
+		case "match":
+			tag.Match = arg
+		case "typeField":
+			tag.TypeField = arg
+		}
+	}
+
+	// ptr documents and enforces that references are shared, not copied
+	if tag.Ptr {
+		targetType := field.Type
+		if targetType.Kind() == reflect.Slice || targetType.Kind() == reflect.Map {
+			targetType = targetType.Elem()
+		}
+		if targetType.Kind() != reflect.Pointer {
+			return tag, StructgenTagError{Field: field.Name, Tag: value, Reason: "modifier ptr requires a *T, []*T or map[K]*T field"}
+		}
+	}
+
+	// A discriminator picks one of the datasets implementing an interface
+	if tag.TypeField != "" && field.Type.Kind() != reflect.Interface {
+		return tag, StructgenTagError{Field: field.Name, Tag: value, Reason: "modifier typeField requires an interface field"}
+	}
+
+	// The match field must exist on a referenced struct, otherwise the field
+	// would silently never resolve
+	if tag.Match != "" {
+		refType := field.Type
+		if refType.Kind() == reflect.Slice || refType.Kind() == reflect.Map {
+			refType = refType.Elem()
+		}
+		if refType.Kind() == reflect.Pointer {
+			refType = refType.Elem()
+		}
+		if refType.Kind() == reflect.Struct {
+			if match, ok := refType.FieldByName(tag.Match); !ok || !isReferenceKeyKind(match.Type.Kind()) {
+				return tag, StructgenTagError{Field: field.Name, Tag: value, Reason: "match field " + tag.Match + " is not a key field of " + refType.Name()}
+			}
+		}
+	}
+
+	return tag, nil
+}
+
+// matchFields returns the fields of the reference type compared against the
+// source keys
+func (g *Generator) matchFields(tag structgenTag, refType reflect.Type) []string {
+	if tag.Match != "" {
+		return []string{tag.Match}
+	}
+	return g.identifierFields(refType)
+}
+
+// identifierFields returns the identifier fields of structType, which are
+// the fields set with WithIdentifierFieldsFor if any
+func (g *Generator) identifierFields(structType reflect.Type) []string {
+	if fields, ok := g.TypeIdentifierFields[structType.Name()]; ok {
+		return fields
+	}
+	return g.IdentifierFields
+}
+
+// addGenError records an error found while generating values, ignoring
+// repeats of the same error reported by other records
+func (g *Generator) addGenError(err error) {
+	for _, existing := range g.genErrors {
+		if existing == err {
+			return
+		}
+	}
+	g.genErrors = append(g.genErrors, err)
+}
@@ -0,0 +1,196 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParseStructgenTag tests parsing of structgen tag modifiers
+func TestParseStructgenTag(t *testing.T) {
+	field := reflect.StructField{Name: "Tags", Type: reflect.TypeOf([]*Tag{})}
+
+	tag, err := parseStructgenTag(field, "TagSlugs, ptr,match=Slug,strict,omitempty")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := structgenTag{Field: "Tags", Source: "TagSlugs", Ptr: true, Match: "Slug", Strict: true, OmitEmpty: true}
+	if tag != want {
+		t.Errorf("Expected %+v, got %+v", want, tag)
+	}
+
+	invalid := map[string]string{
+		"TagSlugs,pointer":  "unknown modifier pointer",
+		"TagSlugs,match":    "modifier match requires a field name",
+		"TagSlugs,strict=1": "modifier strict takes no value",
+		",ptr":              "missing source field",
+	}
+	for value, reason := range invalid {
+		_, err := parseStructgenTag(field, value)
+		var tagErr StructgenTagError
+		if !errors.As(err, &tagErr) || tagErr.Reason != reason {
+			t.Errorf("Tag %q: expected reason %q, got %v", value, reason, err)
+		}
+	}
+
+	valueField := reflect.StructField{Name: "Tags", Type: reflect.TypeOf([]Tag{})}
+	if _, err := parseStructgenTag(valueField, "TagSlugs,ptr"); err == nil {
+		t.Error("Expected ptr on a []T field to be rejected")
+	}
+	if _, err := parseStructgenTag(valueField, "TagSlugs,typeField=Kind"); err == nil {
+		t.Error("Expected typeField on a non-interface field to be rejected")
+	}
+	if _, err := parseStructgenTag(valueField, "TagSlugs,match=Email"); err == nil {
+		t.Error("Expected a match field missing from Tag to be rejected")
+	}
+}
+
+// TestStructgenModifiers tests match, strict and omitempty during generation
+func TestStructgenModifiers(t *testing.T) {
+	type Article struct {
+		ID       string
+		TagNames []string
+		Tags     []*Tag `structgen:"TagNames,ptr,match=Name,omitempty"`
+	}
+	type StrictArticle struct {
+		ID       string
+		TagSlugs []string
+		Tags     []*Tag `structgen:"TagSlugs,strict"`
+	}
+
+	tags := []Tag{
+		{ID: "tag-1", Name: "Go", Slug: "golang"},
+		{ID: "tag-2", Name: "golang", Slug: "go"},
+	}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("testdata"),
+		WithOutputFile("articles.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+	)
+	articles := []Article{
+		{ID: "a-1", TagNames: []string{"golang"}},
+		{ID: "a-2"},
+	}
+	if err := generator.Generate(articles, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	content, err := os.ReadFile(dir + "/articles.go")
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "[]*Tag{&TagGo}") {
+		t.Errorf("Expected match=Name to resolve to TagGo, got:\n%s", content)
+	}
+	if strings.Count(string(content), "Tags:") != 1 {
+		t.Errorf("Expected omitempty to leave out the empty Tags field, got:\n%s", content)
+	}
+
+	// The match field takes precedence over the identifier fields, even when
+	// the key is the slug of another record
+	generator = NewGenerator(
+		WithPackageName("testdata"),
+		WithOutputFile("ids.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+	)
+	type IDArticle struct {
+		ID    string
+		TagID string
+		Tag   *Tag `structgen:"TagID,match=ID"`
+	}
+	ambiguous := []Tag{
+		{ID: "tag-1", Name: "Go", Slug: "tag-2"},
+		{ID: "tag-2", Name: "Rust", Slug: "rust"},
+	}
+	if err := generator.Generate([]IDArticle{{ID: "a-1", TagID: "tag-2"}}, ambiguous); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	content, err = os.ReadFile(dir + "/ids.go")
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "Tag:   &TagRust,") {
+		t.Errorf("Expected match=ID to resolve to TagRust, got:\n%s", content)
+	}
+
+	generator = NewGenerator(
+		WithPackageName("testdata"),
+		WithOutputFile("strict.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+	)
+	strict := []StrictArticle{{ID: "a-1", TagSlugs: []string{"go", "rust"}}}
+	err = generator.Generate(strict, tags)
+	var refErr UnresolvedReferenceError
+	if !errors.As(err, &refErr) || refErr.Key != "rust" || refErr.Field != "Tags" {
+		t.Errorf("Expected unresolved reference error for rust, got %v", err)
+	}
+	if _, err := os.Stat(dir + "/strict.go"); err == nil {
+		t.Error("Expected no output file to be written on error")
+	}
+}
+
+// TestStructgenMapTarget tests populating a map keyed by the source keys
+func TestStructgenMapTarget(t *testing.T) {
+	type Article struct {
+		ID       string
+		TagSlugs []string
+		Tags     map[string]*Tag `structgen:"TagSlugs,ptr,strict"`
+		Copies   map[string]Tag  `structgen:"TagSlugs,omitempty"`
+	}
+
+	tags := []Tag{
+		{ID: "tag-1", Name: "Go", Slug: "go"},
+		{ID: "tag-2", Name: "Rust", Slug: "rust"},
+	}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("testdata"),
+		WithOutputFile("articles.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+	)
+	articles := []Article{
+		{ID: "a-1", TagSlugs: []string{"rust", "go", "go"}},
+		{ID: "a-2"},
+	}
+	if err := generator.Generate(articles, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	content, err := os.ReadFile(dir + "/articles.go")
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	for _, want := range []string{
+		`"go":   &TagGo,`,
+		`"rust": &TagRust,`,
+		`"go":   TagGo,`,
+		"map[string]*Tag{},",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected generated code to contain %q, got:\n%s", want, content)
+		}
+	}
+	if strings.Count(string(content), "Copies:") != 1 {
+		t.Errorf("Expected omitempty to leave out the empty Copies field, got:\n%s", content)
+	}
+
+	generator = NewGenerator(
+		WithPackageName("testdata"),
+		WithOutputFile("strict.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+	)
+	strict := []Article{{ID: "a-1", TagSlugs: []string{"go", "zig"}}}
+	err = generator.Generate(strict, tags)
+	var refErr UnresolvedReferenceError
+	if !errors.As(err, &refErr) || refErr.Key != "zig" || refErr.Field != "Tags" {
+		t.Errorf("Expected unresolved reference error for zig, got %v", err)
+	}
+}
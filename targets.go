@@ -0,0 +1,141 @@
+package genstruct
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Target is one generation run: a generator and the datasets passed to its
+// Generate method.
+type Target struct {
+	Name      string // Name used in errors, defaults to the output file
+	Generator *Generator
+	Data      any
+	Refs      []any
+}
+
+// runConfig holds the settings of RunTargets
+type runConfig struct {
+	parallelism int
+	failFast    bool
+}
+
+// RunOption configures RunTargets
+type RunOption func(*runConfig)
+
+// WithParallelism limits how many targets RunTargets generates at once.
+// Values below one use runtime.GOMAXPROCS(0), which is also the default.
+func WithParallelism(n int) RunOption {
+	return func(c *runConfig) { c.parallelism = n }
+}
+
+// WithFailFast stops RunTargets from starting further targets once one has
+// failed. Targets already running are allowed to finish.
+func WithFailFast() RunOption {
+	return func(c *runConfig) { c.failFast = true }
+}
+
+// RunTargets generates every target concurrently with bounded parallelism.
+// Each target must have its own Generator and write to its own output file,
+// so a failing target never affects the outputs of the others.
+//
+// Unless WithFailFast is used every target runs regardless of failures.
+// Returns a TargetsError listing every failed target, and with WithFailFast
+// every skipped one.
+func RunTargets(targets []Target, opts ...RunOption) error {
+	config := runConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.parallelism < 1 {
+		config.parallelism = runtime.GOMAXPROCS(0)
+	}
+
+	if err := validateTargets(targets); err != nil {
+		return err
+	}
+
+	var (
+		mu     sync.Mutex
+		failed bool
+		errs   = make([]error, len(targets))
+		ran    = make([]bool, len(targets))
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, config.parallelism)
+	)
+	for i, target := range targets {
+		sem <- struct{}{}
+		mu.Lock()
+		skip := config.failFast && failed
+		mu.Unlock()
+		if skip {
+			<-sem
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := target.Generator.Generate(target.Data, target.Refs...)
+			mu.Lock()
+			defer mu.Unlock()
+			ran[i] = true
+			if err != nil {
+				errs[i] = err
+				failed = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	var result TargetsError
+	for i, target := range targets {
+		switch {
+		case errs[i] != nil:
+			result.Failures = append(result.Failures, TargetFailure{Target: target.name(), Err: errs[i]})
+		case !ran[i]:
+			result.Skipped = append(result.Skipped, target.name())
+		}
+	}
+	if len(result.Failures) == 0 && len(result.Skipped) == 0 {
+		return nil
+	}
+	return result
+}
+
+// validateTargets ensures that targets don't share generators or explicitly
+// configured output files, which would make concurrent runs interfere
+func validateTargets(targets []Target) error {
+	generators := make(map[*Generator]string, len(targets))
+	outputs := make(map[string]string, len(targets))
+	for _, target := range targets {
+		name := target.name()
+		if target.Generator == nil {
+			return ConfigError{Option: "target", Value: name, Reason: "no generator"}
+		}
+		if other, ok := generators[target.Generator]; ok {
+			return ConfigError{Option: "target", Value: name, Reason: "shares its generator with target " + other}
+		}
+		generators[target.Generator] = name
+
+		g := target.Generator
+		if g.OutputFile == "" || g.inferred.outputFile {
+			continue
+		}
+		path := g.resolvePath(g.OutputFile)
+		if other, ok := outputs[path]; ok {
+			return ConfigError{Option: "target", Value: name, Reason: "writes " + path + " like target " + other}
+		}
+		outputs[path] = name
+	}
+	return nil
+}
+
+// name returns the name of the target used in errors
+func (t Target) name() string {
+	if t.Name != "" || t.Generator == nil {
+		return t.Name
+	}
+	return t.Generator.OutputFile
+}
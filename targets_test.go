@@ -0,0 +1,73 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRunTargets tests that targets run independently, failures are
+// aggregated per target and fail-fast skips remaining targets
+func TestRunTargets(t *testing.T) {
+	type Animal struct {
+		ID   string
+		Name string
+	}
+	animals := []Animal{{ID: "animal-1", Name: "Leo"}}
+
+	dir := t.TempDir()
+	target := func(file string) Target {
+		return Target{
+			Generator: NewGenerator(
+				WithPackageName("zoo"),
+				WithOutputFile(file),
+				WithWorkingDir(dir),
+			),
+			Data: animals,
+		}
+	}
+
+	broken := target("broken.go")
+	broken.Data = []Animal{}
+	err := RunTargets([]Target{target("a.go"), broken, target("b.go"), target("c.go")}, WithParallelism(2))
+	var targetsErr TargetsError
+	if !errors.As(err, &targetsErr) {
+		t.Fatalf("Expected TargetsError, got %v", err)
+	}
+	if len(targetsErr.Failures) != 1 || targetsErr.Failures[0].Target != "broken.go" {
+		t.Errorf("Expected only broken.go to fail, got %+v", targetsErr.Failures)
+	}
+	if !errors.Is(err, EmptyError{}) {
+		t.Errorf("Expected the target error to be wrapped, got %v", err)
+	}
+	for _, file := range []string{"a.go", "b.go", "c.go"} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("Expected %s to be generated despite the failure: %v", file, err)
+		}
+	}
+
+	broken = target("broken.go")
+	broken.Data = []Animal{}
+	err = RunTargets([]Target{broken, target("d.go")}, WithParallelism(1), WithFailFast())
+	if !errors.As(err, &targetsErr) {
+		t.Fatalf("Expected TargetsError, got %v", err)
+	}
+	if len(targetsErr.Skipped) != 1 || targetsErr.Skipped[0] != "d.go" {
+		t.Errorf("Expected d.go to be skipped, got %v", targetsErr.Skipped)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "d.go")); err == nil {
+		t.Error("Skipped target should not be generated")
+	}
+
+	shared := target("e.go")
+	err = RunTargets([]Target{shared, {Name: "again", Generator: shared.Generator, Data: animals}})
+	var configErr ConfigError
+	if !errors.As(err, &configErr) {
+		t.Errorf("Expected ConfigError for a shared generator, got %v", err)
+	}
+	err = RunTargets([]Target{target("f.go"), target("f.go")})
+	if !errors.As(err, &configErr) {
+		t.Errorf("Expected ConfigError for a shared output file, got %v", err)
+	}
+}
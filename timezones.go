@@ -0,0 +1,105 @@
+package genstruct
+
+import (
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// TimeZoneMode controls how time.Time values with non-UTC locations are emitted.
+type TimeZoneMode int
+
+const (
+	// TimeZoneUTC converts times to UTC, preserving the instant but not the location.
+	TimeZoneUTC TimeZoneMode = iota
+	// TimeZoneFixed emits a time.FixedZone with the name and offset in effect at
+	// that instant, which works without a time zone database.
+	TimeZoneFixed
+	// TimeZoneLocation emits time.LoadLocation calls for named locations and
+	// embeds the time/tzdata package so they succeed on systems without tzdata.
+	TimeZoneLocation
+)
+
+// String returns the name of the mode (e.g. "fixed"), as logged in warnings
+func (m TimeZoneMode) String() string {
+	switch m {
+	case TimeZoneUTC:
+		return "utc"
+	case TimeZoneFixed:
+		return "fixed"
+	case TimeZoneLocation:
+		return "location"
+	}
+	return "TimeZoneMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// getTimeStatement generates a time.Date call for a time value using the
+// configured TimeZoneMode
+func (g *Generator) getTimeStatement(t time.Time) *jen.Statement {
+	location := jen.Qual("time", "UTC")
+
+	if t.Location() != time.UTC {
+		g.recordTimeZone()
+
+		switch {
+		case g.TimeZoneMode == TimeZoneLocation && t.Location() != time.Local:
+			g.File.Anon("time/tzdata")
+			location = jen.Func().Params().Op("*").Qual("time", "Location").Block(
+				jen.List(jen.Id("loc"), jen.Err()).Op(":=").Qual("time", "LoadLocation").Call(jen.Lit(t.Location().String())),
+				jen.If(jen.Err().Op("!=").Nil()).Block(jen.Panic(jen.Err())),
+				jen.Return(jen.Id("loc")),
+			).Call()
+		case g.TimeZoneMode == TimeZoneFixed || g.TimeZoneMode == TimeZoneLocation:
+			name, offset := t.Zone()
+			location = jen.Qual("time", "FixedZone").Call(jen.Lit(name), jen.Lit(offset))
+		default:
+			t = t.UTC()
+		}
+	}
+
+	return jen.Qual("time", "Date").Call(
+		jen.Lit(t.Year()),
+		jen.Qual("time", t.Month().String()),
+		jen.Lit(t.Day()),
+		jen.Lit(t.Hour()),
+		jen.Lit(t.Minute()),
+		jen.Lit(t.Second()),
+		jen.Lit(t.Nanosecond()),
+		location,
+	)
+}
+
+// recordTimeZone remembers that the record currently being generated holds a
+// time with a non-UTC location
+func (g *Generator) recordTimeZone() {
+	if g.currentRecord == "" {
+		return
+	}
+	if g.timeZoneRecords == nil {
+		g.timeZoneRecords = make(map[string]bool)
+	}
+	g.timeZoneRecords[g.currentRecord] = true
+}
+
+// warnTimeZones logs a warning listing the records that hold times with
+// non-UTC locations
+func (g *Generator) warnTimeZones() {
+	if len(g.timeZoneRecords) == 0 {
+		return
+	}
+
+	records := make([]string, 0, len(g.timeZoneRecords))
+	for record := range g.timeZoneRecords {
+		records = append(records, record)
+	}
+	sort.Strings(records)
+
+	g.Logger.Warn(
+		"Records contain times with non-UTC locations",
+		slog.Any("records", records),
+		slog.String("mode", g.TimeZoneMode.String()),
+	)
+}
@@ -0,0 +1,63 @@
+package genstruct
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// TestTimeZoneModes tests how times with non-UTC locations are emitted
+func TestTimeZoneModes(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	value := time.Date(2024, time.January, 15, 9, 30, 0, 0, newYork)
+
+	tests := []struct {
+		mode TimeZoneMode
+		want []string
+	}{
+		{TimeZoneUTC, []string{"time.Date(2024, time.January, 15, 14, 30, 0, 0, time.UTC)"}},
+		{TimeZoneFixed, []string{`time.FixedZone("EST", -18000)`, "15, 9, 30"}},
+		{TimeZoneLocation, []string{`time.LoadLocation("America/New_York")`, "15, 9, 30"}},
+	}
+
+	for _, tt := range tests {
+		g := NewGenerator(WithTimeZoneMode(tt.mode))
+		g.File = jen.NewFile("test")
+		g.currentRecord = "EventLaunch"
+		got := fmt.Sprintf("%#v", g.getTimeStatement(value))
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("mode %s: expected %q in %s", tt.mode, want, got)
+			}
+		}
+		if !g.timeZoneRecords["EventLaunch"] {
+			t.Errorf("mode %s: expected EventLaunch to be recorded as affected", tt.mode)
+		}
+	}
+}
+
+// TestTimeZoneWarning tests that the warning about non-UTC times names the
+// time zone mode
+func TestTimeZoneWarning(t *testing.T) {
+	var logs bytes.Buffer
+	g := NewGenerator(
+		WithTimeZoneMode(TimeZoneFixed),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+	)
+	g.timeZoneRecords = map[string]bool{"EventLaunch": true}
+	g.warnTimeZones()
+	if !strings.Contains(logs.String(), "mode=fixed") {
+		t.Errorf("Expected the warning to name the mode, got %s", logs.String())
+	}
+	if got := TimeZoneMode(7).String(); got != "TimeZoneMode(7)" {
+		t.Errorf("Expected an unknown mode to print its number, got %q", got)
+	}
+}
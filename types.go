@@ -2,13 +2,26 @@ package genstruct
 
 import (
 	"reflect"
-	"strings"
+	"strconv"
 
 	"github.com/dave/jennifer/jen"
 )
 
 // getTypeStatement converts a reflect.Type to a jen.Statement
 func (g *Generator) getTypeStatement(t reflect.Type) *jen.Statement {
+	// Defined numeric types (e.g. type Kilograms float64) keep their name, as
+	// do defined strings, booleans and containers (e.g. Set[string]), whose
+	// underlying types aren't interchangeable with them inside other types
+	if t.PkgPath() != "" && isNumericKind(t.Kind()) {
+		return g.getNamedTypeStatement(t)
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Array, reflect.Slice, reflect.Map:
+		if t.Name() != "" && t.PkgPath() != "" {
+			return g.getNamedTypeStatement(t)
+		}
+	}
+
 	switch t.Kind() {
 	case reflect.Bool:
 		return jen.Bool()
@@ -31,11 +44,15 @@ func (g *Generator) getTypeStatement(t reflect.Type) *jen.Statement {
 		return jen.Id(t.String())
 	case reflect.Array, reflect.Slice:
 		elemType := t.Elem()
+		index := jen.Index()
+		if t.Kind() == reflect.Array {
+			index = jen.Index(jen.Lit(t.Len()))
+		}
 		// Special handling for []*Type pattern
 		if elemType.Kind() == reflect.Pointer {
-			return jen.Index().Add(jen.Op("*").Add(g.getTypeStatement(elemType.Elem())))
+			return index.Add(jen.Op("*").Add(g.getTypeStatement(elemType.Elem())))
 		}
-		return jen.Index().Add(g.getTypeStatement(elemType))
+		return index.Add(g.getTypeStatement(elemType))
 	case reflect.Map:
 		return jen.Map(
 			g.getTypeStatement(t.Key()),
@@ -48,23 +65,36 @@ func (g *Generator) getTypeStatement(t reflect.Type) *jen.Statement {
 			return jen.Qual("time", "Time")
 		}
 
-		// Check if this is from a different package (has a dot in the name)
-		pkgPath := t.PkgPath()
-		// Infer ExportDataMode by checking if output file contains package path separator
-		isExportMode := strings.Contains(g.OutputFile, "/")
-		if pkgPath != "" && pkgPath != "main" && pkgPath != g.PackageName && isExportMode {
-			// If the type comes from a different package, reference it with the package name
-			pkgName := t.String()
-			if lastDot := strings.LastIndex(pkgName, "."); lastDot >= 0 {
-				return jen.Qual(pkgPath, t.Name())
-			}
+		// Anonymous struct types, such as the struct{} of sets, are written out
+		if t.Name() == "" {
+			return jen.StructFunc(func(group *jen.Group) {
+				for i := range t.NumField() {
+					field := t.Field(i)
+					fieldStmt := jen.Id(field.Name)
+					if field.Anonymous {
+						fieldStmt = jen.Null()
+					}
+					fieldStmt.Add(g.getTypeStatement(field.Type))
+					if field.Tag != "" {
+						fieldStmt.Op(strconv.Quote(string(field.Tag)))
+					}
+					group.Add(fieldStmt)
+				}
+			})
 		}
-		return jen.Id(t.Name())
+
+		// Types from a different package are referenced with the package name.
+		// Outside export mode this applies to types declared in packages other
+		// than the data's, such as sql.NullString.
+		return g.namedTypeStatement(t.PkgPath(), t.Name(), g.isLocalType(t))
 	case reflect.Pointer:
 		return jen.Op("*").Add(g.getTypeStatement(t.Elem()))
 	case reflect.Interface:
+		if t.Name() != "" {
+			return g.getNamedTypeStatement(t)
+		}
 		if t.NumMethod() == 0 {
-			return jen.Interface() // empty interface
+			return g.anyType() // empty interface
 		}
 		// Complex interfaces would need more handling
 		return jen.Interface()
@@ -72,3 +102,57 @@ func (g *Generator) getTypeStatement(t reflect.Type) *jen.Statement {
 		return jen.Id(t.String())
 	}
 }
+
+// getNamedTypeStatement returns a reference to a defined (named) type such as
+// `type Kilograms float64`, qualified with its package when it is declared in a
+// package other than the one the generated code lives in
+func (g *Generator) getNamedTypeStatement(t reflect.Type) *jen.Statement {
+	pkgPath := t.PkgPath()
+	if pkgPath == "" || pkgPath == "main" || pkgPath == g.PackageName {
+		return g.namedTypeStatement(pkgPath, t.Name(), true)
+	}
+	return g.namedTypeStatement(pkgPath, t.Name(), !g.ExportMode && pkgPath == g.dataPkgPath())
+}
+
+// isLocalType reports whether the struct type t is declared in the package
+// the code is generated into, so it is referenced without a package name
+func (g *Generator) isLocalType(t reflect.Type) bool {
+	return g.isLocalPkg(t.PkgPath())
+}
+
+// isLocalPkg reports whether types declared in pkgPath are referenced without
+// a package name
+func (g *Generator) isLocalPkg(pkgPath string) bool {
+	if pkgPath == "" || pkgPath == "main" || pkgPath == g.PackageName {
+		return true
+	}
+	dataPkgPath := g.dataPkgPath()
+	return !g.ExportMode && (dataPkgPath == "" || pkgPath == dataPkgPath)
+}
+
+// dataPkgPath returns the package path of the primary data's struct type
+func (g *Generator) dataPkgPath() string {
+	dataType := reflect.TypeOf(g.Data)
+	if dataType == nil ||
+		(dataType.Kind() != reflect.Slice && dataType.Kind() != reflect.Array) {
+		return ""
+	}
+	elemType := dataType.Elem()
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	return elemType.PkgPath()
+}
+
+// isNumericKind reports whether the kind is an integer, float, or complex kind
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Complex64, reflect.Complex128:
+		return true
+	default:
+		return false
+	}
+}
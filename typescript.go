@@ -0,0 +1,353 @@
+package genstruct
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tsDataset is a dataset written to the TypeScript module
+type tsDataset struct {
+	data      reflect.Value
+	elemType  reflect.Type
+	varPrefix string
+	typeName  string
+}
+
+// lowerFirst lowercases the first letter of a Go identifier for use as a
+// TypeScript binding name (e.g. AnimalLeo becomes animalLeo)
+func lowerFirst(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[size:]
+}
+
+// tsFieldName returns the property name of a struct field, following its json
+// tag like encoding/json does, or an empty string if the field is skipped
+func tsFieldName(field reflect.StructField) string {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return field.Name
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// tsStructType returns the struct type of t, dereferencing pointers and
+// unwrapping slices, arrays and maps, or nil if there is none
+func tsStructType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		case reflect.Struct:
+			if t == timeType {
+				return nil
+			}
+			return t
+		default:
+			return nil
+		}
+	}
+}
+
+// tsType returns the TypeScript type of a Go type
+func tsType(t reflect.Type) string {
+	if t == timeType {
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Pointer:
+		return tsType(t.Elem()) + " | null"
+	case reflect.Slice, reflect.Array:
+		elem := tsType(t.Elem())
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case reflect.Map:
+		return "Record<string, " + tsType(t.Elem()) + ">"
+	case reflect.Struct:
+		return t.Name()
+	case reflect.Interface:
+		return "unknown"
+	}
+	if isOrderedKind(t.Kind()) {
+		return "number"
+	}
+	return "unknown"
+}
+
+// tsDatasets returns the datasets to write ordered so that every dataset is
+// declared after the datasets its structgen fields reference
+func (g *Generator) tsDatasets() []tsDataset {
+	datasets := make(map[string]tsDataset)
+	primary := tsDataset{data: reflect.ValueOf(g.Data), varPrefix: g.VarPrefix, typeName: g.TypeName}
+	var names []string
+	for _, typeName := range g.pendingRefs(nil) {
+		names = append(names, typeName)
+		datasets[typeName] = tsDataset{data: reflect.ValueOf(g.Refs[typeName]), varPrefix: g.refPrefix(typeName), typeName: typeName}
+	}
+	names = append(names, g.TypeName)
+	datasets[g.TypeName] = primary
+
+	var ordered []tsDataset
+	visited := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		dataset, ok := datasets[name]
+		if !ok || visited[name] {
+			return
+		}
+		visited[name] = true
+		dataset.elemType = dataset.data.Type().Elem()
+		if dataset.elemType.Kind() == reflect.Pointer {
+			dataset.elemType = dataset.elemType.Elem()
+		}
+		for i := range dataset.elemType.NumField() {
+			field := dataset.elemType.Field(i)
+			if _, ok := field.Tag.Lookup("structgen"); ok {
+				visit(g.referencedTypeName(field.Type))
+			}
+		}
+		ordered = append(ordered, dataset)
+	}
+	for _, name := range names {
+		visit(name)
+	}
+	return ordered
+}
+
+// renderTypeScript renders the datasets as a TypeScript module with an
+// interface per struct type, a const per record and an array per dataset
+func (g *Generator) renderTypeScript() ([]byte, error) {
+	g.loadAllRefs()
+	datasets := g.tsDatasets()
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("// Code generated by genstruct. DO NOT EDIT.\n")
+
+	// Declare an interface for every struct type reachable from the datasets
+	declared := make(map[reflect.Type]bool)
+	var declare func(t reflect.Type)
+	declare = func(t reflect.Type) {
+		if declared[t] {
+			return
+		}
+		declared[t] = true
+		fmt.Fprintf(buf, "\nexport interface %s {\n", t.Name())
+		var nested []reflect.Type
+		for i := range t.NumField() {
+			field := t.Field(i)
+			name := tsFieldName(field)
+			if !field.IsExported() || name == "" {
+				continue
+			}
+			fmt.Fprintf(buf, "  %s: %s;\n", strconv.Quote(name), tsType(field.Type))
+			if nestedType := tsStructType(field.Type); nestedType != nil {
+				nested = append(nested, nestedType)
+			}
+		}
+		buf.WriteString("}\n")
+		for _, nestedType := range nested {
+			declare(nestedType)
+		}
+	}
+	for _, dataset := range datasets {
+		declare(dataset.elemType)
+	}
+
+	// Declare the records and the array of each dataset
+	for _, dataset := range datasets {
+		var records []string
+		for _, elem := range g.unprunedRecords(dataset.data) {
+			name := lowerFirst(g.prefixedIdentifier(dataset.varPrefix, elem))
+			leave := g.enterRecord(elem)
+			value, err := g.tsValue(elem)
+			leave()
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(buf, "\nexport const %s: %s = %s;\n", name, dataset.elemType.Name(), value)
+			records = append(records, name)
+		}
+		fmt.Fprintf(buf, "\nexport const %s = [%s] as const;\n", lowerFirst("All"+g.plural(refTypeIdent(dataset.typeName))), strings.Join(records, ", "))
+	}
+
+	return buf.Bytes(), nil
+}
+
+// tsValue renders a value as a TypeScript literal. Structgen fields of structs
+// refer to the consts of the referenced records.
+func (g *Generator) tsValue(value reflect.Value) (string, error) {
+	if value.Type() == timeType {
+		return strconv.Quote(value.Interface().(time.Time).Format(time.RFC3339Nano)), nil
+	}
+
+	switch value.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.String:
+		text, err := json.Marshal(value.String())
+		return string(text), err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		f := value.Float()
+		switch {
+		case math.IsNaN(f):
+			return "NaN", nil
+		case math.IsInf(f, 1):
+			return "Infinity", nil
+		case math.IsInf(f, -1):
+			return "-Infinity", nil
+		}
+		return strconv.FormatFloat(f, 'g', -1, value.Type().Bits()), nil
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return "null", nil
+		}
+		// Pointers back to an enclosing value are cut as in the Go code
+		if value.Kind() == reflect.Pointer {
+			leave, ok := g.enterPointer(value)
+			if !ok {
+				return "null", nil
+			}
+			defer leave()
+		}
+		return g.tsValue(value.Elem())
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return "[]", nil
+		}
+		items := make([]string, 0, value.Len())
+		for i := range value.Len() {
+			item, err := g.tsValue(value.Index(i))
+			if err != nil {
+				return "", err
+			}
+			items = append(items, item)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case reflect.Map:
+		entries := make([]string, 0, value.Len())
+		for _, key := range value.MapKeys() {
+			item, err := g.tsValue(value.MapIndex(key))
+			if err != nil {
+				return "", err
+			}
+			entries = append(entries, strconv.Quote(fmt.Sprint(key.Interface()))+": "+item)
+		}
+		sort.Strings(entries)
+		return "{" + strings.Join(entries, ", ") + "}", nil
+	case reflect.Struct:
+		return g.tsStructValue(value)
+	}
+	return "", fmt.Errorf("cannot render %s as TypeScript", value.Type())
+}
+
+// tsStructValue renders a struct as a TypeScript object literal
+func (g *Generator) tsStructValue(value reflect.Value) (string, error) {
+	var props []string
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+		name := tsFieldName(field)
+		if !field.IsExported() || name == "" {
+			continue
+		}
+
+		var (
+			text string
+			err  error
+		)
+		if tagValue, ok := field.Tag.Lookup("structgen"); ok && tagValue != "" {
+			text = g.tsReference(value, field, tagValue)
+		} else {
+			text, err = g.tsValue(value.Field(i))
+		}
+		if err != nil {
+			return "", err
+		}
+		props = append(props, "  "+strconv.Quote(name)+": "+text)
+	}
+	if len(props) == 0 {
+		return "{}", nil
+	}
+	return "{\n" + strings.Join(props, ",\n") + ",\n}", nil
+}
+
+// tsReference renders a structgen field as the consts of the records it
+// references, using the same matching as the Go output
+func (g *Generator) tsReference(structValue reflect.Value, field reflect.StructField, tagValue string) string {
+	tag, err := parseStructgenTag(field, tagValue)
+	if err != nil {
+		return "null"
+	}
+	refTypeName := g.referencedTypeName(field.Type)
+	refDataObj, _ := g.refData(refTypeName)
+
+	var names, entries []string
+	for _, key := range referenceKeys(structValue.FieldByName(tag.Source)) {
+		if refDataObj == nil {
+			break
+		}
+		if refStruct, _, found := g.findReference(reflect.ValueOf(refDataObj), key, tag); found {
+			name := lowerFirst(g.prefixedIdentifier(g.refPrefix(refTypeName), refStruct))
+			names = append(names, name)
+			entries = append(entries, strconv.Quote(key)+": "+name)
+		}
+	}
+
+	if field.Type.Kind() == reflect.Map {
+		return "{" + strings.Join(entries, ", ") + "}"
+	}
+	if field.Type.Kind() == reflect.Slice {
+		return "[" + strings.Join(names, ", ") + "]"
+	}
+	if len(names) == 0 {
+		return "null"
+	}
+	return names[0]
+}
+
+// writeTypeScript writes the TypeScript module of the datasets
+func (g *Generator) writeTypeScript() error {
+	tg := *g
+	tg.OutputFile = g.TypeScriptFile
+	if err := tg.validateOutputPath(); err != nil {
+		g.Logger.Error("Invalid TypeScript output path", "error", err)
+		return err
+	}
+
+	content, err := g.renderTypeScript()
+	if err != nil {
+		g.Logger.Error("Failed to render TypeScript", "error", err)
+		return err
+	}
+
+	g.Logger.Debug(
+		"Writing TypeScript to file",
+		slog.String("file", g.TypeScriptFile),
+	)
+	return g.writeFile(g.resolvePath(g.TypeScriptFile), content)
+}
@@ -0,0 +1,81 @@
+package genstruct
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTypeScriptFile tests writing the datasets as a TypeScript module
+func TestTypeScriptFile(t *testing.T) {
+	type Author struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	type Article struct {
+		ID       string    `json:"id"`
+		Title    string    `json:"title"`
+		Date     time.Time `json:"date"`
+		Rating   float64   `json:"rating"`
+		Secret   string    `json:"-"`
+		AuthorID string    `json:"authorId"`
+		Author   *Author   `json:"author" structgen:"AuthorID"`
+		TagSlugs []string
+		Tags     []*Tag `structgen:"TagSlugs"`
+	}
+
+	authors := []Author{{ID: "ana", Name: "Ana"}}
+	tags := []Tag{{ID: "tag-1", Name: "Go", Slug: "go"}}
+	articles := []Article{{
+		ID:       "intro",
+		Title:    `Say "hi"`,
+		Date:     time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		Rating:   4.5,
+		Secret:   "hidden",
+		AuthorID: "ana",
+		TagSlugs: []string{"go"},
+	}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("articles.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+		WithTypeScriptFile("articles.ts"),
+	)
+	if err := generator.Generate(articles, tags, authors); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(dir + "/articles.ts")
+	if err != nil {
+		t.Fatalf("Error reading TypeScript file: %v", err)
+	}
+	ts := string(content)
+	for _, want := range []string{
+		"export interface Article {\n  \"id\": string;\n  \"title\": string;\n  \"date\": string;\n  \"rating\": number;\n",
+		`"author": Author | null;`,
+		`"Tags": (Tag | null)[];`,
+		"export interface Tag {",
+		`"title": "Say \"hi\""`,
+		`"date": "2023-01-02T03:04:05Z"`,
+		`"author": authorAna`,
+		`"Tags": [tagGo]`,
+		"export const allArticles = [articleIntro] as const;",
+	} {
+		if !strings.Contains(ts, want) {
+			t.Errorf("Expected to find %q in TypeScript module:\n%s", want, ts)
+		}
+	}
+	if strings.Contains(ts, "hidden") {
+		t.Errorf("Expected json:\"-\" fields to be skipped:\n%s", ts)
+	}
+
+	// Referenced records are declared before the records referencing them
+	if strings.Index(ts, "export const authorAna") > strings.Index(ts, "export const articleIntro") ||
+		strings.Index(ts, "export const tagGo") > strings.Index(ts, "export const articleIntro") {
+		t.Errorf("Expected references to be declared first:\n%s", ts)
+	}
+}
@@ -0,0 +1,147 @@
+package genstruct
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// referencedTypeName returns the Refs key of the struct type populated by a
+// structgen field, or an empty string if the field type is not supported
+func (g *Generator) referencedTypeName(fieldType reflect.Type) string {
+	if fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Map {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+	if fieldType.Kind() != reflect.Struct {
+		return ""
+	}
+	return g.refKey(fieldType)
+}
+
+// referenceKeys returns the keys held by a source field holding a key or a
+// slice of keys, as compared by referenceKeyText
+func referenceKeys(src reflect.Value) []string {
+	var keys []string
+	switch {
+	case isReferenceKeyKind(src.Kind()):
+		keys = []string{referenceKeyText(src)}
+	case src.Kind() == reflect.Slice && isReferenceKeyKind(src.Type().Elem().Kind()):
+		for j := range src.Len() {
+			keys = append(keys, referenceKeyText(src.Index(j)))
+		}
+	}
+	return keys
+}
+
+// referenceKey returns the value of the first non-empty match field of a
+// reference struct
+func (g *Generator) referenceKey(refStruct reflect.Value, tag structgenTag) string {
+	for _, fieldName := range g.matchFields(tag, refStruct.Type()) {
+		field := refStruct.FieldByName(fieldName)
+		if field.IsValid() && isReferenceKeyKind(field.Kind()) && !field.IsZero() {
+			return referenceKeyText(field)
+		}
+	}
+	return ""
+}
+
+// generateUsageCounts creates, for each reference dataset populated through
+// structgen fields of the primary dataset, a map counting the primary records
+// referencing each reference record (e.g. TagUsageCount["go"] = 2) and a slice
+// of the reference records ordered by that count (e.g. TagsByUsage)
+func (g *Generator) generateUsageCounts(dataValue reflect.Value) {
+	elems := g.unprunedRecords(dataValue)
+	structType := dataValue.Index(0).Type()
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	// Collect the structgen fields by the type they reference, in field order
+	var refTypes []string
+	tagsByType := make(map[string][]structgenTag)
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		tagValue, ok := field.Tag.Lookup("structgen")
+		if !ok || tagValue == "" {
+			continue
+		}
+		tag, err := parseStructgenTag(field, tagValue)
+		if err != nil {
+			continue
+		}
+		typeName := g.referencedTypeName(field.Type)
+		if typeName == "" {
+			continue
+		}
+		if _, seen := tagsByType[typeName]; !seen {
+			refTypes = append(refTypes, typeName)
+		}
+		tagsByType[typeName] = append(tagsByType[typeName], tag)
+	}
+
+	for _, typeName := range refTypes {
+		refDataObj, ok := g.refData(typeName)
+		if !ok {
+			continue
+		}
+		refData := reflect.ValueOf(refDataObj)
+
+		// Count each referencing record once per reference record
+		counts := make(map[string]int)
+		for _, elem := range elems {
+			referenced := make(map[string]bool)
+			for _, tag := range tagsByType[typeName] {
+				for _, key := range referenceKeys(elem.FieldByName(tag.Source)) {
+					if refStruct, _, found := g.findReference(refData, key, tag); found {
+						referenced[g.referenceKey(refStruct, tag)] = true
+					}
+				}
+			}
+			for key := range referenced {
+				counts[key]++
+			}
+		}
+
+		// Order the reference records by usage, keeping dataset order for ties
+		tag := tagsByType[typeName][0]
+		var refs []reflect.Value
+		for j := range refData.Len() {
+			refStruct := refData.Index(j)
+			if refStruct.Kind() == reflect.Pointer {
+				refStruct = refStruct.Elem()
+			}
+			if g.referenceKey(refStruct, tag) != "" {
+				refs = append(refs, refStruct)
+			}
+		}
+		if len(refs) == 0 {
+			continue
+		}
+		sort.SliceStable(refs, func(i, j int) bool {
+			return counts[g.referenceKey(refs[i], tag)] > counts[g.referenceKey(refs[j], tag)]
+		})
+
+		countName := refTypeIdent(typeName) + "UsageCount"
+		g.File.Commentf("%s counts the %s values referencing each %s.", countName, g.TypeName, typeName)
+		g.File.Var().Id(countName).Op("=").Map(jen.String()).Int().Values(jen.DictFunc(func(dict jen.Dict) {
+			for _, refStruct := range refs {
+				key := g.referenceKey(refStruct, tag)
+				dict[jen.Lit(key)] = jen.Lit(counts[key])
+			}
+		}))
+
+		sliceName := g.plural(refTypeIdent(typeName)) + "ByUsage"
+		g.File.Commentf("%s holds all %s values ordered from most to least used.", sliceName, typeName)
+		g.File.Var().Id(sliceName).Op("=").Index().Op("*").Add(g.getTypeStatement(refs[0].Type())).ValuesFunc(func(group *jen.Group) {
+			for _, refStruct := range refs {
+				if varName := g.refVarName(typeName, refStruct); !g.isPrunedName(varName) {
+					group.Op("&").Id(varName)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package genstruct
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestUsageCounts tests the reference frequency maps and usage ordering
+func TestUsageCounts(t *testing.T) {
+	tags := []Tag{
+		{ID: "tag-1", Name: "Go", Slug: "go"},
+		{ID: "tag-2", Name: "Rust", Slug: "rust"},
+		{ID: "tag-3", Name: "Testing", Slug: "testing"},
+	}
+	posts := []Post{
+		{ID: "post-1", Title: "Testing in Go", TagSlugs: []string{"go", "testing"}},
+		{ID: "post-2", Title: "Go Tips", TagSlugs: []string{"go", "go"}},
+		{ID: "post-3", Title: "More Testing", TagSlugs: []string{"testing"}},
+		{ID: "post-4", Title: "Go Again", TagSlugs: []string{"go", "unknown"}},
+	}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("blog"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+		WithUsageCounts(),
+	)
+	if err := generator.Generate(posts, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(dir + "/posts.go")
+	if err != nil {
+		t.Fatalf("Error reading generated file: %v", err)
+	}
+	for _, want := range []string{
+		"var TagUsageCount = map[string]int{",
+		`"go":      3,`,
+		`"rust":    0,`,
+		`"testing": 2,`,
+		"var TagsByUsage = []*Tag{&TagGo, &TagTesting, &TagRust}",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected to find %q in generated code:\n%s", want, content)
+		}
+	}
+}
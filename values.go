@@ -3,6 +3,9 @@ package genstruct
 import (
 	"fmt"
 	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,25 +14,72 @@ import (
 
 // getValueStatement generates code for a value based on its type
 func (g *Generator) getValueStatement(value reflect.Value) *jen.Statement {
+	// Stop at the configured depth rather than exhausting the stack
+	if stmt := g.depthStatement(); stmt != nil {
+		return stmt
+	}
+
+	// Defined numeric types (e.g. type Kilograms float64) are emitted as
+	// conversions such as Kilograms(180.5) to preserve their type
+	if value.Type().PkgPath() != "" && isNumericKind(value.Kind()) {
+		return g.getNamedTypeStatement(value.Type()).Call(g.getUntypedNumberStatement(value))
+	}
+
+	return g.getBasicValueStatement(value)
+}
+
+// getUntypedNumberStatement generates an untyped numeric literal (e.g. 42
+// rather than int64(42)) suitable for wrapping in a type conversion
+func (g *Generator) getUntypedNumberStatement(value reflect.Value) *jen.Statement {
 	switch value.Kind() {
-	case reflect.Bool:
-		return jen.Lit(value.Bool())
 	case reflect.Int,
 		reflect.Int8,
 		reflect.Int16,
 		reflect.Int32,
 		reflect.Int64:
-		return jen.Lit(value.Int())
+		return jen.Op(strconv.FormatInt(value.Int(), 10))
 	case reflect.Uint,
 		reflect.Uint8,
 		reflect.Uint16,
 		reflect.Uint32,
-		reflect.Uint64:
-		return jen.Lit(value.Uint())
+		reflect.Uint64,
+		reflect.Uintptr:
+		return jen.Op(strconv.FormatUint(value.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		return g.getFloatStatement(value.Float(), value.Type().Bits())
+	default:
+		return g.getComplexStatement(value.Complex(), value.Type().Bits())
+	}
+}
+
+// getBasicValueStatement generates code for a value based on its kind
+func (g *Generator) getBasicValueStatement(value reflect.Value) *jen.Statement {
+	switch value.Kind() {
+	case reflect.Bool:
+		return jen.Lit(value.Bool())
+	case reflect.Int:
+		return jen.Lit(int(value.Int()))
+	case reflect.Int8,
+		reflect.Int16,
+		reflect.Int32,
+		reflect.Int64,
+		reflect.Uint,
+		reflect.Uint8,
+		reflect.Uint16,
+		reflect.Uint32,
+		reflect.Uint64,
+		reflect.Uintptr:
+		if stmt := g.getCharStatement(value); stmt != nil {
+			return stmt
+		}
+		// Convert the exact decimal value to the value's own type (e.g.
+		// uint64(18446744073709551615)), which compiles for every value of
+		// the type and keeps its type in interfaces and untyped contexts
+		return jen.Id(value.Kind().String()).Call(g.getUntypedNumberStatement(value))
 	case reflect.Float32, reflect.Float64:
-		return jen.Lit(value.Float())
+		return g.getFloatStatement(value.Float(), value.Type().Bits())
 	case reflect.Complex64, reflect.Complex128:
-		return jen.Lit(value.Complex())
+		return g.getComplexStatement(value.Complex(), value.Type().Bits())
 	case reflect.Array:
 		// Handle arrays properly with their type and dimensions
 		elemType := g.getTypeStatement(value.Type().Elem())
@@ -41,7 +91,9 @@ func (g *Generator) getValueStatement(value reflect.Value) *jen.Statement {
 		// Create values inside the array
 		return arrayType.ValuesFunc(func(group *jen.Group) {
 			for i := range value.Len() {
+				leave := g.enterPath("[" + strconv.Itoa(i) + "]")
 				group.Add(g.getValueStatement(value.Index(i)))
+				leave()
 			}
 		})
 	case reflect.Slice:
@@ -50,7 +102,9 @@ func (g *Generator) getValueStatement(value reflect.Value) *jen.Statement {
 			g.getTypeStatement(value.Type().Elem()),
 		).ValuesFunc(func(group *jen.Group) {
 			for i := range value.Len() {
+				leave := g.enterPath("[" + strconv.Itoa(i) + "]")
 				group.Add(g.getValueStatement(value.Index(i)))
+				leave()
 			}
 		})
 	case reflect.Map:
@@ -60,52 +114,81 @@ func (g *Generator) getValueStatement(value reflect.Value) *jen.Statement {
 	case reflect.Struct:
 		// Special case for time.Time
 		if value.Type().String() == "time.Time" {
-			t := value.Interface().(time.Time)
-			return jen.Qual("time", "Date").Call(
-				jen.Lit(t.Year()),
-				jen.Qual("time", t.Month().String()),
-				jen.Lit(t.Day()),
-				jen.Lit(t.Hour()),
-				jen.Lit(t.Minute()),
-				jen.Lit(t.Second()),
-				jen.Lit(t.Nanosecond()),
-				jen.Qual("time", "UTC"),
-			)
-		}
-
-		// Check if this struct is from another package in export mode
-		isExportMode := strings.Contains(g.OutputFile, "/")
-		pkgPath := value.Type().PkgPath()
-
-		if isExportMode && pkgPath != "" && pkgPath != "main" && pkgPath != g.PackageName {
-			// For structs from another package, use fully qualified names
-			return jen.Qual(pkgPath, value.Type().Name()).ValuesFunc(func(group *jen.Group) {
-				g.generateStructValues(group, value)
-			})
+			return g.getTimeStatement(value.Interface().(time.Time))
 		}
 
-		// For other structs, create a new values block with the struct fields
-		return jen.Id(
-			value.Type().Name(),
-		).ValuesFunc(func(group *jen.Group) {
+		// Third-party types may be rendered with a constructor expression
+		if stmt := g.getVendorStatement(value); stmt != nil {
+			return stmt
+		}
+
+		// Option wrappers such as sql.NullString are rendered compactly
+		if fieldName, ok := optionWrapperField(value.Type()); ok {
+			return g.getOptionWrapperStatement(value, fieldName)
+		}
+
+		// Structs from another package are qualified with their package
+		return g.getTypeStatement(value.Type()).ValuesFunc(func(group *jen.Group) {
 			g.generateStructValues(group, value)
 		})
 	case reflect.Pointer:
 		if value.IsNil() {
 			return jen.Nil()
 		}
-		return jen.Op("&").Add(g.getValueStatement(value.Elem()))
+		leave, ok := g.enterPointer(value)
+		if !ok {
+			return g.cycleStatement(value)
+		}
+		defer leave()
+		if g.isCompositeLiteral(value.Elem()) {
+			return jen.Op("&").Add(g.getValueStatement(value.Elem()))
+		}
+		// Other values aren't addressable, so point to a copy
+		return jen.Func().Params().Op("*").Add(g.getTypeStatement(value.Type().Elem())).Block(
+			jen.Var().Id("v").Add(g.getTypeStatement(value.Type().Elem())).Op("=").Add(g.getValueStatement(value.Elem())),
+			jen.Return(jen.Op("&").Id("v")),
+		).Call()
 	case reflect.Interface:
 		if value.IsNil() {
 			return jen.Nil()
 		}
+		// Keep the dynamic type of values that would otherwise be untyped
+		// constants defaulting to float64 or complex128
+		switch elem := value.Elem(); {
+		case elem.Type() == reflect.TypeFor[float32]() && nonConstantFloat(elem.Float()) == nil:
+			return jen.Float32().Call(g.getValueStatement(elem))
+		case elem.Type() == reflect.TypeFor[complex64]():
+			return jen.Complex64().Call(g.getValueStatement(elem))
+		}
 		return g.getValueStatement(value.Elem())
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		// Only nil values have a literal
+		if !value.IsNil() {
+			g.addGenError(UnsupportedValueError{Record: g.currentRecord, Type: value.Type().String()})
+		}
+		return jen.Nil()
 	default:
 		// For complex cases, fallback to string representation
 		return jen.Lit(fmt.Sprintf("%v", value.Interface()))
 	}
 }
 
+// isCompositeLiteral reports whether value is generated as a composite
+// literal, whose address can be taken with &
+func (g *Generator) isCompositeLiteral(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Map:
+		return true
+	case reflect.Struct:
+		if value.Type() == timeType {
+			return false
+		}
+		_, constructed := g.PackageConstructors[value.Type().PkgPath()]
+		return !constructed || !g.isForeignStruct(value.Type())
+	}
+	return false
+}
+
 // getMapStatement generates code for a map
 func (g *Generator) getMapStatement(mapValue reflect.Value) *jen.Statement {
 	// Return empty map if there are no entries
@@ -123,15 +206,14 @@ func (g *Generator) getMapStatement(mapValue reflect.Value) *jen.Statement {
 	).Add(
 		g.getTypeStatement(mapValue.Type().Elem()),
 	).ValuesFunc(func(group *jen.Group) {
-		var (
-			dict = jen.Dict{}
-			key  reflect.Value
-		)
-
-		// Add all key-value pairs to the Dict
-		for _, key = range mapValue.MapKeys() {
-			var stmt = g.getValueStatement(mapValue.MapIndex(key))
-			dict[g.getValueStatement(key)] = stmt
+		dict := jen.Dict{}
+
+		// Add all key-value pairs to the Dict, iterating so that NaN keys,
+		// which can't be looked up, are included
+		for iter := mapValue.MapRange(); iter.Next(); {
+			leave := g.enterPath(fmt.Sprintf("[%#v]", iter.Key()))
+			dict[g.getValueStatement(iter.Key())] = g.getValueStatement(iter.Value())
+			leave()
 		}
 
 		// Add dict to group
@@ -141,12 +223,44 @@ func (g *Generator) getMapStatement(mapValue reflect.Value) *jen.Statement {
 
 // generateStructValues adds values for a struct to a Dict
 func (g *Generator) generateStructValues(group *jen.Group, structValue reflect.Value) {
+	defer g.enterRecord(structValue)()
 	if structValue.Kind() == reflect.Pointer {
 		structValue = structValue.Elem()
 	}
 
 	structType := structValue.Type()
 
+	// Only the record itself, not its nested structs, receives a synthetic ID
+	syntheticID := g.syntheticID
+	g.syntheticID = ""
+	idFieldName := ""
+	if syntheticID != "" {
+		idFieldName = g.idFieldName(structValue)
+	}
+
+	// Likewise for the attribution read from a sidecar file
+	attribution := g.attribution
+	g.attribution = reflect.Value{}
+	attributionField := ""
+	if attribution.IsValid() {
+		attributionField = g.Attributions.withDefaults().Field
+	}
+
+	// Source fields of structgen references mapped to their target fields, so
+	// their keys can be emitted as constants
+	var refKeyTargets map[string]reflect.StructField
+	if g.ConstantRefs {
+		refKeyTargets = make(map[string]reflect.StructField)
+		for i := range structType.NumField() {
+			fieldType := structType.Field(i)
+			if tagValue := fieldType.Tag.Get("structgen"); tagValue != "" {
+				if tag, err := parseStructgenTag(fieldType, tagValue); err == nil {
+					refKeyTargets[tag.Source] = fieldType
+				}
+			}
+		}
+	}
+
 	dict := jen.Dict{}
 
 	// Track fields that need to be processed in a second pass (with structgen tag)
@@ -164,8 +278,8 @@ func (g *Generator) generateStructValues(group *jen.Group, structValue reflect.V
 			fieldType = structType.Field(i)
 		)
 
-		// Skip unexported fields
-		if !fieldType.IsExported() {
+		// Skip unexported fields and fields left out of the view being generated
+		if !fieldType.IsExported() || !g.isViewField(structType, fieldType) {
 			continue
 		}
 
@@ -182,16 +296,17 @@ func (g *Generator) generateStructValues(group *jen.Group, structValue reflect.V
 			continue
 		}
 
+		leave := g.enterPath("." + fieldType.Name)
+
 		// Handle embedded fields specially in export mode
-		isExportMode := strings.Contains(g.OutputFile, "/")
-		if fieldType.Anonymous && isExportMode {
+		if fieldType.Anonymous && g.ExportMode {
 			// For embedded fields in export mode, check if it comes from another package
 			embeddedType := fieldType.Type
 			pkgPath := embeddedType.PkgPath()
 
 			if pkgPath != "" && pkgPath != "main" && pkgPath != g.PackageName {
 				// Reference the embedded type from its original package but keep its field values
-				dict[jen.Id(fieldType.Name)] = jen.Qual(pkgPath, embeddedType.Name()).ValuesFunc(func(embGroup *jen.Group) {
+				dict[jen.Id(fieldType.Name)] = g.qual(pkgPath, embeddedType.Name()).ValuesFunc(func(embGroup *jen.Group) {
 					// Generate inner struct values while preserving field data
 					innerDict := jen.Dict{}
 
@@ -225,10 +340,27 @@ func (g *Generator) generateStructValues(group *jen.Group, structValue reflect.V
 				// Use regular reference for embedded fields from same package
 				dict[jen.Id(fieldType.Name)] = g.getValueStatement(field)
 			}
+		} else if g.isEncryptedField(fieldType) {
+			// Encrypted field
+			dict[jen.Id(fieldType.Name)] = jen.Lit(g.encryptString(field.String()))
+		} else if hash, ok := g.assetHashValue(structValue, fieldType); ok {
+			// Content hash or fingerprinted path of an asset
+			dict[jen.Id(fieldType.Name)] = jen.Lit(hash)
+		} else if fieldType.Name == attributionField {
+			// Attribution read from a sidecar file
+			dict[jen.Id(fieldType.Name)] = g.getValueStatement(attribution)
+		} else if fieldType.Name == idFieldName {
+			// Synthetic ID of a record without one
+			dict[jen.Id(fieldType.Name)] = jen.Lit(syntheticID)
+		} else if keys := g.refKeyStatement(field, refKeyTargets[fieldType.Name]); keys != nil {
+			// Reference keys emitted as the constants of the referenced records
+			dict[jen.Id(fieldType.Name)] = keys
 		} else {
 			// Regular field
 			dict[jen.Id(fieldType.Name)] = g.getValueStatement(field)
 		}
+
+		leave()
 	}
 
 	// Second pass: process fields with structgen tag
@@ -247,23 +379,40 @@ func (g *Generator) generateStructValues(group *jen.Group, structValue reflect.V
 //
 // The structgen tag enables automatic population of struct fields from reference datasets.
 // It takes the source field name as a value, which should contain identifiers (strings or string slices)
-// that can be used to look up matching structs in the reference datasets.
+// that can be used to look up matching structs in the reference datasets. Struct references may also
+// use boolean or numeric identifiers (e.g. "AuthorID int"), matched against identifier fields of any
+// of these kinds by value.
 //
 // Supported reference patterns:
 //   - String to Struct: A string field (e.g., "AuthorID") referencing a single struct or struct pointer (*T)
 //   - String Slice to Struct Slice: A slice of strings (e.g., "TagSlugs") referencing a slice of structs ([]T) or struct pointers ([]*T)
+//   - String Slice to Struct Map: A slice of strings (e.g., "TagSlugs") referencing a map of structs (map[string]T) or
+//     struct pointers (map[string]*T) keyed by the identifiers
+//   - String or String Slice to Interface: A string or slice of strings (e.g., "ItemIDs") referencing an interface ([]ContentItem),
+//     resolved against every reference dataset implementing it
+//
+// The source field name may be followed by modifiers (see structgenTag), e.g.
+// `structgen:"TagSlugs,ptr,match=Slug,strict"`. Invalid tags and unresolved
+// strict references are collected and returned by Generate.
 //
 // Parameters:
 //   - structValue: The struct instance being processed
-//   - srcFieldName: The name of the source field (from the tag value)
+//   - tagValue: The value of the structgen tag
 //   - targetField: The field to populate with references
 func (g *Generator) generateStructGenField(
 	structValue reflect.Value,
-	srcFieldName string,
+	tagValue string,
 	targetField reflect.StructField,
 ) *jen.Statement {
 	structType := structValue.Type()
 
+	tag, err := parseStructgenTag(targetField, tagValue)
+	if err != nil {
+		g.addGenError(err)
+		return nil
+	}
+	srcFieldName := tag.Source
+
 	// Find the source field
 	srcField, found := structType.FieldByName(srcFieldName)
 	if !found {
@@ -285,103 +434,169 @@ func (g *Generator) generateStructGenField(
 		((targetType.Elem().Kind() == reflect.Struct) ||
 			(targetType.Elem().Kind() == reflect.Pointer && targetType.Elem().Elem().Kind() == reflect.Struct)) &&
 		srcField.Type.Kind() == reflect.Slice &&
-		srcField.Type.Elem().Kind() == reflect.String {
+		isReferenceKeyKind(srcField.Type.Elem().Kind()) {
 
 		// Check if the slice is empty
 		if srcValue.Len() == 0 {
+			if tag.OmitEmpty {
+				return nil
+			}
 			// For empty source slices, return an empty slice of the appropriate type
 			return g.getEmptyReferenceSlice(targetType)
 		}
 
 		// We need to look up structs by ID or another field
-		return g.generateReferenceSlice(srcValue, targetType)
+		return g.generateReferenceSlice(srcValue, targetType, tag)
+	}
+
+	// Check for map of structs or struct pointers keyed by the identifiers of
+	// a slice of keys of the same kind
+	if targetType.Kind() == reflect.Map &&
+		((targetType.Elem().Kind() == reflect.Struct) ||
+			(targetType.Elem().Kind() == reflect.Pointer && targetType.Elem().Elem().Kind() == reflect.Struct)) &&
+		srcField.Type.Kind() == reflect.Slice &&
+		isReferenceKeyKind(srcField.Type.Elem().Kind()) &&
+		targetType.Key().Kind() == srcField.Type.Elem().Kind() {
+		if srcValue.Len() == 0 {
+			if tag.OmitEmpty {
+				return nil
+			}
+			return g.getTypeStatement(targetType).Values()
+		}
+		return g.generateReferenceMap(srcValue, targetType, tag)
 	}
 
 	// Check for single struct or struct pointer referencing a string
 	if (targetType.Kind() == reflect.Struct ||
 		(targetType.Kind() == reflect.Pointer && targetType.Elem().Kind() == reflect.Struct)) &&
-		srcField.Type.Kind() == reflect.String {
+		isReferenceKeyKind(srcField.Type.Kind()) {
 
-		// Check if the source string is empty
-		if srcValue.String() == "" {
+		// Check if the source key is empty, or zero for non-string keys
+		if srcValue.IsZero() {
+			if tag.OmitEmpty {
+				return nil
+			}
 			// For empty source string, return nil or empty struct
 			return g.getEmptyReference(targetType)
 		}
 
 		// We need to look up one struct by ID or another field
-		return g.generateReferenceSingle(srcValue, targetType)
+		return g.generateReferenceSingle(srcValue, targetType, tag)
+	}
+
+	// Check for interfaces or interface slices resolved against every
+	// reference dataset implementing the interface
+	if targetType.Kind() == reflect.Slice &&
+		targetType.Elem().Kind() == reflect.Interface &&
+		srcField.Type.Kind() == reflect.Slice &&
+		srcField.Type.Elem().Kind() == reflect.String {
+		if srcValue.Len() == 0 {
+			if tag.OmitEmpty {
+				return nil
+			}
+			return g.getTypeStatement(targetType).Values()
+		}
+		return g.generateInterfaceSlice(srcValue, targetType, tag)
+	}
+	if targetType.Kind() == reflect.Interface && srcField.Type.Kind() == reflect.String {
+		if srcValue.String() == "" {
+			if tag.OmitEmpty {
+				return nil
+			}
+			return jen.Nil()
+		}
+		if tag.TypeField != "" {
+			return g.generateDiscriminatedReference(structValue, srcValue, targetType, tag, tagValue)
+		}
+		return g.generateInterfaceSingle(srcValue, targetType, tag)
 	}
 
 	// Unsupported reference type
 	return nil
 }
 
-// getEmptyReferenceSlice returns an empty slice statement for a given target type
-func (g *Generator) getEmptyReferenceSlice(targetType reflect.Type) *jen.Statement {
-	// Determine if we're dealing with a pointer slice ([]*T) or struct slice ([]T)
-	isPointerSlice := targetType.Elem().Kind() == reflect.Pointer
-
-	// Get the target struct type name
-	var structTypeName string
-	if isPointerSlice {
-		structTypeName = targetType.Elem().Elem().Name()
-	} else {
-		structTypeName = targetType.Elem().Name()
+// refKeyStatement returns the keys held by field, the source of the structgen
+// reference target, as the constants of the referenced records. Keys without
+// a constant are kept as literals. It returns nil when target is the zero
+// StructField, the reference dataset is missing or no key has a constant.
+func (g *Generator) refKeyStatement(field reflect.Value, target reflect.StructField) *jen.Statement {
+	if target.Type == nil {
+		return nil
 	}
-
-	// Check if we need to use fully qualified type references
-	isExportMode := strings.Contains(g.OutputFile, "/")
-	refType := targetType.Elem()
-	if isPointerSlice {
+	tag, err := parseStructgenTag(target, target.Tag.Get("structgen"))
+	if err != nil {
+		return nil
+	}
+	refType := target.Type
+	if refType.Kind() == reflect.Slice || refType.Kind() == reflect.Map {
+		refType = refType.Elem()
+	}
+	if refType.Kind() == reflect.Pointer {
 		refType = refType.Elem()
 	}
-	pkgPath := refType.PkgPath()
-	useQualified := isExportMode && pkgPath != "" && pkgPath != "main" && pkgPath != g.PackageName
+	refDataObj, ok := g.refData(g.refKey(refType))
+	if !ok {
+		return nil
+	}
+	refData := reflect.ValueOf(refDataObj)
+	if refData.Kind() != reflect.Slice && refData.Kind() != reflect.Array {
+		return nil
+	}
 
-	// Return an empty slice of the appropriate type
-	if isPointerSlice {
-		if useQualified {
-			return jen.Index().Add(jen.Op("*").Qual(pkgPath, structTypeName)).Values()
+	switch {
+	case field.Kind() == reflect.String:
+		if name, ok := g.refKeyConstant(refData, field.String(), tag); ok {
+			return jen.Id(name)
+		}
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		keys := make([]jen.Code, 0, field.Len())
+		hasConstant := false
+		for i := range field.Len() {
+			key := field.Index(i).String()
+			if name, ok := g.refKeyConstant(refData, key, tag); ok {
+				keys = append(keys, jen.Id(name))
+				hasConstant = true
+			} else {
+				keys = append(keys, jen.Lit(key))
+			}
+		}
+		if hasConstant {
+			return g.getTypeStatement(field.Type()).Values(keys...)
 		}
-		return jen.Index().Add(jen.Op("*").Id(structTypeName)).Values()
-	}
-	if useQualified {
-		return jen.Index().Add(jen.Qual(pkgPath, structTypeName)).Values()
 	}
-	return jen.Index().Add(jen.Id(structTypeName)).Values()
+	return nil
 }
 
-// getEmptyReference returns nil or an empty struct for a given target type
-func (g *Generator) getEmptyReference(targetType reflect.Type) *jen.Statement {
-	// Determine if we're dealing with a pointer (*T) or struct (T)
-	isPointer := targetType.Kind() == reflect.Pointer
-
-	// Get the target struct type name
-	var structTypeName string
-	var structType reflect.Type
-	if isPointer {
-		structTypeName = targetType.Elem().Name()
-		structType = targetType.Elem()
-	} else {
-		structTypeName = targetType.Name()
-		structType = targetType
+// refKeyConstant returns the name of the constant generated for the value of
+// the field key matched in the reference dataset. Keys only matching after
+// normalization by RefMatchNormalizer have no constant.
+func (g *Generator) refKeyConstant(refData reflect.Value, key string, tag structgenTag) (string, bool) {
+	refStruct, matchField, found := g.findReference(refData, key, tag)
+	if !found || refStruct.FieldByName(matchField).Kind() != reflect.String || refStruct.FieldByName(matchField).String() != key {
+		return "", false
+	}
+	name := g.prefixedIdentifier(g.refPrefix(refStruct.Type().Name()), refStruct)
+	switch {
+	case matchField == g.idFieldName(refStruct):
+		return g.safeName(name + "ID"), true
+	case g.IdentifierConsts && slices.Contains(g.identifierFields(refStruct.Type()), matchField):
+		return g.safeName(name + matchField), true
 	}
+	return "", false
+}
 
-	// Check if we need to use fully qualified type references
-	isExportMode := strings.Contains(g.OutputFile, "/")
-	pkgPath := structType.PkgPath()
-	useQualified := isExportMode && pkgPath != "" && pkgPath != "main" && pkgPath != g.PackageName
+// getEmptyReferenceSlice returns an empty slice statement for a given target type
+func (g *Generator) getEmptyReferenceSlice(targetType reflect.Type) *jen.Statement {
+	return g.getTypeStatement(targetType).Values()
+}
 
+// getEmptyReference returns nil or an empty struct for a given target type
+func (g *Generator) getEmptyReference(targetType reflect.Type) *jen.Statement {
 	// For pointer types, return nil
-	if isPointer {
+	if targetType.Kind() == reflect.Pointer {
 		return jen.Nil()
 	}
-
-	// For struct types, return an empty struct
-	if useQualified {
-		return jen.Qual(pkgPath, structTypeName).Values()
-	}
-	return jen.Id(structTypeName).Values()
+	return g.getTypeStatement(targetType).Values()
 }
 
 // generateReferenceSlice generates a slice of referenced structs for string slice to struct slice references
@@ -392,130 +607,119 @@ func (g *Generator) getEmptyReference(targetType reflect.Type) *jen.Statement {
 // Parameters:
 //   - srcValue: The source field value (slice of strings)
 //   - targetType: The target field type (slice of structs or struct pointers)
-func (g *Generator) generateReferenceSlice(srcValue reflect.Value, targetType reflect.Type) *jen.Statement {
+//   - tag: The parsed structgen tag of the target field
+func (g *Generator) generateReferenceSlice(srcValue reflect.Value, targetType reflect.Type, tag structgenTag) *jen.Statement {
 	// Determine if we're dealing with a pointer slice ([]*T) or struct slice ([]T)
 	isPointerSlice := targetType.Elem().Kind() == reflect.Pointer
 
-	// Get the target struct type name
-	var structTypeName string
-	if isPointerSlice {
-		structTypeName = targetType.Elem().Elem().Name()
-	} else {
-		structTypeName = targetType.Elem().Name()
-	}
-
-	// Check if we need to use fully qualified type references
-	isExportMode := strings.Contains(g.OutputFile, "/")
+	// Get the key of the target struct type's reference dataset
 	refType := targetType.Elem()
 	if isPointerSlice {
 		refType = refType.Elem()
 	}
-	pkgPath := refType.PkgPath()
-	useQualified := isExportMode && pkgPath != "" && pkgPath != "main" && pkgPath != g.PackageName
+	refKey := g.refKey(refType)
 
 	// Check if we have this reference type
-	refDataObj, hasRef := g.Refs[structTypeName]
+	refDataObj, hasRef := g.refData(refKey)
 	if !hasRef {
 		// We don't have this reference data
-		if isPointerSlice {
-			if useQualified {
-				return jen.Index().Add(jen.Op("*").Qual(pkgPath, structTypeName)).Values()
+		if tag.Strict {
+			for i := range srcValue.Len() {
+				g.addGenError(UnresolvedReferenceError{Record: g.currentRecord, Field: tag.Field, Key: referenceKeyText(srcValue.Index(i))})
 			}
-			return jen.Index().Add(jen.Op("*").Id(structTypeName)).Values()
-		}
-		if useQualified {
-			return jen.Index().Add(jen.Qual(pkgPath, structTypeName)).Values()
 		}
-		return jen.Index().Add(jen.Id(structTypeName)).Values()
+		return g.getEmptyReferenceSlice(targetType)
 	}
 
 	// Convert to reflect.Value
 	refData := reflect.ValueOf(refDataObj)
 	if refData.Kind() != reflect.Slice && refData.Kind() != reflect.Array {
 		// Reference isn't a slice/array
-		if isPointerSlice {
-			if useQualified {
-				return jen.Index().Add(jen.Op("*").Qual(pkgPath, structTypeName)).Values()
-			}
-			return jen.Index().Add(jen.Op("*").Id(structTypeName)).Values()
-		}
-		if useQualified {
-			return jen.Index().Add(jen.Qual(pkgPath, structTypeName)).Values()
-		}
-		return jen.Index().Add(jen.Id(structTypeName)).Values()
-	}
-
-	// Create a statement for the appropriate slice type
-	var sliceStmt *jen.Statement
-
-	// Use the qualified type if needed
-	if useQualified {
-		if isPointerSlice {
-			// For []*pkg.T
-			sliceStmt = jen.Index().Add(jen.Op("*").Qual(pkgPath, structTypeName))
-		} else {
-			// For []pkg.T
-			sliceStmt = jen.Index().Add(jen.Qual(pkgPath, structTypeName))
-		}
-	} else {
-		// Regular non-exported mode
-		if isPointerSlice {
-			// For []*T
-			sliceStmt = jen.Index().Add(jen.Op("*").Id(structTypeName))
-		} else {
-			// For []T
-			sliceStmt = jen.Index().Add(jen.Id(structTypeName))
-		}
+		return g.getEmptyReferenceSlice(targetType)
 	}
 
 	// Now create a slice with all matching references
-	return sliceStmt.ValuesFunc(func(group *jen.Group) {
+	return g.getTypeStatement(targetType).ValuesFunc(func(group *jen.Group) {
 		// For each source ID
 		for i := range srcValue.Len() {
-			idValue := srcValue.Index(i).String()
-			found := false
+			idValue := referenceKeyText(srcValue.Index(i))
 
 			// Try to find a matching reference struct
-			for j := range refData.Len() {
-				refStruct := refData.Index(j)
-
-				// Handle pointer to struct case
-				if refStruct.Kind() == reflect.Pointer {
-					refStruct = refStruct.Elem()
+			refStruct, _, found := g.findReference(refData, idValue, tag)
+			if found {
+				// Get a name for the referenced variable
+				refVarName := g.refVarName(refKey, refStruct)
+				if g.prunedReference(refVarName) {
+					continue
 				}
 
-				// Try each possible identifier field
-				for _, idField := range g.IdentifierFields {
-					refIDField := refStruct.FieldByName(idField)
-
-					if refIDField.IsValid() &&
-						refIDField.Kind() == reflect.String &&
-						refIDField.String() == idValue {
-
-						// Found a matching reference
-						// Get a name for the referenced variable
-						identValue := g.getStructIdentifier(refStruct)
-						refVarName := structTypeName + slugToIdentifier(identValue)
-
-						// Use a direct reference to the variable (e.g., TagGoProgramming)
-						// For pointer slices, add the & operator
-						if isPointerSlice {
-							group.Add(jen.Op("&").Id(refVarName))
-						} else {
-							group.Add(jen.Id(refVarName))
-						}
-						found = true
-						break
-					}
-				}
-				if found {
-					break
+				// Use a direct reference to the variable (e.g., TagGoProgramming)
+				// For pointer slices, add the & operator
+				if isPointerSlice {
+					group.Add(jen.Op("&").Id(refVarName))
+				} else {
+					group.Add(jen.Id(refVarName))
 				}
+			} else if tag.Strict {
+				g.addGenError(UnresolvedReferenceError{Record: g.currentRecord, Field: tag.Field, Key: idValue})
 			}
 		}
 	})
 }
 
+// generateReferenceMap generates a map of referenced structs keyed by the
+// identifiers of a string slice, e.g. map[string]*Tag{"go": &TagGo}.
+// Duplicate identifiers are written once.
+func (g *Generator) generateReferenceMap(srcValue reflect.Value, targetType reflect.Type, tag structgenTag) *jen.Statement {
+	isPointerMap := targetType.Elem().Kind() == reflect.Pointer
+	refType := targetType.Elem()
+	if isPointerMap {
+		refType = refType.Elem()
+	}
+	refKey := g.refKey(refType)
+
+	refDataObj, hasRef := g.refData(refKey)
+	refData := reflect.ValueOf(refDataObj)
+	if !hasRef || (refData.Kind() != reflect.Slice && refData.Kind() != reflect.Array) {
+		if tag.Strict {
+			for i := range srcValue.Len() {
+				g.addGenError(UnresolvedReferenceError{Record: g.currentRecord, Field: tag.Field, Key: referenceKeyText(srcValue.Index(i))})
+			}
+		}
+		return g.getTypeStatement(targetType).Values()
+	}
+
+	dict := jen.Dict{}
+	seen := make(map[string]bool)
+	for i := range srcValue.Len() {
+		key := srcValue.Index(i)
+		idValue := referenceKeyText(key)
+		if seen[idValue] {
+			continue
+		}
+		seen[idValue] = true
+
+		refStruct, _, found := g.findReference(refData, idValue, tag)
+		if !found {
+			if tag.Strict {
+				g.addGenError(UnresolvedReferenceError{Record: g.currentRecord, Field: tag.Field, Key: idValue})
+			}
+			continue
+		}
+		refVarName := g.refVarName(refKey, refStruct)
+		if g.prunedReference(refVarName) {
+			continue
+		}
+		keyStatement := g.getValueStatement(key.Convert(targetType.Key()))
+		if isPointerMap {
+			dict[keyStatement] = jen.Op("&").Id(refVarName)
+		} else {
+			dict[keyStatement] = jen.Id(refVarName)
+		}
+	}
+	return g.getTypeStatement(targetType).Values(dict)
+}
+
 // generateReferenceSingle generates a single referenced struct for string to struct references
 //
 // This method handles the case where a field contains a string (e.g., "author-1")
@@ -524,62 +728,83 @@ func (g *Generator) generateReferenceSlice(srcValue reflect.Value, targetType re
 // Parameters:
 //   - srcValue: The source field value (string)
 //   - targetType: The target field type (struct or pointer to struct)
-func (g *Generator) generateReferenceSingle(srcValue reflect.Value, targetType reflect.Type) *jen.Statement {
+//   - tag: The parsed structgen tag of the target field
+func (g *Generator) generateReferenceSingle(srcValue reflect.Value, targetType reflect.Type, tag structgenTag) *jen.Statement {
 	// Determine if we're dealing with a pointer (*T) or struct (T)
 	isPointer := targetType.Kind() == reflect.Pointer
 
-	// Get the target struct type name
-	var structTypeName string
-	var structType reflect.Type
+	// Get the key of the target struct type's reference dataset
+	structType := targetType
 	if isPointer {
-		structTypeName = targetType.Elem().Name()
 		structType = targetType.Elem()
-	} else {
-		structTypeName = targetType.Name()
-		structType = targetType
 	}
+	refKey := g.refKey(structType)
 
-	// Check if we need to use fully qualified type references
-	isExportMode := strings.Contains(g.OutputFile, "/")
-	pkgPath := structType.PkgPath()
-	useQualified := isExportMode && pkgPath != "" && pkgPath != "main" && pkgPath != g.PackageName
+	// Unresolved references are an empty struct, or a pointer to one
+	empty := func() *jen.Statement {
+		if isPointer {
+			return jen.Op("&").Add(g.getTypeStatement(structType)).Values()
+		}
+		return g.getTypeStatement(structType).Values()
+	}
 
 	// Check if we have this reference type
-	refDataObj, hasRef := g.Refs[structTypeName]
+	refDataObj, hasRef := g.refData(refKey)
 	if !hasRef {
 		// We don't have this reference data
-		if isPointer {
-			if useQualified {
-				return jen.Op("&").Qual(pkgPath, structTypeName).Values()
-			}
-			return jen.Op("&").Id(structTypeName).Values()
-		}
-		if useQualified {
-			return jen.Qual(pkgPath, structTypeName).Values()
+		if tag.Strict {
+			g.addGenError(UnresolvedReferenceError{Record: g.currentRecord, Field: tag.Field, Key: referenceKeyText(srcValue)})
 		}
-		return jen.Id(structTypeName).Values()
+		return empty()
 	}
 
 	// Convert to reflect.Value
 	refData := reflect.ValueOf(refDataObj)
 	if refData.Kind() != reflect.Slice && refData.Kind() != reflect.Array {
 		// Reference isn't a slice/array
-		if isPointer {
-			if useQualified {
-				return jen.Op("&").Qual(pkgPath, structTypeName).Values()
-			}
-			return jen.Op("&").Id(structTypeName).Values()
-		}
-		if useQualified {
-			return jen.Qual(pkgPath, structTypeName).Values()
-		}
-		return jen.Id(structTypeName).Values()
+		return empty()
 	}
 
 	// Get ID value from source
-	idValue := srcValue.String()
+	idValue := referenceKeyText(srcValue)
 
 	// Try to find a matching reference struct
+	if refStruct, _, found := g.findReference(refData, idValue, tag); found {
+		// Found match - get a name for the referenced variable
+		refVarName := g.refVarName(refKey, refStruct)
+		if g.prunedReference(refVarName) {
+			return empty()
+		}
+
+		// For pointer types, just return a pointer to the existing variable
+		if isPointer {
+			return jen.Op("&").Id(refVarName)
+		}
+		// For non-pointer types, return the variable directly
+		return jen.Id(refVarName)
+	}
+
+	// No match found
+	if tag.Strict {
+		g.addGenError(UnresolvedReferenceError{Record: g.currentRecord, Field: tag.Field, Key: idValue})
+	}
+	return empty()
+}
+
+// findReference returns the first struct in the reference dataset with one of
+// the match fields of the tag equal to key, along with the name of that field
+func (g *Generator) findReference(refData reflect.Value, key string, tag structgenTag) (reflect.Value, string, bool) {
+	if index, ok := g.indexRefs(refData, tag); ok {
+		if g.RefMatchNormalizer != nil {
+			key = g.RefMatchNormalizer(key)
+		}
+		entry, found := index[key]
+		if !found {
+			return reflect.Value{}, "", false
+		}
+		return reflect.Indirect(refData.Index(entry.index)), entry.field, true
+	}
+
 	for j := range refData.Len() {
 		refStruct := refData.Index(j)
 
@@ -589,36 +814,61 @@ func (g *Generator) generateReferenceSingle(srcValue reflect.Value, targetType r
 		}
 
 		// Try each possible identifier field
-		for _, idField := range g.IdentifierFields {
+		for _, idField := range g.matchFields(tag, refStruct.Type()) {
 			refIDField := refStruct.FieldByName(idField)
 
 			if refIDField.IsValid() &&
-				refIDField.Kind() == reflect.String &&
-				refIDField.String() == idValue {
-
-				// Found match - get a name for the referenced variable
-				identValue := g.getStructIdentifier(refStruct)
-				refVarName := structTypeName + slugToIdentifier(identValue)
-
-				// For pointer types, just return a pointer to the existing variable
-				if isPointer {
-					return jen.Op("&").Id(refVarName)
-				}
-				// For non-pointer types, return the variable directly
-				return jen.Id(refVarName)
+				isReferenceKeyKind(refIDField.Kind()) &&
+				g.refKeysMatch(referenceKeyText(refIDField), key) {
+				return refStruct, idField, true
 			}
 		}
 	}
+	return reflect.Value{}, "", false
+}
 
-	// No match found
-	if isPointer {
-		if useQualified {
-			return jen.Op("&").Qual(pkgPath, structTypeName).Values()
-		}
-		return jen.Op("&").Id(structTypeName).Values()
+// isReferenceKeyKind reports whether values of the kind can be reference keys
+func isReferenceKeyKind(kind reflect.Kind) bool {
+	return kind == reflect.String || kind == reflect.Bool || isNumericKind(kind)
+}
+
+// referenceKeyText returns the text a reference key or identifier is
+// compared by, so that e.g. an int key 7 matches an int64 or "7" identifier
+func referenceKeyText(value reflect.Value) string {
+	switch {
+	case value.Kind() == reflect.String:
+		return value.String()
+	case value.Kind() == reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case value.CanInt():
+		return strconv.FormatInt(value.Int(), 10)
+	case value.CanUint():
+		return strconv.FormatUint(value.Uint(), 10)
+	case value.CanFloat():
+		return strconv.FormatFloat(value.Float(), 'g', -1, 64)
+	case value.CanComplex():
+		return strconv.FormatComplex(value.Complex(), 'g', -1, 128)
 	}
-	if useQualified {
-		return jen.Qual(pkgPath, structTypeName).Values()
+	return ""
+}
+
+// refKeysMatch reports whether a reference key matches an identifier of a
+// reference struct after applying the configured RefMatchNormalizer
+func (g *Generator) refKeysMatch(refKey, key string) bool {
+	if g.RefMatchNormalizer == nil {
+		return refKey == key
 	}
-	return jen.Id(structTypeName).Values()
+	return g.RefMatchNormalizer(refKey) == g.RefMatchNormalizer(key)
+}
+
+// slugSeparators matches the runs of characters NormalizeSlug replaces with a
+// hyphen
+var slugSeparators = regexp.MustCompile("[^a-z0-9]+")
+
+// NormalizeSlug normalizes a string to a lowercase slug by replacing runs of
+// non-alphanumeric characters with a single hyphen.
+// It can be passed to WithRefMatchNormalizer so that "Go Programming",
+// "go_programming" and "go-programming" all match each other.
+func NormalizeSlug(s string) string {
+	return strings.Trim(slugSeparators.ReplaceAllString(strings.ToLower(s), "-"), "-")
 }
@@ -0,0 +1,304 @@
+package genstruct
+
+import (
+	"encoding/binary"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// FuzzCelsius is a defined numeric type rendered as a conversion
+type FuzzCelsius float64
+
+// FuzzCount is a defined integer type rendered as a conversion
+type FuzzCount uint16
+
+// FuzzInner is a nested struct of FuzzRecord
+type FuzzInner struct {
+	Name  string
+	Score float32
+	Flags []bool
+}
+
+// FuzzRecord holds a field of every kind of value the generator renders
+type FuzzRecord struct {
+	ID         string
+	Bool       bool
+	Int        int
+	Int8       int8
+	Int16      int16
+	Int32      int32
+	Int64      int64
+	Uint       uint
+	Uint8      uint8
+	Uint16     uint16
+	Uint32     uint32
+	Uint64     uint64
+	Uintptr    uintptr
+	Rune       rune
+	Float32    float32
+	Float64    float64
+	Complex64  complex64
+	Complex128 complex128
+	String     string
+	Bytes      []byte
+	Celsius    FuzzCelsius
+	Count      FuzzCount
+	Time       time.Time
+	Pointer    *int64
+	Array      [2]float64
+	Floats     []float64
+	ByFloat    map[float64]string
+	ByString   map[string]complex128
+	Nested     map[string][]rune
+	Inner      FuzzInner
+	InnerPtr   *FuzzInner
+	Any        any
+	Anys       []any
+	Func       func()
+	Chan       chan int
+}
+
+// TestIntegerLiterals tests that extreme integers render as exact decimal
+// literals converted to their type
+func TestIntegerLiterals(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{math.MaxInt64, "9223372036854775807"},
+		{math.MinInt, "-9223372036854775808"},
+		{int8(math.MinInt8), "int8(-128)"},
+		{int64(math.MinInt64), "int64(-9223372036854775808)"},
+		{uint64(math.MaxUint64), "uint64(18446744073709551615)"},
+		{uint32(math.MaxUint32), "uint32(4294967295)"},
+		{uintptr(1 << 40), "uintptr(1099511627776)"},
+		{FuzzCount(math.MaxUint16), "genstruct.FuzzCount(65535)"},
+	}
+
+	g := NewGenerator()
+	for _, tt := range tests {
+		got := fmt.Sprintf("%#v", g.getValueStatement(reflect.ValueOf(tt.value)))
+		if got != tt.want {
+			t.Errorf("getValueStatement(%T(%v)) = %q, want %q", tt.value, tt.value, got, tt.want)
+		}
+	}
+}
+
+// fuzzRecordSource declares the types of FuzzRecord in the package the
+// rendered records are type-checked in
+const fuzzRecordSource = `package fuzz
+
+import "time"
+
+type FuzzCelsius float64
+
+type FuzzCount uint16
+
+type FuzzInner struct {
+	Name  string
+	Score float32
+	Flags []bool
+}
+
+type FuzzRecord struct {
+	ID         string
+	Bool       bool
+	Int        int
+	Int8       int8
+	Int16      int16
+	Int32      int32
+	Int64      int64
+	Uint       uint
+	Uint8      uint8
+	Uint16     uint16
+	Uint32     uint32
+	Uint64     uint64
+	Uintptr    uintptr
+	Rune       rune
+	Float32    float32
+	Float64    float64
+	Complex64  complex64
+	Complex128 complex128
+	String     string
+	Bytes      []byte
+	Celsius    FuzzCelsius
+	Count      FuzzCount
+	Time       time.Time
+	Pointer    *int64
+	Array      [2]float64
+	Floats     []float64
+	ByFloat    map[float64]string
+	ByString   map[string]complex128
+	Nested     map[string][]rune
+	Inner      FuzzInner
+	InnerPtr   *FuzzInner
+	Any        any
+	Anys       []any
+	Func       func()
+	Chan       chan int
+}
+`
+
+// fuzzFiller fills values from fuzz input, reading zeros once it runs out
+type fuzzFiller struct {
+	data []byte
+}
+
+// next returns the next n bytes of input
+func (f *fuzzFiller) next(n int) []byte {
+	buf := make([]byte, n)
+	copied := copy(buf, f.data)
+	f.data = f.data[copied:]
+	return buf
+}
+
+// uint64 returns the next 8 bytes of input as an integer
+func (f *fuzzFiller) uint64() uint64 {
+	return binary.LittleEndian.Uint64(f.next(8))
+}
+
+// float returns the next 8 bytes of input as a float, picking the special
+// values NaN, infinities and negative zero more often than chance would
+func (f *fuzzFiller) float() float64 {
+	switch f.next(1)[0] % 8 {
+	case 0:
+		return math.NaN()
+	case 1:
+		return math.Inf(1)
+	case 2:
+		return math.Inf(-1)
+	case 3:
+		return math.Copysign(0, -1)
+	default:
+		return math.Float64frombits(f.uint64())
+	}
+}
+
+// fill sets value, which must be settable, from the input
+func (f *fuzzFiller) fill(value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Bool:
+		value.SetBool(f.next(1)[0]%2 == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value.SetInt(int64(f.uint64()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value.SetUint(f.uint64())
+	case reflect.Float32, reflect.Float64:
+		value.SetFloat(f.float())
+	case reflect.Complex64, reflect.Complex128:
+		value.SetComplex(complex(f.float(), f.float()))
+	case reflect.String:
+		value.SetString(string(f.next(int(f.next(1)[0] % 16))))
+	case reflect.Pointer:
+		if f.next(1)[0]%2 == 0 {
+			return
+		}
+		value.Set(reflect.New(value.Type().Elem()))
+		f.fill(value.Elem())
+	case reflect.Array:
+		for i := range value.Len() {
+			f.fill(value.Index(i))
+		}
+	case reflect.Slice:
+		n := int(f.next(1)[0] % 4)
+		value.Set(reflect.MakeSlice(value.Type(), n, n))
+		for i := range n {
+			f.fill(value.Index(i))
+		}
+	case reflect.Map:
+		value.Set(reflect.MakeMap(value.Type()))
+		for range f.next(1)[0] % 4 {
+			key := reflect.New(value.Type().Key()).Elem()
+			elem := reflect.New(value.Type().Elem()).Elem()
+			f.fill(key)
+			f.fill(elem)
+			value.SetMapIndex(key, elem)
+		}
+	case reflect.Struct:
+		if value.Type() == timeType {
+			value.Set(reflect.ValueOf(time.Unix(int64(f.uint64()%(1<<40)), int64(f.uint64()%1e9)).UTC()))
+			return
+		}
+		for i := range value.NumField() {
+			f.fill(value.Field(i))
+		}
+	case reflect.Interface:
+		candidates := []reflect.Type{
+			reflect.TypeFor[int](),
+			reflect.TypeFor[int8](),
+			reflect.TypeFor[uint64](),
+			reflect.TypeFor[uintptr](),
+			reflect.TypeFor[float32](),
+			reflect.TypeFor[float64](),
+			reflect.TypeFor[complex64](),
+			reflect.TypeFor[string](),
+			reflect.TypeFor[bool](),
+			reflect.TypeFor[[]string](),
+			reflect.TypeFor[FuzzCelsius](),
+		}
+		choice := int(f.next(1)[0]) % (len(candidates) + 1)
+		if choice == len(candidates) {
+			return
+		}
+		elem := reflect.New(candidates[choice]).Elem()
+		f.fill(elem)
+		value.Set(elem)
+	case reflect.Func, reflect.Chan:
+		// Only nil values can be rendered
+	}
+}
+
+// FuzzValueStatements tests that records filled with arbitrary values render
+// to code that parses and type-checks
+func FuzzValueStatements(f *testing.F) {
+	// Negative corpus of values known to be hard to render
+	f.Add([]byte{})
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f})
+	f.Add([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0x80, 0, 1, 2, 3})
+	for special := range byte(4) {
+		seed := make([]byte, 512)
+		for i := range seed {
+			seed[i] = special
+		}
+		f.Add(seed)
+	}
+	f.Add([]byte("\xff\xfe\x00\xed\xa0\x80\"`\\\n\t \U0010ffff"))
+	f.Add([]byte{0x00, 0xd8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x03, 0x07})
+
+	importer := importer.Default()
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var record FuzzRecord
+		(&fuzzFiller{data: data}).fill(reflect.ValueOf(&record).Elem())
+		record.ID = "fuzz"
+
+		src, err := NewGenerator(
+			WithPackageName("fuzz"),
+			WithOutputFile("records.go"),
+		).Render([]FuzzRecord{record})
+		if err != nil {
+			t.Fatalf("Error rendering %#v: %v", record, err)
+		}
+
+		fset := token.NewFileSet()
+		generated, err := parser.ParseFile(fset, "records.go", src, 0)
+		if err != nil {
+			t.Fatalf("Rendered code does not parse: %v\n%s", err, src)
+		}
+		decls, err := parser.ParseFile(fset, "types.go", fuzzRecordSource, 0)
+		if err != nil {
+			t.Fatalf("Error parsing types: %v", err)
+		}
+		config := types.Config{Importer: importer}
+		if _, err := config.Check("fuzz", fset, []*ast.File{generated, decls}, nil); err != nil {
+			t.Fatalf("Rendered code does not type-check: %v\n%s", err, src)
+		}
+	})
+}
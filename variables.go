@@ -1,7 +1,6 @@
 package genstruct
 
 import (
-	"fmt"
 	"reflect"
 	"strings"
 
@@ -10,76 +9,92 @@ import (
 
 // generateVariables creates variables for each struct
 func (g *Generator) generateVariables(dataValue reflect.Value) {
-	// Generate a variable for each struct
-	for i := range dataValue.Len() {
-		elem := dataValue.Index(i)
+	// Generate a variable for each struct, in sections when partitioned
+	for _, section := range g.sections(dataValue) {
+		section.writeComment(g.File.Group)
+		for _, i := range section.indices {
+			elem := dataValue.Index(i)
 
-		// Determine the variable name using the identifier function
-		identValue := g.getStructIdentifier(elem)
-		varName := g.VarPrefix + slugToIdentifier(identValue)
+			// Skip records excluded by pruning
+			if g.isPruned(elem) {
+				continue
+			}
 
-		// Get the type to use (may be from another package)
-		var typeStmt *jen.Statement
+			// Determine the variable name using the identifier function
+			varName := g.varName(elem)
 
-		// Check if this is a struct from another package
-		var structType reflect.Type
-		if elem.Kind() == reflect.Struct {
-			structType = elem.Type()
-		} else if elem.Kind() == reflect.Pointer && elem.Elem().Kind() == reflect.Struct {
-			structType = elem.Elem().Type()
-		}
+			// Get the type to use (may be from another package)
+			typeStmt := g.elemTypeStatement(dataValue)
 
-		// If we have a struct type and it comes from a different package, use qualified name
-		if structType != nil {
-			pkgPath := structType.PkgPath()
-			// Infer ExportDataMode by checking if output file contains package path separator
-			isExportMode := strings.Contains(g.OutputFile, "/")
-			if isExportMode && pkgPath != "" && pkgPath != "main" && pkgPath != g.PackageName {
-				parts := strings.Split(g.TypeName, ".")
-				if len(parts) > 1 {
-					// If TypeName already has package qualifier (e.g., "pkg.Animal"), use it directly
-					typeStmt = jen.Id(g.TypeName)
-				} else {
-					// Use package qualification
-					typeStmt = jen.Qual(pkgPath, structType.Name())
+			// Records without an ID carry the same synthetic ID as their constant
+			if idFieldName := g.idFieldName(elem); idFieldName != "" {
+				idField := reflect.Indirect(elem).FieldByName(idFieldName)
+				if idField.Kind() == reflect.String && idField.String() == "" {
+					g.syntheticID = g.syntheticIDFor(g.sourceIndex(dataValue, i))
 				}
-			} else {
-				typeStmt = jen.Id(g.TypeName)
 			}
-		} else {
-			typeStmt = jen.Id(g.TypeName)
+
+			// Records with a sidecar attribution carry it in their literal
+			g.attribution = g.sidecarAttributions[varName]
+
+			// Create the variable with its value
+			g.currentRecord = varName
+			g.File.Var().Id(varName).Op("=").Add(typeStmt).ValuesFunc(func(group *jen.Group) {
+				g.generateStructValues(group, elem)
+			})
+			g.currentRecord = ""
+
+			if g.SymbolMap {
+				g.symbols = append(g.symbols, varName)
+			}
 		}
+	}
+}
 
-		// Create the variable with its value
-		g.File.Var().Id(varName).Op("=").Add(typeStmt).ValuesFunc(func(group *jen.Group) {
-			g.generateStructValues(group, elem)
-		})
+// sliceName returns the name of the slice holding all struct instances,
+// handling both regular and irregular plurals (e.g., AllAnimals, AllBoxes, AllCategories)
+func (g *Generator) sliceName() string {
+	if name, ok := g.SliceNames[g.TypeName]; ok {
+		return g.safeName(name)
 	}
+	return g.safeName("All" + g.plural(g.TypeName))
 }
 
-// generateSlice creates a slice containing all struct instances
-func (g *Generator) generateSlice(dataValue reflect.Value) {
-	// Determine the slice name - handle both regular and irregular plurals
-	var sliceName string
-	if g.TypeName[len(g.TypeName)-1] == 's' ||
-		g.TypeName[len(g.TypeName)-1] == 'x' ||
-		g.TypeName[len(g.TypeName)-1] == 'z' ||
-		strings.HasSuffix(g.TypeName, "sh") ||
-		strings.HasSuffix(g.TypeName, "ch") {
-		sliceName = fmt.Sprintf(
-			"All%ses",
-			g.TypeName,
-		)
-	} else if g.TypeName[len(g.TypeName)-1] == 'y' {
-		sliceName = fmt.Sprintf(
-			"All%sies",
-			g.TypeName[:len(g.TypeName)-1],
-		)
-	} else {
-		sliceName = fmt.Sprintf("All%ss", g.TypeName)
+// plural returns the plural of a type name as used in generated names, from
+// the slice name set with WithAllSliceName, the configured Pluralizer, or the
+// default rules
+func (g *Generator) plural(typeName string) string {
+	if name, ok := g.SliceNames[typeName]; ok {
+		return strings.TrimPrefix(name, "All")
+	}
+	if g.Pluralizer != nil {
+		return g.Pluralizer(typeName)
+	}
+	return pluralize(typeName)
+}
+
+// pluralize returns the plural of a type name (e.g., Animals, Boxes, Categories)
+func pluralize(name string) string {
+	if name[len(name)-1] == 's' ||
+		name[len(name)-1] == 'x' ||
+		name[len(name)-1] == 'z' ||
+		strings.HasSuffix(name, "sh") ||
+		strings.HasSuffix(name, "ch") {
+		return name + "es"
+	} else if name[len(name)-1] == 'y' {
+		return name[:len(name)-1] + "ies"
+	}
+	return name + "s"
+}
+
+// elemTypeStatement returns the type statement for the elements of the dataset,
+// qualified with its package when the type comes from a different package
+func (g *Generator) elemTypeStatement(dataValue reflect.Value) *jen.Statement {
+	// Views declare their own type in the view package
+	if g.viewType != nil {
+		return jen.Id(g.TypeName)
 	}
 
-	// Get the type to use (may be from another package)
 	var typeStmt *jen.Statement
 	var elemType reflect.Type
 
@@ -94,12 +109,16 @@ func (g *Generator) generateSlice(dataValue reflect.Value) {
 		}
 	}
 
+	// Types sharing their name with another dataset's are told apart by
+	// package, and instantiated generic types need their type arguments
+	if elemType != nil && (g.refKey(elemType) != elemType.Name() || strings.Contains(elemType.Name(), "[")) {
+		return g.getTypeStatement(elemType)
+	}
+
 	// If we have a struct type and it comes from a different package, use qualified name
 	if elemType != nil {
 		pkgPath := elemType.PkgPath()
-		// Infer ExportDataMode by checking if output file contains package path separator
-		isExportMode := strings.Contains(g.OutputFile, "/")
-		if isExportMode &&
+		if g.ExportMode &&
 			pkgPath != "" &&
 			pkgPath != "main" &&
 			pkgPath != g.PackageName {
@@ -110,7 +129,7 @@ func (g *Generator) generateSlice(dataValue reflect.Value) {
 				typeStmt = jen.Id(g.TypeName)
 			} else {
 				// Use package qualification
-				typeStmt = jen.Qual(pkgPath, elemType.Name())
+				typeStmt = g.qual(pkgPath, elemType.Name())
 			}
 		} else {
 			typeStmt = jen.Id(g.TypeName)
@@ -119,6 +138,17 @@ func (g *Generator) generateSlice(dataValue reflect.Value) {
 		typeStmt = jen.Id(g.TypeName)
 	}
 
+	return typeStmt
+}
+
+// generateSlice creates a slice containing all struct instances
+func (g *Generator) generateSlice(dataValue reflect.Value) {
+	// Determine the slice name - handle both regular and irregular plurals
+	sliceName := g.sliceName()
+
+	// Get the type to use (may be from another package)
+	typeStmt := g.elemTypeStatement(dataValue)
+
 	// Generate as pointer slice []*Type with &Var references
 	g.File.Var().Id(
 		sliceName,
@@ -129,10 +159,12 @@ func (g *Generator) generateSlice(dataValue reflect.Value) {
 	).ValuesFunc(func(group *jen.Group) {
 		for i := range dataValue.Len() {
 			elem := dataValue.Index(i)
+			if g.isPruned(elem) {
+				continue
+			}
 
 			// Get the variable name using the same method as in generateVariables
-			identValue := g.getStructIdentifier(elem)
-			varName := g.VarPrefix + slugToIdentifier(identValue)
+			varName := g.varName(elem)
 
 			// Add & operator to create pointer references
 			group.Op("&").Id(varName)
@@ -0,0 +1,76 @@
+package genstruct
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// hasUnexportedFields reports whether a struct type has fields that cannot be
+// set in a composite literal from another package
+func hasUnexportedFields(t reflect.Type) bool {
+	for i := range t.NumField() {
+		if !t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+// isForeignStruct reports whether a struct type is declared in a package
+// other than the data's, so its literal can only set exported fields
+func (g *Generator) isForeignStruct(t reflect.Type) bool {
+	pkgPath := t.PkgPath()
+	dataPkgPath := g.dataPkgPath()
+	return pkgPath != "" && dataPkgPath != "" && pkgPath != dataPkgPath
+}
+
+// valueText returns the text form of a value from its MarshalText or String
+// method, checking pointer receivers as well
+func valueText(value reflect.Value) (string, bool) {
+	ptr := reflect.New(value.Type())
+	ptr.Elem().Set(value)
+	for _, v := range []any{value.Interface(), ptr.Interface()} {
+		if marshaler, ok := v.(encoding.TextMarshaler); ok {
+			text, err := marshaler.MarshalText()
+			if err != nil {
+				return "", false
+			}
+			return string(text), true
+		}
+	}
+	for _, v := range []any{value.Interface(), ptr.Interface()} {
+		if stringer, ok := v.(fmt.Stringer); ok {
+			return stringer.String(), true
+		}
+	}
+	return "", false
+}
+
+// getVendorStatement renders a struct value from another package according to
+// the configured package rendering. It returns nil when the value should be
+// rendered as a regular composite literal.
+func (g *Generator) getVendorStatement(value reflect.Value) *jen.Statement {
+	t := value.Type()
+	if !g.isForeignStruct(t) {
+		return nil
+	}
+
+	if constructor, ok := g.PackageConstructors[t.PkgPath()]; ok {
+		text, ok := valueText(value)
+		if !ok {
+			g.addGenError(VendorTypeError{Type: t.String(), Record: g.currentRecord, Reason: "constructor rendering requires a MarshalText or String method"})
+			return jen.Null()
+		}
+		return g.qual(t.PkgPath(), constructor).Call(jen.Lit(text))
+	}
+
+	// A literal of the exported fields would silently drop the internal state
+	if hasUnexportedFields(t) && !slices.Contains(g.LiteralPackages, t.PkgPath()) {
+		g.addGenError(VendorTypeError{Type: t.String(), Record: g.currentRecord, Reason: "unexported fields cannot be set in a composite literal"})
+	}
+	return nil
+}
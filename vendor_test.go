@@ -0,0 +1,64 @@
+package genstruct
+
+import (
+	"errors"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestPackageRendering tests that struct values with unexported internals from
+// other packages are rendered with a constructor, allowed as literals, or
+// reported
+func TestPackageRendering(t *testing.T) {
+	type Host struct {
+		ID   string
+		Addr netip.Addr
+	}
+	hosts := []Host{{ID: "host-1", Addr: netip.MustParseAddr("10.0.0.1")}}
+
+	dir := t.TempDir()
+	generate := func(opts ...Option) (string, error) {
+		err := NewGenerator(append([]Option{
+			WithPackageName("network"),
+			WithOutputFile("hosts.go"),
+			WithWorkingDir(dir),
+		}, opts...)...).Generate(hosts)
+		if err != nil {
+			return "", err
+		}
+		content, err := os.ReadFile(filepath.Join(dir, "hosts.go"))
+		return string(content), err
+	}
+
+	var vendorErr VendorTypeError
+	if _, err := generate(); !errors.As(err, &vendorErr) {
+		t.Fatalf("Expected VendorTypeError, got %v", err)
+	}
+	if vendorErr.Type != "netip.Addr" || vendorErr.Record != "HostHost1" {
+		t.Errorf("Unexpected error details: %+v", vendorErr)
+	}
+
+	output, err := generate(WithPackageConstructor("net/netip", "MustParseAddr"))
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	if !strings.Contains(output, `Addr: netip.MustParseAddr("10.0.0.1")`) {
+		t.Errorf("Expected constructor expression in output:\n%s", output)
+	}
+
+	output, err = generate(WithPackageLiterals("net/netip"))
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	if !strings.Contains(output, "Addr: netip.Addr{}") {
+		t.Errorf("Expected composite literal in output:\n%s", output)
+	}
+
+	var configErr ConfigError
+	if _, err := generate(WithPackageConstructor("net/netip", "mustParse")); !errors.As(err, &configErr) {
+		t.Errorf("Expected ConfigError for unexported constructor, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package genstruct
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+)
+
+// Manifest describes the declarations a generated file is expected to contain.
+// Empty fields are not checked.
+type Manifest struct {
+	Package   string              // Package name of the file
+	Constants []string            // Names of declared constants
+	Variables []string            // Names of declared variables
+	Types     []string            // Names of declared types
+	Funcs     []string            // Names of declared functions
+	Refs      map[string][]string // Variable names mapped to identifiers their values must reference
+}
+
+// VerifyGeneratedFile parses the Go file at path and checks that it declares
+// everything listed in want. Unlike comparing text, the check doesn't depend on
+// formatting or declaration order.
+//
+// Returns a ManifestError listing every missing declaration or reference.
+func VerifyGeneratedFile(path string, want Manifest) error {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	// Collect the top-level declarations by kind
+	consts := make(map[string]bool)
+	vars := make(map[string]ast.Expr)
+	types := make(map[string]bool)
+	funcs := make(map[string]bool)
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				funcs[decl.Name.Name] = true
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					types[spec.Name.Name] = true
+				case *ast.ValueSpec:
+					for i, name := range spec.Names {
+						if decl.Tok == token.CONST {
+							consts[name.Name] = true
+							continue
+						}
+						var value ast.Expr
+						if i < len(spec.Values) {
+							value = spec.Values[i]
+						}
+						vars[name.Name] = value
+					}
+				}
+			}
+		}
+	}
+
+	var problems []string
+	if want.Package != "" && file.Name.Name != want.Package {
+		problems = append(problems, "package is "+file.Name.Name+", want "+want.Package)
+	}
+	for _, name := range want.Constants {
+		if !consts[name] {
+			problems = append(problems, "missing constant "+name)
+		}
+	}
+	for _, name := range want.Variables {
+		if _, ok := vars[name]; !ok {
+			problems = append(problems, "missing variable "+name)
+		}
+	}
+	for _, name := range want.Types {
+		if !types[name] {
+			problems = append(problems, "missing type "+name)
+		}
+	}
+	for _, name := range want.Funcs {
+		if !funcs[name] {
+			problems = append(problems, "missing function "+name)
+		}
+	}
+
+	// Check references in a stable order
+	varNames := make([]string, 0, len(want.Refs))
+	for name := range want.Refs {
+		varNames = append(varNames, name)
+	}
+	sort.Strings(varNames)
+	for _, name := range varNames {
+		value, ok := vars[name]
+		if !ok {
+			problems = append(problems, "missing variable "+name)
+			continue
+		}
+		used := make(map[string]bool)
+		if value != nil {
+			collectIdents(value, used)
+		}
+		for _, ref := range want.Refs[name] {
+			if !used[ref] {
+				problems = append(problems, "variable "+name+" does not reference "+ref)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return ManifestError{Path: path, Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package genstruct
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// TestVerifyGeneratedFile tests checking a generated file against a manifest
+func TestVerifyGeneratedFile(t *testing.T) {
+	tags := []Tag{{ID: "tag-1", Name: "Go", Slug: "go"}}
+	posts := []Post{{ID: "post-1", Title: "Intro", TagSlugs: []string{"go"}}}
+
+	dir := t.TempDir()
+	generator := NewGenerator(
+		WithPackageName("testdata"),
+		WithOutputFile("posts.go"),
+		WithWorkingDir(dir),
+		WithIdentifierFields([]string{"Slug", "ID"}),
+	)
+	if err := generator.Generate(posts, tags); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+	path := dir + "/posts.go"
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Generated file was not created: %v", err)
+	}
+
+	err := VerifyGeneratedFile(path, Manifest{
+		Package:   "testdata",
+		Constants: []string{"PostPost1ID", "TagGoID"},
+		Variables: []string{"PostPost1", "AllPosts", "TagGo", "AllTags"},
+		Refs:      map[string][]string{"PostPost1": {"TagGo"}},
+	})
+	if err != nil {
+		t.Errorf("Expected file to match manifest, got %v", err)
+	}
+
+	err = VerifyGeneratedFile(path, Manifest{
+		Package:   "blog",
+		Variables: []string{"PostPost2"},
+		Funcs:     []string{"Decrypt"},
+		Refs:      map[string][]string{"AllTags": {"TagRust"}},
+	})
+	var manifestErr ManifestError
+	if !errors.As(err, &manifestErr) {
+		t.Fatalf("Expected ManifestError, got %v", err)
+	}
+	want := []string{
+		"package is testdata, want blog",
+		"missing variable PostPost2",
+		"missing function Decrypt",
+		"variable AllTags does not reference TagRust",
+	}
+	if !reflect.DeepEqual(manifestErr.Problems, want) {
+		t.Errorf("Expected problems %q, got %q", want, manifestErr.Problems)
+	}
+}
@@ -0,0 +1,94 @@
+package genstruct
+
+import (
+	"bytes"
+	"log/slog"
+	"reflect"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// View describes an additional generated package exposing only a subset of
+// the primary dataset's fields, such as a public package without internal notes.
+//
+// The view package declares its own struct type containing only the selected
+// fields, along with constants, variables, and an All-slice for the records.
+// Fields populated through structgen references are not included in views.
+type View struct {
+	OutputFile  string   // Output file path of the view package
+	PackageName string   // Package name, inferred from OutputFile if empty
+	Fields      []string // Names of the fields to include in the view
+}
+
+// generateView writes the view package for the primary dataset
+func (g *Generator) generateView(view View, dataValue reflect.Value) error {
+	structType := dataValue.Index(0).Type()
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	// Configure a copy of the generator for the view package
+	vg := *g
+	vg.OutputFile = view.OutputFile
+	vg.PackageName = view.PackageName
+	if vg.PackageName == "" {
+		vg.PackageName = GetPackageNameFromPath(view.OutputFile)
+	}
+	// A view never lives in the package of the data types
+	vg.ExportMode = true
+	vg.File = jen.NewFile(vg.PackageName)
+	vg.Refs = make(map[string]any)
+	vg.viewType = structType
+	vg.viewFields = make(map[string]bool)
+	for _, name := range view.Fields {
+		vg.viewFields[name] = true
+	}
+
+	if err := vg.validateOutputPath(); err != nil {
+		g.Logger.Error("Invalid view output path", "error", err)
+		return err
+	}
+	if err := vg.writePackageComment(); err != nil {
+		return err
+	}
+
+	// Declare the view type with only the selected fields
+	vg.File.Comment(vg.TypeName + " is a view of " + structType.Name() + " containing a subset of its fields.")
+	vg.File.Type().Id(vg.TypeName).StructFunc(func(group *jen.Group) {
+		for i := range structType.NumField() {
+			field := structType.Field(i)
+			if !vg.isViewField(structType, field) {
+				continue
+			}
+			group.Id(field.Name).Add(vg.getTypeStatement(field.Type))
+		}
+	})
+
+	vg.generateConstants(dataValue)
+	vg.generateVariables(dataValue)
+	vg.generateSlice(dataValue)
+
+	buf := &bytes.Buffer{}
+	if err := vg.File.Render(buf); err != nil {
+		g.Logger.Error("Failed to render view", "error", err)
+		return err
+	}
+
+	g.Logger.Debug(
+		"Writing view to file",
+		slog.String("file", vg.OutputFile),
+	)
+	return vg.writeFile(vg.resolvePath(vg.OutputFile), buf.Bytes())
+}
+
+// isViewField reports whether a field of structType is part of the view being
+// generated. Outside of views every field is included.
+func (g *Generator) isViewField(structType reflect.Type, field reflect.StructField) bool {
+	if g.viewType == nil || structType != g.viewType {
+		return true
+	}
+	if _, hasStructgenTag := field.Tag.Lookup("structgen"); hasStructgenTag {
+		return false
+	}
+	return field.IsExported() && g.viewFields[field.Name]
+}
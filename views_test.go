@@ -0,0 +1,133 @@
+package genstruct
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ViewAnimal is a record with a field of a named type, which the view package
+// has to qualify with the package of the data types
+type ViewAnimal struct {
+	ID     string
+	Name   string
+	Weight Kilograms
+	Notes  string
+}
+
+// TestViews tests that a view package exposes only the selected fields
+func TestViews(t *testing.T) {
+	type Animal struct {
+		ID            string
+		Name          string
+		InternalNotes string
+	}
+	animals := []Animal{
+		{ID: "lion", Name: "Leo", InternalNotes: "bites"},
+	}
+
+	// The view package lives in its own directory next to the zoo package
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "public"), 0755); err != nil {
+		t.Fatalf("Error creating view directory: %v", err)
+	}
+	generator := NewGenerator(
+		WithPackageName("zoo"),
+		WithOutputFile("animals.go"),
+		WithWorkingDir(dir),
+		WithView(View{
+			OutputFile: filepath.Join("public", "animals.go"),
+			Fields:     []string{"ID", "Name"},
+		}),
+	)
+	if err := generator.Generate(animals); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "public", "animals.go"))
+	if err != nil {
+		t.Fatalf("Error reading view file: %v", err)
+	}
+	contentStr := string(content)
+
+	if strings.Contains(contentStr, "InternalNotes") || strings.Contains(contentStr, "bites") {
+		t.Errorf("Expected view to omit InternalNotes:\n%s", contentStr)
+	}
+	expected := []string{
+		"package public",
+		"type Animal struct {",
+		`Name: "Leo"`,
+		"var AllAnimals = []*Animal{&AnimalLion}",
+	}
+	for _, want := range expected {
+		if !strings.Contains(contentStr, want) {
+			t.Errorf("Expected to find %q in view:\n%s", want, contentStr)
+		}
+	}
+}
+
+// TestViewsCompile tests that a view package compiles next to a primary
+// output generated into the package of the data types
+func TestViewsCompile(t *testing.T) {
+	animals := []ViewAnimal{{ID: "lion", Name: "Leo", Weight: 190.5, Notes: "bites"}}
+
+	// The temporary module shares the path of the data types' package
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "view"), 0755); err != nil {
+		t.Fatalf("Error creating view directory: %v", err)
+	}
+	generator := NewGenerator(
+		WithPackageName("genstruct"),
+		WithOutputFile("animals.go"),
+		WithWorkingDir(dir),
+		WithExportMode(false),
+		WithView(View{
+			OutputFile: filepath.Join("view", "animals.go"),
+			Fields:     []string{"ID", "Name", "Weight"},
+		}),
+	)
+	if err := generator.Generate(animals); err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "view", "animals.go"))
+	if err != nil {
+		t.Fatalf("Error reading view file: %v", err)
+	}
+	if !strings.Contains(string(content), "Weight genstruct.Kilograms") {
+		t.Errorf("Expected view to qualify the named field type:\n%s", content)
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+	files := map[string]string{
+		"go.mod": "module github.com/conneroisu/genstruct\n\ngo 1.24\n",
+		"types.go": `package genstruct
+
+type Kilograms float64
+
+type ViewAnimal struct {
+	ID     string
+	Name   string
+	Weight Kilograms
+	Notes  string
+}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Error writing %s: %v", name, err)
+		}
+	}
+
+	cmd := exec.Command(goBin, "vet", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Generated packages failed to compile: %v\n%s", err, output)
+	}
+}
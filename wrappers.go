@@ -0,0 +1,40 @@
+package genstruct
+
+import (
+	"reflect"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// optionWrapperField returns the name of the value field of an option wrapper
+// type such as sql.NullString, sql.Null[T] or uuid.NullUUID: a struct with a
+// Valid bool field and exactly one other exported field holding the value
+func optionWrapperField(t reflect.Type) (string, bool) {
+	if t.Kind() != reflect.Struct || t.NumField() != 2 {
+		return "", false
+	}
+
+	valid, ok := t.FieldByName("Valid")
+	if !ok || valid.Type.Kind() != reflect.Bool || len(valid.Index) != 1 {
+		return "", false
+	}
+	value := t.Field(1 - valid.Index[0])
+	if !value.IsExported() || value.Anonymous {
+		return "", false
+	}
+	return value.Name, true
+}
+
+// getOptionWrapperStatement renders an option wrapper compactly, leaving out
+// its zero fields: a null value as an empty literal and a valid one as its
+// value with Valid set
+func (g *Generator) getOptionWrapperStatement(value reflect.Value, fieldName string) *jen.Statement {
+	fields := jen.Dict{}
+	if field := value.FieldByName(fieldName); !field.IsZero() {
+		fields[jen.Id(fieldName)] = g.getValueStatement(field)
+	}
+	if value.FieldByName("Valid").Bool() {
+		fields[jen.Id("Valid")] = jen.True()
+	}
+	return g.getTypeStatement(value.Type()).Values(fields)
+}
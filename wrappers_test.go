@@ -0,0 +1,89 @@
+package genstruct
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestOptionWrappers tests that sql.Null* style wrappers are rendered
+// compactly and qualified with their package outside export mode
+func TestOptionWrappers(t *testing.T) {
+	type Account struct {
+		ID        string
+		Nickname  sql.NullString
+		Deleted   sql.NullTime
+		Score     sql.Null[int64]
+		Referrers []sql.NullInt32
+		Legacy    sql.NullString
+	}
+	accounts := []Account{
+		{
+			ID:        "account-1",
+			Nickname:  sql.NullString{String: "ace", Valid: true},
+			Score:     sql.Null[int64]{V: 42, Valid: true},
+			Referrers: []sql.NullInt32{{Int32: 7, Valid: true}, {}},
+		},
+		{
+			ID:      "account-2",
+			Deleted: sql.NullTime{Time: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Valid: true},
+			Legacy:  sql.NullString{String: "old"},
+		},
+	}
+
+	dir := t.TempDir()
+	err := NewGenerator(
+		WithPackageName("accounts"),
+		WithOutputFile("accounts.go"),
+		WithWorkingDir(dir),
+	).Generate(accounts)
+	if err != nil {
+		t.Fatalf("Error generating code: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "accounts.go"))
+	if err != nil {
+		t.Fatalf("Error reading output: %v", err)
+	}
+	output := string(content)
+
+	expected := []string{
+		`"database/sql"`,
+		`Nickname: sql.NullString{`,
+		`String: "ace",`,
+		`Deleted: sql.NullTime{},`,
+		`Score: sql.Null[int64]{`,
+		`V:     int64(42),`,
+		`Nickname:  sql.NullString{},`,
+		`sql.NullInt32{}`,
+		`Time:  time.Date(2024`,
+		`sql.NullString{String: "old"},`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q", exp)
+		}
+	}
+	if strings.Contains(output, "Valid: false") {
+		t.Error("Null values should be rendered as empty literals")
+	}
+}
+
+// TestOptionWrapperField tests the detection of option wrapper types
+func TestOptionWrapperField(t *testing.T) {
+	type NotWrapper struct {
+		Name  string
+		Valid bool
+		Extra int
+	}
+	if field, ok := optionWrapperField(reflect.TypeOf(sql.NullString{})); !ok || field != "String" {
+		t.Errorf("Expected sql.NullString value field String, got %q", field)
+	}
+	if _, ok := optionWrapperField(reflect.TypeOf(NotWrapper{})); ok {
+		t.Error("Structs with more than a value and Valid field are not wrappers")
+	}
+}